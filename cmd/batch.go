@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SelectBatch returns a copy of the config limited to the next size modules in
+// alphabetical order, starting where the previous run stopped, and advances
+// the state. Batches never wrap, so successive runs cover every module once
+// before starting over.
+func (c *ModuleConfig) SelectBatch(size int, state *State) *ModuleConfig {
+	modules := slices.Sorted(maps.Keys(c.Modules))
+
+	start := state.NextBatch
+	if start >= len(modules) {
+		start = 0
+	}
+	end := min(start+size, len(modules))
+
+	state.NextBatch = end
+	if end == len(modules) {
+		state.NextBatch = 0
+	}
+
+	return c.withModules(modules[start:end])
+}
+
+// SelectModules returns a copy of the config limited to the named modules.
+// Names are matched case-insensitively; an unknown name is an error listing
+// the configured modules.
+func (c *ModuleConfig) SelectModules(names []string) (*ModuleConfig, error) {
+	modules := slices.Sorted(maps.Keys(c.Modules))
+
+	var selected []string
+	for _, name := range names {
+		i := slices.IndexFunc(modules, func(module string) bool {
+			return strings.EqualFold(module, name)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("unknown module %q, must be one of: %s", name, strings.Join(modules, ", "))
+		}
+		if !slices.Contains(selected, modules[i]) {
+			selected = append(selected, modules[i])
+		}
+	}
+
+	return c.withModules(selected), nil
+}
+
+// withModules returns a copy of the config containing only the given modules.
+// Dependencies on modules outside the selection are dropped.
+func (c *ModuleConfig) withModules(names []string) *ModuleConfig {
+	selected := *c
+	selected.Modules = make(map[string]string, len(names))
+	for _, name := range names {
+		selected.Modules[name] = c.Modules[name]
+	}
+
+	if c.DependsOn != nil {
+		selected.DependsOn = map[string][]string{}
+		for module, deps := range c.DependsOn {
+			if _, ok := selected.Modules[module]; !ok {
+				continue
+			}
+			for _, dep := range deps {
+				if _, ok := selected.Modules[dep]; ok {
+					selected.DependsOn[module] = append(selected.DependsOn[module], dep)
+				}
+			}
+		}
+	}
+
+	return &selected
+}
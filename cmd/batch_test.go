@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestModuleConfig_SelectBatchCoversAllModules(t *testing.T) {
+	config := NewModuleConfig()
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	var batches [][]string
+	for range 4 {
+		state, err := LoadState(path)
+		if err != nil {
+			t.Fatalf("LoadState() error = %v", err)
+		}
+
+		batch := config.SelectBatch(2, state)
+		batches = append(batches, slices.Sorted(maps.Keys(batch.Modules)))
+
+		if err := state.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	want := [][]string{
+		{"Brands", "Classes"},
+		{"Customers", "Home"},
+		{"Vivapay"},
+		{"Brands", "Classes"},
+	}
+	for i := range want {
+		if !slices.Equal(batches[i], want[i]) {
+			t.Errorf("run %d synced %v, want %v", i+1, batches[i], want[i])
+		}
+	}
+
+	if len(config.Modules) != 5 {
+		t.Error("SelectBatch() should not modify the original config")
+	}
+}
+
+func TestModuleConfig_SelectBatchDropsOutsideDependencies(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:   map[string]string{"Brands": "", "Customers": "", "Home": ""},
+		DependsOn: map[string][]string{"Customers": {"Brands"}, "Home": {"Customers"}},
+	}
+
+	batch := config.SelectBatch(2, &State{NextBatch: 1})
+
+	if _, err := batch.dependencyOrder(); err != nil {
+		t.Errorf("dependencyOrder() error = %v, want dependencies outside the batch dropped", err)
+	}
+	if !slices.Equal(batch.DependsOn["Home"], []string{"Customers"}) || len(batch.DependsOn["Customers"]) != 0 {
+		t.Errorf("DependsOn = %v", batch.DependsOn)
+	}
+}
+
+func TestModuleConfig_SelectModules(t *testing.T) {
+	config := NewModuleConfig()
+
+	selected, err := config.SelectModules([]string{"customers", "Home", "CUSTOMERS"})
+	if err != nil {
+		t.Fatalf("SelectModules() error = %v", err)
+	}
+
+	want := map[string]string{"Customers": "Customers Module API", "Home": "Home Module API"}
+	if !maps.Equal(selected.Modules, want) {
+		t.Errorf("SelectModules() = %v, want %v", selected.Modules, want)
+	}
+	if len(config.Modules) != 5 {
+		t.Error("SelectModules() should not modify the original config")
+	}
+}
+
+func TestModuleConfig_SelectModulesUnknown(t *testing.T) {
+	_, err := NewModuleConfig().SelectModules([]string{"Customers", "Members"})
+	want := `unknown module "Members", must be one of: Brands, Classes, Customers, Home, Vivapay`
+	if err == nil || err.Error() != want {
+		t.Errorf("SelectModules() error = %v, want %q", err, want)
+	}
+}
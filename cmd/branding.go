@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Branding is the metadata added to the description of every collection the
+// tool creates or updates, so all generated collections look alike.
+type Branding struct {
+	// Header is markdown placed above the spec's own description.
+	Header string `yaml:"header"`
+	// Links are listed below the description.
+	Links []BrandingLink `yaml:"links"`
+}
+
+// BrandingLink is a named link listed in branded collection descriptions.
+type BrandingLink struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// LoadBranding reads the branding from a YAML or JSON file, e.g.
+//
+//	header: Generated from the internal docs, do not edit by hand.
+//	links:
+//	  - name: Runbook
+//	    url: https://wiki.internal/api-runbook
+func LoadBranding(path string) (*Branding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading branding file: %w", err)
+	}
+
+	var branding Branding
+	if err := yaml.Unmarshal(data, &branding); err != nil {
+		return nil, fmt.Errorf("parsing branding file %s: %w", path, err)
+	}
+
+	if strings.TrimSpace(branding.Header) == "" && len(branding.Links) == 0 {
+		return nil, fmt.Errorf("branding file %s sets neither a header nor links", path)
+	}
+	var errs []error
+	for i, link := range branding.Links {
+		if link.Name == "" || link.URL == "" {
+			errs = append(errs, fmt.Errorf("branding link %d needs both a name and a url", i+1))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return &branding, nil
+}
+
+// WithBranding makes the client add the branding to the description of every
+// collection it imports or updates in place.
+func WithBranding(branding *Branding) ClientOption {
+	return func(c *APIClient) {
+		c.branding = branding
+	}
+}
+
+// describe returns the collection description made of the header, the
+// spec's description and the links, separated by blank lines.
+func (b *Branding) describe(description string) string {
+	var parts []string
+	if header := strings.TrimSpace(b.Header); header != "" {
+		parts = append(parts, header)
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		parts = append(parts, description)
+	}
+	if len(b.Links) > 0 {
+		links := make([]string, len(b.Links))
+		for i, link := range b.Links {
+			links[i] = fmt.Sprintf("- [%s](%s)", link.Name, link.URL)
+		}
+		parts = append(parts, strings.Join(links, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// specDescription returns info.description of a JSON spec.
+func specDescription(doc string) (string, error) {
+	var spec struct {
+		Info struct {
+			Description string `json:"description"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+	return spec.Info.Description, nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testBranding = &Branding{
+	Header: "Generated from the internal docs.",
+	Links:  []BrandingLink{{Name: "Runbook", URL: "https://wiki.internal/runbook"}},
+}
+
+func TestBranding_describe(t *testing.T) {
+	want := "Generated from the internal docs.\n\nCustomer API.\n\n- [Runbook](https://wiki.internal/runbook)"
+	if got := testBranding.describe("Customer API."); got != want {
+		t.Errorf("describe() = %q, want %q", got, want)
+	}
+
+	if got := (&Branding{Header: "Header"}).describe(""); got != "Header" {
+		t.Errorf("describe() = %q, want only the header", got)
+	}
+}
+
+func TestAPIClient_ImportModuleBranding(t *testing.T) {
+	var patched map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/import/openapi":
+			w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Customers Module API"}]}`))
+		case r.Method == "PATCH" && r.URL.Path == "/collections/1-new":
+			json.NewDecoder(r.Body).Decode(&patched)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithBranding(testBranding))
+	client.postmanBaseURL = server.URL
+
+	err := client.ImportModule(t.Context(), &PreparedModule{
+		ModuleName:     "Customers",
+		CollectionName: "Customers Module API",
+		WorkspaceID:    "workspace",
+		Doc:            `{"openapi":"3.0.0","info":{"description":"Customer API."},"paths":{"/customers":{"get":{}}}}`,
+	})
+	if err != nil {
+		t.Fatalf("ImportModule() error = %v", err)
+	}
+
+	info, _ := patched["collection"].(map[string]any)["info"].(map[string]any)
+	if info["name"] != "Customers Module API" {
+		t.Errorf("patched name = %v, want Customers Module API", info["name"])
+	}
+	if want := testBranding.describe("Customer API."); info["description"] != want {
+		t.Errorf("patched description = %q, want %q", info["description"], want)
+	}
+}
+
+func TestLoadBranding(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	branding, err := LoadBranding(write("branding.yaml", "header: Generated.\nlinks:\n  - name: Runbook\n    url: https://wiki.internal/runbook\n"))
+	if err != nil {
+		t.Fatalf("LoadBranding() error = %v", err)
+	}
+	if branding.Header != "Generated." || len(branding.Links) != 1 || branding.Links[0].URL != "https://wiki.internal/runbook" {
+		t.Errorf("LoadBranding() = %+v", branding)
+	}
+
+	for name, content := range map[string]string{
+		"empty.yaml":   "{}",
+		"nourl.yaml":   "links:\n  - name: Runbook\n",
+		"invalid.yaml": "header: [",
+	} {
+		if _, err := LoadBranding(write(name, content)); err == nil {
+			t.Errorf("LoadBranding(%s) error = nil, want an error", name)
+		}
+	}
+}
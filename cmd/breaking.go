@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrBreakingChange is returned for a module whose spec breaks its clients
+// compared with the spec it was last synced from, when such changes are
+// blocked.
+var ErrBreakingChange = errors.New("breaking API change")
+
+// SpecSurface is the part of a spec its clients depend on. It is recorded in
+// the state so the next run can tell what changed.
+type SpecSurface struct {
+	// Endpoints lists every operation as "GET /path".
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Fields maps every "Schema.property" of the spec's schemas to its
+	// type.
+	Fields map[string]SpecField `json:"fields,omitempty"`
+}
+
+// SpecField is a schema property of a SpecSurface.
+type SpecField struct {
+	Type     string `json:"type"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// APIChange is a difference between two specs of a module.
+type APIChange struct {
+	Breaking    bool
+	Description string
+}
+
+// WithBreakingChanges makes the client fail a module whose spec has a
+// breaking change since it was last synced when block is set, unless allow
+// is set too. Changes are only reported otherwise.
+func WithBreakingChanges(block, allow bool) ClientOption {
+	return func(c *APIClient) {
+		c.blockBreaking = block
+		c.allowBreaking = allow
+	}
+}
+
+// computeSpecSurface extracts the endpoints and schema properties of a JSON
+// spec, from components.schemas or from definitions in a Swagger 2 spec.
+func computeSpecSurface(doc string) (SpecSurface, error) {
+	var spec struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]surfaceSchema `json:"schemas"`
+		} `json:"components"`
+		Definitions map[string]surfaceSchema `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return SpecSurface{}, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	var surface SpecSurface
+	for path, item := range spec.Paths {
+		for _, method := range httpMethods {
+			if _, ok := item[method]; ok {
+				surface.Endpoints = append(surface.Endpoints, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	slices.Sort(surface.Endpoints)
+
+	schemas := maps.Clone(spec.Definitions)
+	if schemas == nil {
+		schemas = map[string]surfaceSchema{}
+	}
+	maps.Copy(schemas, spec.Components.Schemas)
+	for name, schema := range schemas {
+		for property, raw := range schema.Properties {
+			if surface.Fields == nil {
+				surface.Fields = map[string]SpecField{}
+			}
+			surface.Fields[name+"."+property] = SpecField{
+				Type:     propertyType(raw),
+				Required: slices.Contains(schema.Required, property),
+			}
+		}
+	}
+
+	return surface, nil
+}
+
+type surfaceSchema struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+}
+
+// propertyType describes the type of a schema property: its type, with the
+// item type for arrays, or the schema it references.
+func propertyType(raw json.RawMessage) string {
+	var property struct {
+		Ref   string          `json:"$ref"`
+		Type  json.RawMessage `json:"type"`
+		Items json.RawMessage `json:"items"`
+	}
+	if json.Unmarshal(raw, &property) != nil {
+		return ""
+	}
+	if property.Ref != "" {
+		return property.Ref
+	}
+
+	var typ string
+	if json.Unmarshal(property.Type, &typ) != nil {
+		// OpenAPI 3.1 allows a list of types.
+		typ = string(property.Type)
+	}
+	if typ == "array" && property.Items != nil {
+		return "array of " + propertyType(property.Items)
+	}
+	return typ
+}
+
+// diffSpecSurfaces lists the changes from old to current, breaking ones first.
+// Removed endpoints, removed required properties and changed property types
+// break clients; added endpoints and properties, and removed optional
+// properties, do not.
+func diffSpecSurfaces(old, current SpecSurface) []APIChange {
+	var breaking, compatible []APIChange
+
+	for _, endpoint := range old.Endpoints {
+		if !slices.Contains(current.Endpoints, endpoint) {
+			breaking = append(breaking, APIChange{true, "removed endpoint " + endpoint})
+		}
+	}
+	for _, endpoint := range current.Endpoints {
+		if !slices.Contains(old.Endpoints, endpoint) {
+			compatible = append(compatible, APIChange{false, "added endpoint " + endpoint})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(old.Fields)) {
+		was := old.Fields[name]
+		is, ok := current.Fields[name]
+		switch {
+		case !ok && was.Required:
+			breaking = append(breaking, APIChange{true, "removed required field " + name})
+		case !ok:
+			compatible = append(compatible, APIChange{false, "removed optional field " + name})
+		case is.Type != was.Type:
+			breaking = append(breaking, APIChange{true, fmt.Sprintf("changed type of field %s from %s to %s", name, was.Type, is.Type)})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(current.Fields)) {
+		if _, ok := old.Fields[name]; !ok {
+			compatible = append(compatible, APIChange{false, "added field " + name})
+		}
+	}
+
+	return append(breaking, compatible...)
+}
+
+// checkBreakingChanges compares the module's spec with the surface recorded
+// when it was last synced and reports every change. It fails on a breaking
+// change when they are blocked and not allowed. Without a state there is
+// nothing to compare with.
+func (c *APIClient) checkBreakingChanges(ctx context.Context, module string, surface SpecSurface) error {
+	if c.state == nil {
+		return nil
+	}
+	previous, ok := c.state.Surface(module)
+	if !ok {
+		return nil
+	}
+
+	var breaking []string
+	for _, change := range diffSpecSurfaces(previous, surface) {
+		if !change.Breaking {
+			c.log.InfoContext(ctx, "API change", "change", change.Description)
+			continue
+		}
+		c.log.WarnContext(ctx, "breaking API change", "change", change.Description)
+		breaking = append(breaking, change.Description)
+	}
+
+	if len(breaking) == 0 || !c.blockBreaking {
+		return nil
+	}
+	if c.allowBreaking {
+		c.log.WarnContext(ctx, "breaking API changes allowed", "count", len(breaking))
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrBreakingChange, strings.Join(breaking, "; "))
+}
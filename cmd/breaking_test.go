@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const breakingBaseSpec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Customers", "version": "1.0.0"},
+  "paths": {
+    "/customers": {"get": {"responses": {"200": {"description": "OK"}}}},
+    "/customers/{id}": {"get": {"responses": {"200": {"description": "OK"}}}}
+  },
+  "components": {"schemas": {"Customer": {
+    "required": ["id"],
+    "properties": {"id": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}
+  }}}
+}`
+
+// breakingServers serves the current value of *spec as the doc and accepts
+// every Postman request.
+func breakingServers(t *testing.T, spec *string) (docURL, postmanURL string) {
+	t.Helper()
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(*spec))
+	}))
+	t.Cleanup(docServer.Close)
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"collections":[]}`))
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(postman.Close)
+
+	return docServer.URL, postman.URL
+}
+
+func TestAPIClient_BreakingChangesBetweenRuns(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		allow        bool
+		wantBreaking bool
+	}{
+		{
+			name:         "removed endpoint",
+			spec:         strings.Replace(breakingBaseSpec, `"/customers/{id}": {"get": {"responses": {"200": {"description": "OK"}}}}`, `"/customers/{id}": {}`, 1),
+			wantBreaking: true,
+		},
+		{
+			name:  "removed endpoint allowed",
+			spec:  strings.Replace(breakingBaseSpec, `"/customers/{id}": {"get": {"responses": {"200": {"description": "OK"}}}}`, `"/customers/{id}": {}`, 1),
+			allow: true,
+		},
+		{
+			name: "added endpoint",
+			spec: strings.Replace(breakingBaseSpec, `"/customers": {"get"`, `"/customers": {"post": {"responses": {"201": {"description": "Created"}}}, "get"`, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := breakingBaseSpec
+			docURL, postmanURL := breakingServers(t, &spec)
+
+			state := &State{}
+			client := NewAPIClient("doc-key", "pm-key", WithState(state), WithBreakingChanges(true, tt.allow), WithOutput(io.Discard))
+			client.postmanBaseURL = postmanURL
+			client.docURL = func(string) string { return docURL }
+
+			if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+				t.Fatalf("first ProcessModule() error = %v", err)
+			}
+			if _, ok := state.Surface("Customers"); !ok {
+				t.Fatal("no surface recorded after the sync")
+			}
+
+			spec = tt.spec
+			err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+			if tt.wantBreaking {
+				if !errors.Is(err, ErrBreakingChange) || !strings.Contains(err.Error(), "removed endpoint GET /customers/{id}") {
+					t.Errorf("ProcessModule() error = %v, want the removed endpoint blocked", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ProcessModule() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDiffSpecSurfaces(t *testing.T) {
+	old := SpecSurface{
+		Endpoints: []string{"GET /customers", "GET /customers/{id}"},
+		Fields: map[string]SpecField{
+			"Customer.id":   {Type: "string", Required: true},
+			"Customer.name": {Type: "string", Required: true},
+			"Customer.note": {Type: "string"},
+			"Customer.tags": {Type: "array of string"},
+		},
+	}
+	current := SpecSurface{
+		Endpoints: []string{"GET /customers", "POST /customers"},
+		Fields: map[string]SpecField{
+			"Customer.id":    {Type: "integer", Required: true},
+			"Customer.tags":  {Type: "array of string"},
+			"Customer.email": {Type: "string"},
+		},
+	}
+
+	var got []string
+	for _, change := range diffSpecSurfaces(old, current) {
+		prefix := "compatible: "
+		if change.Breaking {
+			prefix = "breaking: "
+		}
+		got = append(got, prefix+change.Description)
+	}
+
+	want := []string{
+		"breaking: removed endpoint GET /customers/{id}",
+		"breaking: changed type of field Customer.id from string to integer",
+		"breaking: removed required field Customer.name",
+		"compatible: added endpoint POST /customers",
+		"compatible: removed optional field Customer.note",
+		"compatible: added field Customer.email",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diffSpecSurfaces() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestComputeSpecSurface(t *testing.T) {
+	surface, err := computeSpecSurface(breakingBaseSpec)
+	if err != nil {
+		t.Fatalf("computeSpecSurface() error = %v", err)
+	}
+
+	if want := []string{"GET /customers", "GET /customers/{id}"}; !slices.Equal(surface.Endpoints, want) {
+		t.Errorf("Endpoints = %v, want %v", surface.Endpoints, want)
+	}
+	if got := surface.Fields["Customer.id"]; got != (SpecField{Type: "string", Required: true}) {
+		t.Errorf("Customer.id = %+v", got)
+	}
+	if got := surface.Fields["Customer.tags"]; got != (SpecField{Type: "array of string"}) {
+		t.Errorf("Customer.tags = %+v", got)
+	}
+}
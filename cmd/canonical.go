@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithCanonicalSpecs makes the client canonicalize every spec before it is
+// used, so the same upstream content always yields the same bytes.
+func WithCanonicalSpecs(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.canonical = enabled
+	}
+}
+
+// canonicalizeSpec rewrites a spec in a byte-stable form: object keys sorted,
+// arrays whose order carries no meaning in OpenAPI sorted, two-space indent,
+// no HTML escaping and a trailing newline.
+func canonicalizeSpec(doc string) (string, error) {
+	var spec any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	sortSets(spec)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(spec); err != nil {
+		return "", fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// sortSets sorts, in place, the arrays under keys that OpenAPI treats as sets:
+// required property names, tag lists and parameter lists.
+func sortSets(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			sortSets(child)
+
+			items, ok := child.([]any)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "required", "tags":
+				slices.SortStableFunc(items, func(a, b any) int {
+					return strings.Compare(setKey(a, "name"), setKey(b, "name"))
+				})
+			case "parameters":
+				slices.SortStableFunc(items, func(a, b any) int {
+					return strings.Compare(setKey(a, "in")+"\x00"+setKey(a, "name"), setKey(b, "in")+"\x00"+setKey(b, "name"))
+				})
+			}
+		}
+	case []any:
+		for _, child := range v {
+			sortSets(child)
+		}
+	}
+}
+
+// setKey returns the sort key of a set element: the element itself when it is
+// a string, otherwise the string at field, or the $ref of a referenced element.
+func setKey(item any, field string) string {
+	switch v := item.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if s, ok := v[field].(string); ok {
+			return s
+		}
+		ref, _ := v["$ref"].(string)
+		return ref
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCanonicalizeSpec_ByteStable(t *testing.T) {
+	inputs := []string{
+		`{"openapi":"3.0.0","info":{"title":"A & B","version":"1"},
+		  "tags":[{"name":"orders"},{"name":"customers"}],
+		  "paths":{"/c":{"get":{"tags":["b","a"],
+		    "parameters":[{"in":"query","name":"z"},{"in":"path","name":"id"},{"in":"query","name":"a"}]}}},
+		  "components":{"schemas":{"C":{"type":"object","required":["name","id"]}}}}`,
+		`{"components":{"schemas":{"C":{"required":["id","name"],"type":"object"}}},
+		  "paths":{"/c":{"get":{
+		    "parameters":[{"name":"a","in":"query"},{"name":"z","in":"query"},{"name":"id","in":"path"}],"tags":["a","b"]}}},
+		  "tags":[{"name":"customers"},{"name":"orders"}],
+		  "info":{"version":"1","title":"A & B"},"openapi":"3.0.0"}`,
+	}
+
+	dir := t.TempDir()
+	var files []string
+	for run := range 3 {
+		for i, input := range inputs {
+			doc, err := canonicalizeSpec(input)
+			if err != nil {
+				t.Fatalf("canonicalizeSpec() error = %v", err)
+			}
+			path := filepath.Join(dir, fmt.Sprintf("run%d-input%d.json", run, i))
+			if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, path)
+		}
+	}
+
+	want, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range files[1:] {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs from %s:\n%s\nwant:\n%s", path, files[0], got, want)
+		}
+	}
+}
+
+func TestCanonicalizeSpec_Format(t *testing.T) {
+	doc, err := canonicalizeSpec(`{"b":["<x>"],"a":{"required":["y","x"]}}`)
+	if err != nil {
+		t.Fatalf("canonicalizeSpec() error = %v", err)
+	}
+
+	want := `{
+  "a": {
+    "required": [
+      "x",
+      "y"
+    ]
+  },
+  "b": [
+    "<x>"
+  ]
+}
+`
+	if doc != want {
+		t.Errorf("canonicalizeSpec() = %q, want %q", doc, want)
+	}
+}
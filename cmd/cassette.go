@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never written to a cassette.
+var sensitiveHeaders = []string{"X-API-Key", "Authorization"}
+
+// Interaction is one recorded HTTP request and its response.
+type Interaction struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"requestHeaders"`
+	RequestBody     string      `json:"requestBody,omitempty"`
+	Status          int         `json:"status"`
+	ResponseHeaders http.Header `json:"responseHeaders"`
+	ResponseBody    string      `json:"responseBody"`
+}
+
+// Cassette records the HTTP interactions of a run, or replays recorded ones
+// instead of touching the network, so a failing run can be reproduced.
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	replay       bool
+	Interactions []Interaction `json:"interactions"`
+	next         map[string]int
+}
+
+// NewRecorder returns a cassette that records interactions to path on Save.
+func NewRecorder(path string) *Cassette {
+	return &Cassette{path: path}
+}
+
+// LoadCassette reads a recorded cassette for replay.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+
+	cassette := &Cassette{path: path, replay: true, next: map[string]int{}}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("parsing cassette: %w", err)
+	}
+
+	return cassette, nil
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling cassette: %w", err)
+	}
+
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// Transport wraps base so requests are recorded, or answered from the
+// cassette when replaying.
+func (c *Cassette) Transport(base http.RoundTripper) http.RoundTripper {
+	return cassetteTransport{cassette: c, base: base}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	base     http.RoundTripper
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t cassetteTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (t cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cassette.replay {
+		return t.cassette.replayResponse(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.cassette.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		RequestHeaders:  redactHeaders(req.Header),
+		RequestBody:     string(reqBody),
+		Status:          resp.StatusCode,
+		ResponseHeaders: resp.Header.Clone(),
+		ResponseBody:    string(respBody),
+	})
+	t.cassette.mu.Unlock()
+
+	return resp, nil
+}
+
+// replayResponse answers a request with the next recorded interaction for the
+// same method and URL.
+func (c *Cassette) replayResponse(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := req.Method + " " + req.URL.String()
+	seen := 0
+	for _, interaction := range c.Interactions {
+		if interaction.Method+" "+interaction.URL != key {
+			continue
+		}
+		if seen == c.next[key] {
+			c.next[key]++
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+				StatusCode:    interaction.Status,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        interaction.ResponseHeaders.Clone(),
+				Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+				ContentLength: int64(len(interaction.ResponseBody)),
+				Request:       req,
+			}, nil
+		}
+		seen++
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s", key)
+}
+
+func redactHeaders(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clone.Get(name) != "" {
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
+
+// WithCassette routes every request of the client through the cassette.
+func WithCassette(cassette *Cassette) ClientOption {
+	return func(c *APIClient) {
+		c.cassette = cassette
+		c.httpClient.Transport = cassette.Transport(c.httpClient.Transport)
+	}
+}
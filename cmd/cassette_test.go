@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// newMockAPIs starts a doc API and a Postman API serving one module with one
+// existing collection.
+func newMockAPIs(t *testing.T) (docServer, postman *httptest.Server) {
+	t.Helper()
+
+	docServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{}}}}`))
+	}))
+	postman = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+		case "DELETE":
+			w.Write([]byte(`{"collection":{"id":"c1"}}`))
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2","name":"Customers Module API"}]}`))
+		}
+	}))
+
+	t.Cleanup(docServer.Close)
+	t.Cleanup(postman.Close)
+	return docServer, postman
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	docServer, postman := newMockAPIs(t)
+	path := filepath.Join(t.TempDir(), "run.json")
+
+	run := func(cassette *Cassette) (string, error) {
+		var out strings.Builder
+		client := NewAPIClient("secret-doc-key", "secret-pm-key", WithCassette(cassette), WithOutput(&out))
+		client.postmanBaseURL = postman.URL
+		client.docURL = func(string) string { return docServer.URL }
+
+		err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+		// Timestamps differ between the runs.
+		return regexp.MustCompile(`time=\S+ `).ReplaceAllString(out.String(), ""), err
+	}
+
+	recorder := NewRecorder(path)
+	recorded, err := run(recorder)
+	if err != nil {
+		t.Fatalf("recorded run error = %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret-") {
+		t.Errorf("cassette should not contain API keys:\n%s", data)
+	}
+	if len(recorder.Interactions) != 4 {
+		t.Errorf("recorded %d interactions, want fetch, list, delete and import", len(recorder.Interactions))
+	}
+
+	docServer.Close()
+	postman.Close()
+
+	replayer, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+	replayed, err := run(replayer)
+	if err != nil {
+		t.Fatalf("replayed run error = %v", err)
+	}
+
+	if replayed != recorded {
+		t.Errorf("replayed output differs from recorded output\nrecorded:\n%s\nreplayed:\n%s", recorded, replayed)
+	}
+}
+
+func TestCassette_ReplayUnknownRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	os.WriteFile(path, []byte(`{"interactions":[]}`), 0o600)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+
+	client := NewAPIClient("doc-key", "pm-key", WithCassette(cassette))
+	_, err = client.fetchDoc(t.Context(), "https://docs.example.com/v1/internal-docs")
+	if err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("fetchDoc() error = %v, want missing recording error", err)
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"text/tabwriter"
+)
+
+// CheckResult records whether an API accepted the configured credential.
+type CheckResult struct {
+	API    string
+	OK     bool
+	Status int
+	// Detail names the Postman user, or tells why the check failed.
+	Detail string
+}
+
+// CheckCredentials sends one cheap authenticated request to every doc host
+// of the configured modules and to Postman's /me, and reports which of them
+// accepted the keys. Nothing is synced.
+func (c *APIClient) CheckCredentials(ctx context.Context, config *ModuleConfig) []CheckResult {
+	var results []CheckResult
+
+	checked := map[string]bool{}
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		docURL, err := c.moduleDocURL(module)
+		if err != nil {
+			results = append(results, CheckResult{API: module + " doc API", Detail: err.Error()})
+			continue
+		}
+
+		host := docURL
+		if u, err := url.Parse(docURL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if checked[host] {
+			continue
+		}
+		checked[host] = true
+
+		results = append(results, c.checkDocAPI(ctx, host, docURL))
+	}
+
+	return append(results, c.checkPostman(ctx))
+}
+
+// checkDocAPI requests the headers of a doc. The doc must exist, but a host
+// that does not allow HEAD still proves the key was accepted.
+func (c *APIClient) checkDocAPI(ctx context.Context, host, docURL string) CheckResult {
+	result := CheckResult{API: "doc API " + host}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", docURL, nil)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	c.setDocHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.OK = resp.StatusCode < http.StatusBadRequest || resp.StatusCode == http.StatusMethodNotAllowed
+	if !result.OK {
+		result.Detail = checkFailure(resp.StatusCode)
+	}
+	return result
+}
+
+// checkPostman asks Postman which user the API key belongs to.
+func (c *APIClient) checkPostman(ctx context.Context) CheckResult {
+	result := CheckResult{API: "Postman API"}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.postmanBaseURL+"/me", nil)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		result.Detail = checkFailure(resp.StatusCode)
+		return result
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var me struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(body, &me); err != nil {
+		result.Detail = fmt.Sprintf("unexpected response: %s", string(body))
+		return result
+	}
+
+	result.OK = true
+	if me.User.Username != "" {
+		result.Detail = "user " + me.User.Username
+	}
+	return result
+}
+
+func checkFailure(status int) string {
+	if keyRejected(status) {
+		return "key rejected"
+	}
+	return http.StatusText(status)
+}
+
+// WriteCheckResults writes the credential check results as a table.
+func WriteCheckResults(w io.Writer, results []CheckResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "API\tRESULT\tSTATUS\tDETAIL")
+
+	for _, r := range results {
+		state := "FAIL"
+		if r.OK {
+			state = "OK"
+		}
+
+		status := "-"
+		if r.Status != 0 {
+			status = fmt.Sprint(r.Status)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.API, state, status, r.Detail)
+	}
+
+	return tw.Flush()
+}
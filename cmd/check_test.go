@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_CheckCredentials(t *testing.T) {
+	docs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("doc check method = %s, want HEAD", r.Method)
+		}
+		if r.Header.Get("X-API-Key") != "doc-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer docs.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-API-Key") != "pm-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"user":{"id":1,"username":"ci-bot"}}`))
+	}))
+	defer postman.Close()
+
+	config := &ModuleConfig{Modules: map[string]string{
+		"Brands":    "Brands Module API",
+		"Customers": "Customers Module API",
+	}}
+
+	tests := []struct {
+		name   string
+		docKey string
+		pmKey  string
+		want   []CheckResult
+	}{
+		{
+			name:   "valid keys",
+			docKey: "doc-key",
+			pmKey:  "pm-key",
+			want: []CheckResult{
+				{API: "doc API " + strings.TrimPrefix(docs.URL, "http://"), OK: true, Status: http.StatusOK},
+				{API: "Postman API", OK: true, Status: http.StatusOK, Detail: "user ci-bot"},
+			},
+		},
+		{
+			name:   "rejected keys",
+			docKey: "wrong",
+			pmKey:  "wrong",
+			want: []CheckResult{
+				{API: "doc API " + strings.TrimPrefix(docs.URL, "http://"), Status: http.StatusUnauthorized, Detail: "key rejected"},
+				{API: "Postman API", Status: http.StatusUnauthorized, Detail: "key rejected"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewAPIClient(tt.docKey, tt.pmKey, WithPostmanBaseURL(postman.URL), WithRetry(0, 0))
+			// Both modules share the doc host, which is checked once.
+			client.docURL = func(module string) string { return docs.URL + "/" + module }
+
+			results := client.CheckCredentials(t.Context(), config)
+			if len(results) != len(tt.want) {
+				t.Fatalf("CheckCredentials() = %+v, want %+v", results, tt.want)
+			}
+			for i, want := range tt.want {
+				if results[i] != want {
+					t.Errorf("result %d = %+v, want %+v", i, results[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCheckResults(t *testing.T) {
+	var b strings.Builder
+	err := WriteCheckResults(&b, []CheckResult{
+		{API: "doc API docs.example.com", OK: true, Status: http.StatusOK},
+		{API: "Postman API", Detail: "dial tcp: connection refused"},
+	})
+	if err != nil {
+		t.Fatalf("WriteCheckResults() error = %v", err)
+	}
+
+	want := "API                       RESULT  STATUS  DETAIL\n" +
+		"doc API docs.example.com  OK      200     \n" +
+		"Postman API               FAIL    -       dial tcp: connection refused\n"
+	if b.String() != want {
+		t.Errorf("WriteCheckResults() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultCircuitWindow is the circuit breaker window of the command line.
+const DefaultCircuitWindow = 24 * time.Hour
+
+// ErrCircuitOpen marks modules skipped because their doc host failed too
+// often in a row. Unlike a disabled feature flag, it fails the run, so a host
+// that stays down does not go unnoticed while its modules are skipped.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// HostFailures counts the consecutive failed doc fetches from one host.
+type HostFailures struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// circuitBreaker stops fetching docs from a host that failed threshold times
+// in a row, each failure within window of the previous one, until window
+// has passed since the last failure. The next fetch then decides whether the
+// host is healthy again: a success closes the circuit, a failure opens it for
+// another window. It is shared by all copies of a client.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+	hosts     map[string]HostFailures
+}
+
+// WithCircuitBreaker skips the modules whose doc host failed threshold times
+// in a row, counting only failures less than window apart, until window has
+// passed. With a state the failures are remembered across runs. A threshold
+// of zero disables the breaker.
+func WithCircuitBreaker(threshold int, window time.Duration) ClientOption {
+	return func(c *APIClient) {
+		if threshold <= 0 {
+			c.circuit = nil
+			return
+		}
+		c.circuit = &circuitBreaker{threshold: threshold, window: window, now: time.Now, hosts: map[string]HostFailures{}}
+	}
+}
+
+// failures returns the failures of host in this run, or else those recorded
+// in the state.
+func (b *circuitBreaker) failures(host string, state *State) HostFailures {
+	if f, ok := b.hosts[host]; ok {
+		return f
+	}
+	if state != nil {
+		f, _ := state.DocHostFailures(host)
+		return f
+	}
+	return HostFailures{}
+}
+
+// checkCircuit returns an error wrapping ErrCircuitOpen while the circuit of
+// the doc URL's host is open.
+func (c *APIClient) checkCircuit(docURL string) error {
+	if c.circuit == nil {
+		return nil
+	}
+	host := docHost(docURL)
+
+	c.circuit.mu.Lock()
+	defer c.circuit.mu.Unlock()
+
+	f := c.circuit.failures(host, c.state)
+	if f.Count < c.circuit.threshold || c.circuit.now().Sub(f.Last) >= c.circuit.window {
+		return nil
+	}
+	return fmt.Errorf("skipped (%w): doc host %s failed %d times in a row, last at %s", ErrCircuitOpen, host, f.Count, f.Last.Format(time.RFC3339))
+}
+
+// recordDocFetch counts a failed fetch of the doc URL towards opening the
+// circuit of its host, and a successful one closes it. Errors that say
+// nothing about the host's health, such as an invalid doc, are ignored.
+func (c *APIClient) recordDocFetch(ctx context.Context, docURL string, err error) {
+	if c.circuit == nil || ctx.Err() != nil {
+		return
+	}
+	if err != nil && !isHostFailure(err) {
+		return
+	}
+	host := docHost(docURL)
+
+	c.circuit.mu.Lock()
+	defer c.circuit.mu.Unlock()
+
+	var f HostFailures
+	if err != nil {
+		now := c.circuit.now()
+		f = c.circuit.failures(host, c.state)
+		// Failures further apart than the window are not in a row, except
+		// the failed probe of an open circuit, which reopens it.
+		if f.Count < c.circuit.threshold && now.Sub(f.Last) >= c.circuit.window {
+			f.Count = 0
+		}
+		f.Count++
+		f.Last = now
+		switch {
+		case f.Count == c.circuit.threshold:
+			c.log.WarnContext(ctx, "opening circuit for doc host", "host", host, "failures", f.Count, "window", c.circuit.window)
+		case f.Count > c.circuit.threshold:
+			c.log.WarnContext(ctx, "reopening circuit for doc host", "host", host, "failures", f.Count, "window", c.circuit.window)
+		}
+	}
+
+	c.circuit.hosts[host] = f
+	if c.state != nil && !c.dryRun {
+		c.state.SetDocHostFailures(host, f)
+	}
+}
+
+// isHostFailure reports whether a doc fetch failed because the host could
+// not be reached or answered with a server error.
+func isHostFailure(err error) bool {
+	var statusErr *docStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Status >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// docHost returns the host of a doc URL, or the URL itself when it has none.
+func docHost(docURL string) string {
+	if u, err := url.Parse(docURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return docURL
+}
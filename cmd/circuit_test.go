@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSyncAllModules_CircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var fetches atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`))
+	}))
+	defer failing.Close()
+
+	postman := httptest.NewServer(&mockPostman{collections: `{"collections":[]}`})
+	defer postman.Close()
+
+	now := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	state := &State{}
+	newClient := func() *APIClient {
+		client := NewAPIClient("doc-key", "pm-key",
+			WithState(state),
+			WithPostmanBaseURL(postman.URL),
+			WithDocURLTemplate(failing.URL+"/%s"),
+			WithCircuitBreaker(2, time.Hour),
+			WithOutput(io.Discard),
+		)
+		client.circuit.now = func() time.Time { return now }
+		return client
+	}
+
+	config := &ModuleConfig{
+		Modules:     map[string]string{"Brands": "Brands Module API", "Classes": "Classes Module API", "Customers": "Customers Module API"},
+		Concurrency: 1,
+	}
+
+	results, err := NewSyncOrchestrator(newClient(), config).SyncAllModules(t.Context(), "workspace")
+	if err == nil || !strings.Contains(err.Error(), "Brands") || !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("SyncAllModules() error = %v, want the failures and the skipped module", err)
+	}
+	wantStatuses := []ModuleStatus{StatusFailed, StatusFailed, StatusSkipped}
+	for i, want := range wantStatuses {
+		if results[i].Status != want {
+			t.Errorf("%s status = %s, want %s", results[i].Module, results[i].Status, want)
+		}
+	}
+	if !errors.Is(results[2].Err, ErrCircuitOpen) {
+		t.Errorf("Customers error = %v, want circuit open", results[2].Err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("doc host fetched %d times, want 2", fetches.Load())
+	}
+
+	// The next run within the window skips the host without a request, and
+	// still fails.
+	now = now.Add(30 * time.Minute)
+	results, err = NewSyncOrchestrator(newClient(), config).SyncAllModules(t.Context(), "workspace")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("SyncAllModules() within the window error = %v, want circuit open", err)
+	}
+	for _, r := range results {
+		if r.Status != StatusSkipped {
+			t.Errorf("%s status = %s within the window, want skipped", r.Module, r.Status)
+		}
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("doc host fetched %d times within the window, want no more", fetches.Load())
+	}
+
+	// Once the window has passed, the recovered host is tried and closes
+	// the circuit.
+	now = now.Add(time.Hour)
+	down.Store(false)
+	if _, err := NewSyncOrchestrator(newClient(), config).SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() after the window error = %v", err)
+	}
+	if _, ok := state.DocHostFailures(docHost(failing.URL)); ok {
+		t.Error("state still records failures of the recovered host")
+	}
+}
+
+func TestAPIClient_recordDocFetch(t *testing.T) {
+	now := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	client := NewAPIClient("doc-key", "pm-key", WithCircuitBreaker(2, time.Hour), WithOutput(io.Discard))
+	client.circuit.now = func() time.Time { return now }
+	const docURL = "https://api.customers.example.com/v1/internal-docs"
+	unavailable := &docStatusError{Status: http.StatusServiceUnavailable}
+
+	client.recordDocFetch(t.Context(), docURL, &docStatusError{Status: http.StatusNotFound})
+	client.recordDocFetch(t.Context(), docURL, errEmptyDoc)
+	if err := client.checkCircuit(docURL); err != nil {
+		t.Fatalf("checkCircuit() = %v after client errors, want closed", err)
+	}
+
+	client.recordDocFetch(t.Context(), docURL, unavailable)
+	now = now.Add(2 * time.Hour)
+	client.recordDocFetch(t.Context(), docURL, unavailable)
+	if err := client.checkCircuit(docURL); err != nil {
+		t.Fatalf("checkCircuit() = %v after failures further apart than the window, want closed", err)
+	}
+
+	client.recordDocFetch(t.Context(), docURL, unavailable)
+	if err := client.checkCircuit(docURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("checkCircuit() = %v, want circuit open", err)
+	}
+	if err := client.checkCircuit("https://api.brands.example.com/v1/internal-docs"); err != nil {
+		t.Errorf("checkCircuit() = %v for another host, want closed", err)
+	}
+
+	// Once the window has passed, a failed probe reopens the circuit.
+	now = now.Add(2 * time.Hour)
+	if err := client.checkCircuit(docURL); err != nil {
+		t.Fatalf("checkCircuit() = %v after the window, want the host tried", err)
+	}
+	client.recordDocFetch(t.Context(), docURL, unavailable)
+	if err := client.checkCircuit(docURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("checkCircuit() = %v after a failed probe, want circuit open", err)
+	}
+
+	client.recordDocFetch(t.Context(), docURL, nil)
+	if err := client.checkCircuit(docURL); err != nil {
+		t.Errorf("checkCircuit() = %v after a success, want closed", err)
+	}
+}
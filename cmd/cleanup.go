@@ -0,0 +1,455 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PreparedModule holds a fetched doc together with the existing collections
+// that will be replaced when it is imported.
+type PreparedModule struct {
+	ModuleName     string
+	CollectionName string
+	WorkspaceID    string
+	Doc            string
+	Stale          []CollectionRef
+	// Target is the collection updated in place instead of importing a new
+	// one, when upserting a module whose collection is known.
+	Target *CollectionRef
+	// DocHash is the hash of the doc as fetched, recorded once it is synced.
+	DocHash string
+	// Surface is the surface of the doc as fetched, recorded with DocHash.
+	Surface *SpecSurface
+	// Validator holds the doc's ETag and Last-Modified, recorded with
+	// DocHash.
+	Validator DocValidator
+	// Unchanged is set when the doc is the same as when the module was last
+	// synced; nothing is listed and the import is skipped.
+	Unchanged bool
+}
+
+// PhasedProcessor splits module processing into preparation, cleanup and
+// import so the orchestrator can run the cleanup for all modules at once.
+type PhasedProcessor interface {
+	PrepareModule(ctx context.Context, moduleName, collectionName, workspaceID string) (*PreparedModule, error)
+	DeleteCollections(ctx context.Context, refs []CollectionRef) error
+	ImportModule(ctx context.Context, prepared *PreparedModule) error
+}
+
+// PrepareModule fetches the module's doc and lists the collections it replaces.
+// Nothing is changed in Postman.
+func (c *APIClient) PrepareModule(ctx context.Context, moduleName, collectionName, workspaceID string) (*PreparedModule, error) {
+	ctx = withModule(ctx, moduleName)
+	url, err := c.moduleDocURL(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkCircuit(url); err != nil {
+		c.log.WarnContext(ctx, "doc host keeps failing, skipping", "error", err)
+		return nil, err
+	}
+
+	validator, recordedHash := c.docValidator(moduleName)
+	start := time.Now()
+	data, validator, err := c.fetchDocIfModified(ctx, url, validator)
+	c.timePhase(ctx, phaseFetch, start)
+	c.recordDocFetch(ctx, url, err)
+	if errors.Is(err, errNotModified) {
+		c.log.InfoContext(ctx, "doc not modified since last sync, skipping")
+		return &PreparedModule{
+			ModuleName:     moduleName,
+			CollectionName: collectionName,
+			WorkspaceID:    workspaceID,
+			DocHash:        recordedHash,
+			Validator:      validator,
+			Unchanged:      true,
+		}, nil
+	}
+	if err != nil {
+		c.log.ErrorContext(ctx, "fetching doc failed", "error", err)
+		return nil, err
+	}
+
+	// Whatever the strategy, a broken doc must not cost the old collection.
+	if err := ValidateSpec(data); err != nil {
+		c.log.ErrorContext(ctx, "spec validation failed", "error", err)
+		return nil, err
+	}
+
+	// Measure the spec as published, before any rewrite below.
+	if metrics, err := computeSpecMetrics(data); err == nil {
+		c.metrics.record(moduleName, metrics)
+	}
+
+	hash := docHash(data)
+	if c.unchanged(moduleName, hash) {
+		c.log.InfoContext(ctx, "spec unchanged since last sync, skipping")
+		// The doc API may have started sending validators since the sync.
+		if !c.dryRun {
+			c.state.SetDocValidator(moduleName, validator)
+		}
+		return &PreparedModule{
+			ModuleName:     moduleName,
+			CollectionName: collectionName,
+			WorkspaceID:    workspaceID,
+			DocHash:        hash,
+			Validator:      validator,
+			Unchanged:      true,
+		}, nil
+	}
+
+	var surface *SpecSurface
+	if s, err := computeSpecSurface(data); err == nil {
+		if err := c.checkBreakingChanges(ctx, moduleName, s); err != nil {
+			c.log.ErrorContext(ctx, "breaking API changes are blocked", "error", err)
+			return nil, err
+		}
+		surface = &s
+	}
+
+	if isSwagger2(data) {
+		c.log.InfoContext(ctx, "converting swagger 2.0 spec to openapi 3.0")
+		data, err = convertSwagger2(data)
+		if err != nil {
+			c.log.ErrorContext(ctx, "converting spec failed", "error", err)
+			return nil, err
+		}
+	}
+
+	if c.injectSecurity != "" {
+		data, err = injectSecurity(data, c.injectSecurity, c.forceSecurity)
+		if err != nil {
+			c.log.ErrorContext(ctx, "injecting security scheme failed", "error", err)
+			return nil, err
+		}
+	}
+
+	if c.generateOperationID {
+		data, err = generateOperationIDs(data)
+		if err != nil {
+			c.log.ErrorContext(ctx, "generating operation IDs failed", "error", err)
+			return nil, err
+		}
+	}
+
+	if len(c.maskPatterns) > 0 {
+		data, err = maskSpec(data, c.maskPatterns)
+		if err != nil {
+			c.log.ErrorContext(ctx, "masking spec failed", "error", err)
+			return nil, err
+		}
+	}
+
+	if err := c.checkDuplicateOperationIDs(ctx, data); err != nil {
+		return nil, err
+	}
+
+	if c.requireOperationID != "" {
+		if err := c.checkOperationIDs(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	// Normalize after the checks above, which report the spec as published.
+	if c.normalize {
+		data = c.normalizeDoc(ctx, data)
+	}
+
+	prepared := &PreparedModule{
+		ModuleName:     moduleName,
+		CollectionName: collectionName,
+		WorkspaceID:    workspaceID,
+		DocHash:        hash,
+		Surface:        surface,
+		Validator:      validator,
+	}
+
+	start = time.Now()
+	uid, configured := c.collectionUIDs[moduleName]
+	id, known := c.knownCollection(moduleName)
+	switch {
+	case c.offline():
+		// Nothing is synced, so Postman is not consulted.
+	case configured:
+		// The configured collection is updated in place, and the other
+		// collections of its name are left alone.
+		prepared.Target = &CollectionRef{UID: uid}
+		err = c.checkConfiguredCollection(ctx, moduleName, uid)
+		if err == nil && c.collectionKeyField != "" {
+			data, _, err = embedCollectionKey(data, c.collectionKeyField)
+		}
+	case known:
+		// The recorded collection is updated in place. Only with an id map
+		// are the other collections of its name listed, to delete them.
+		prepared.Target = &CollectionRef{UID: id}
+		if c.idMap != nil {
+			prepared.Stale, err = c.strayCollections(ctx, collectionName, workspaceID, id)
+		}
+		if err == nil && c.collectionKeyField != "" {
+			data, _, err = embedCollectionKey(data, c.collectionKeyField)
+		}
+	default:
+		// Check if collection already exists
+		if c.collectionKeyField == "" {
+			prepared.Stale, err = c.getCollectionsByName(ctx, collectionName, workspaceID)
+		} else {
+			var key string
+			data, key, err = embedCollectionKey(data, c.collectionKeyField)
+			if err == nil {
+				prepared.Stale, err = c.getCollectionsByKey(ctx, key, workspaceID)
+			}
+		}
+	}
+	c.timePhase(ctx, phaseDelete, start)
+	if err != nil {
+		c.log.ErrorContext(ctx, "checking existing collections failed", "error", err)
+		return nil, err
+	}
+
+	if err := c.checkDuplicateCollections(ctx, collectionName, prepared.Stale); err != nil {
+		return nil, err
+	}
+
+	// Canonicalize last so no later rewrite reintroduces unstable formatting.
+	if c.canonical {
+		data, err = canonicalizeSpec(data)
+		if err != nil {
+			c.log.ErrorContext(ctx, "canonicalizing spec failed", "error", err)
+			return nil, err
+		}
+	}
+
+	prepared.Doc = data
+	return prepared, nil
+}
+
+// DeleteCollections deletes every given collection, continuing past failures.
+func (c *APIClient) DeleteCollections(ctx context.Context, refs []CollectionRef) error {
+	defer c.timePhase(ctx, phaseDelete, time.Now())
+
+	var errs []error
+	for _, ref := range refs {
+		if c.dryRun {
+			c.log.InfoContext(ctx, "dry run: would delete collection", "collection", ref.DeleteKey(), "name", ref.Name)
+			continue
+		}
+
+		c.log.InfoContext(ctx, "deleting existing collection", "collection", ref.DeleteKey())
+		if err := c.deleteCollection(ctx, ref); err != nil {
+			c.log.ErrorContext(ctx, "deleting collection failed", "collection", ref.DeleteKey(), "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ImportModule imports a prepared module's doc into Postman, or updates the
+// prepared target collection in place.
+func (c *APIClient) ImportModule(ctx context.Context, prepared *PreparedModule) error {
+	ctx = withModule(ctx, prepared.ModuleName)
+	defer c.timePhase(ctx, phaseImport, time.Now())
+
+	if prepared.Unchanged {
+		return ErrUnchanged
+	}
+
+	if err := ValidateSpec(prepared.Doc); err != nil {
+		c.log.ErrorContext(ctx, "skipping import of invalid spec", "error", err)
+		return fmt.Errorf("skipping import: %w", err)
+	}
+
+	if c.offline() {
+		return c.finishOffline(ctx, prepared)
+	}
+
+	if prepared.Target != nil {
+		err := c.updateModule(ctx, prepared)
+		if !c.recordedCollectionGone(ctx, prepared, err) {
+			if err != nil {
+				return err
+			}
+			c.rememberDoc(prepared)
+			return nil
+		}
+		// The collection is imported anew below, and recorded in its place.
+		prepared.Target = nil
+	}
+
+	if c.dryRun {
+		size, err := importPayloadSize(prepared.Doc, c.importOptions[prepared.ModuleName])
+		if err != nil {
+			return err
+		}
+		c.log.InfoContext(ctx, "dry run: would import collection", "collection", prepared.CollectionName, "bytes", size)
+		return nil
+	}
+
+	// A retried import may leave collections of earlier attempts, which
+	// are told apart from any other by a title marking this import.
+	doc, title := prepared.Doc, ""
+	if c.importRetries() > 0 {
+		var err error
+		if doc, title, err = markImport(prepared.Doc, prepared.CollectionName); err != nil {
+			return err
+		}
+	}
+
+	imported, retried, err := c.importToPostman(ctx, doc, prepared.CollectionName, prepared.WorkspaceID, c.importOptions[prepared.ModuleName])
+	if err != nil {
+		c.log.ErrorContext(ctx, "postman import failed", "error", err)
+		return err
+	}
+
+	if retried {
+		if err := c.removeDuplicateImports(ctx, prepared.WorkspaceID, title, imported); err != nil {
+			c.log.ErrorContext(ctx, "removing duplicate imports failed", "error", err)
+			return err
+		}
+	}
+
+	if err := c.renameImported(ctx, imported, prepared); err != nil {
+		c.log.ErrorContext(ctx, "renaming imported collection failed", "error", err)
+		return err
+	}
+
+	if c.collectionKeyField != "" {
+		if description, err := specDescription(prepared.Doc); err == nil {
+			c.keyIndex.added(prepared.WorkspaceID, collectionKey(description), imported)
+		}
+	}
+
+	if len(imported) > 0 {
+		c.changes.created(prepared.ModuleName, imported[0].UpdateKey())
+		if c.state != nil {
+			c.state.SetCollectionID(prepared.ModuleName, imported[0].UpdateKey())
+		}
+		if c.idMap != nil {
+			c.idMap.SetCollectionID(prepared.ModuleName, imported[0].UpdateKey())
+		}
+	}
+
+	if c.verifyImport != "" {
+		for _, ref := range imported {
+			if err := c.verifyCollection(ctx, ref, prepared.Doc); err != nil {
+				c.log.ErrorContext(ctx, "verifying imported collection failed", "collection", ref.UpdateKey(), "error", err)
+				return err
+			}
+		}
+	}
+
+	if c.share != "" {
+		for _, ref := range imported {
+			if err := c.shareCollection(ctx, ref, c.share); err != nil {
+				return fmt.Errorf("sharing collection %s: %w", ref.UpdateKey(), err)
+			}
+		}
+	}
+
+	c.rememberDoc(prepared)
+	return nil
+}
+
+// syncInPhases prepares all modules concurrently, deletes every stale
+// collection in a single coordinated phase, then imports the modules. A
+// module's result covers both its preparation and its import. Phases start
+// only while ctx is not done; the work within them runs with work. A failed
+// module calls abort.
+func (s *SyncOrchestrator) syncInPhases(ctx, work context.Context, workspaceID string, abort context.CancelCauseFunc) ([]ModuleResult, error) {
+	var (
+		mu         sync.Mutex
+		prepared   = map[string]*PreparedModule{}
+		processors = map[string]PhasedProcessor{}
+	)
+
+	prepareResults, prepareErr := s.runModules(ctx, s.config, func(mod string) error {
+		processor, err := s.phasedProcessorFor(mod)
+		if err != nil {
+			return err
+		}
+		if err := s.checkFeatureFlag(work, mod, processor); err != nil {
+			return err
+		}
+		if err := s.waitStartJitter(work); err != nil {
+			return err
+		}
+
+		module, err := processor.PrepareModule(work, mod, s.config.Modules[mod], s.config.workspaceFor(mod, workspaceID))
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		prepared[mod] = module
+		processors[mod] = processor
+		mu.Unlock()
+		return nil
+	}, s.abortOnFailure(abort, func(result ModuleResult) {
+		// Prepared modules are complete only once imported.
+		if result.Status != StatusSucceeded {
+			s.completed(result)
+		}
+	}))
+
+	prepareDurations := map[string]time.Duration{}
+	for _, result := range prepareResults {
+		prepareDurations[result.Module] = result.Duration
+	}
+
+	// A shutdown before the cleanup leaves every collection in place.
+	if ctx.Err() == nil {
+		s.log.InfoContext(ctx, "cleaning up stale collections")
+		for mod, module := range prepared {
+			modCtx := withModule(work, mod)
+			if err := processors[mod].DeleteCollections(modCtx, module.Stale); err != nil {
+				s.log.ErrorContext(modCtx, "deleting collections failed", "error", err)
+			}
+		}
+	}
+
+	// Modules that failed to prepare already reported their error.
+	importResults, importErr := s.runModules(ctx, s.config.withModules(slices.Collect(maps.Keys(prepared))), func(mod string) error {
+		if err := processors[mod].ImportModule(work, prepared[mod]); err != nil {
+			return err
+		}
+		s.log.InfoContext(withModule(work, mod), "processed module")
+		return nil
+	}, s.abortOnFailure(abort, func(result ModuleResult) {
+		result.Duration += prepareDurations[result.Module]
+		s.completed(result)
+	}))
+
+	imported := map[string]ModuleResult{}
+	for _, result := range importResults {
+		result.Duration += prepareDurations[result.Module]
+		imported[result.Module] = result
+	}
+
+	results := prepareResults
+	for i, result := range results {
+		if imp, ok := imported[result.Module]; ok {
+			results[i] = imp
+		}
+	}
+
+	return results, errors.Join(prepareErr, importErr)
+}
+
+func (s *SyncOrchestrator) phasedProcessorFor(moduleName string) (PhasedProcessor, error) {
+	processor, err := s.processorFor(moduleName)
+	if err != nil {
+		return nil, fmt.Errorf("configuring client: %w", err)
+	}
+
+	phased, ok := processor.(PhasedProcessor)
+	if !ok {
+		return nil, errors.New("processor does not support batch cleanup")
+	}
+
+	return phased, nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// phasedProcessor records the order of cleanup and import calls.
+type phasedProcessor struct {
+	mu         sync.Mutex
+	stale      map[string][]CollectionRef
+	failImport string
+	calls      []string
+}
+
+func (p *phasedProcessor) record(call string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+}
+
+func (p *phasedProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	return errors.New("ProcessModule should not be called in batch cleanup mode")
+}
+
+func (p *phasedProcessor) PrepareModule(ctx context.Context, moduleName, collectionName, workspaceID string) (*PreparedModule, error) {
+	p.record("prepare " + moduleName)
+	return &PreparedModule{
+		ModuleName:     moduleName,
+		CollectionName: collectionName,
+		WorkspaceID:    workspaceID,
+		Stale:          p.stale[moduleName],
+	}, nil
+}
+
+func (p *phasedProcessor) DeleteCollections(ctx context.Context, refs []CollectionRef) error {
+	for _, ref := range refs {
+		p.record("delete " + ref.ID)
+	}
+	return nil
+}
+
+func (p *phasedProcessor) ImportModule(ctx context.Context, prepared *PreparedModule) error {
+	p.record("import " + prepared.ModuleName)
+	if prepared.ModuleName == p.failImport {
+		return errors.New("import failed")
+	}
+	return nil
+}
+
+func TestSyncAllModules_BatchCleanup(t *testing.T) {
+	processor := &phasedProcessor{
+		stale: map[string][]CollectionRef{
+			"Customers": {{ID: "c1"}, {ID: "c2"}},
+			"Brands":    {{ID: "b1"}},
+		},
+	}
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Brands":    "Brands Module API",
+			"Home":      "Home Module API",
+		},
+		BatchCleanup: true,
+	}
+
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	var deletes []string
+	lastDelete, firstImport := -1, len(processor.calls)
+	for i, call := range processor.calls {
+		switch call[:6] {
+		case "delete":
+			deletes = append(deletes, call)
+			lastDelete = i
+		case "import":
+			firstImport = min(firstImport, i)
+		}
+	}
+
+	slices.Sort(deletes)
+	if want := []string{"delete b1", "delete c1", "delete c2"}; !slices.Equal(deletes, want) {
+		t.Errorf("deletes = %v, want %v", deletes, want)
+	}
+	if lastDelete > firstImport {
+		t.Errorf("calls = %v, want every delete before the first import", processor.calls)
+	}
+	if n := len(processor.calls); n != 9 {
+		t.Errorf("got %d calls, want 3 prepares, 3 deletes and 3 imports: %v", n, processor.calls)
+	}
+}
+
+func TestSyncAllModules_BatchCleanupImportError(t *testing.T) {
+	processor := &phasedProcessor{failImport: "Brands"}
+	config := &ModuleConfig{
+		Modules:      map[string]string{"Customers": "Customers Module API", "Brands": "Brands Module API"},
+		BatchCleanup: true,
+	}
+
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err == nil {
+		t.Error("SyncAllModules() error = nil, want import error")
+	}
+}
+
+func TestAPIClient_DeleteCollectionsLogsDeleteKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/collections/c2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var out strings.Builder
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(&out), WithRetry(0, 0))
+	client.postmanBaseURL = server.URL
+
+	// Collections listed without a uid are known by their id only.
+	refs := []CollectionRef{{ID: "c1"}, {ID: "c2"}}
+	if err := client.DeleteCollections(t.Context(), refs); err == nil {
+		t.Fatal("DeleteCollections() error = nil, want the failed delete of c2")
+	}
+
+	for _, want := range []string{
+		`msg="deleting existing collection" collection=c1`,
+		`msg="deleting collection failed" collection=c2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"net/http"
+	"sync"
+)
+
+// httpClientSet holds the HTTP clients of the copies made with
+// WithClientSettings. It is shared by all copies of a client.
+type httpClientSet struct {
+	mu      sync.Mutex
+	clients []*http.Client
+}
+
+func (s *httpClientSet) add(client *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients = append(s.clients, client)
+}
+
+// Close closes the idle keep-alive connections of the client and of every
+// copy made from it with WithClientSettings since the last Close. Services
+// that create clients over time should call it once a client is no longer
+// used, or the idle connections pile up. Connections in use are left alone,
+// and the client remains usable afterwards. It always returns nil.
+func (c *APIClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+
+	c.httpClients.mu.Lock()
+	defer c.httpClients.mu.Unlock()
+	for _, client := range c.httpClients.clients {
+		client.CloseIdleConnections()
+	}
+	c.httpClients.clients = nil
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAPIClient_Close(t *testing.T) {
+	var mu sync.Mutex
+	open := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch state {
+		case http.StateNew:
+			open++
+		case http.StateClosed:
+			open--
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	openConns := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return open
+	}
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = server.URL
+	processor, err := client.WithClientSettings(ClientSettings{Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+
+	for _, c := range []*APIClient{client, processor.(*APIClient)} {
+		if _, err := c.listCollections(t.Context(), "workspace"); err != nil {
+			t.Fatalf("listCollections() error = %v", err)
+		}
+	}
+	if got := openConns(); got != 2 {
+		t.Fatalf("%d connections open after the requests, want 2 idle ones", got)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for openConns() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := openConns(); got != 0 {
+		t.Errorf("%d connections still open after Close(), want 0", got)
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// collectionKeyMarker prefixes the canonical collection key embedded in the
+// description of every imported collection when a key field is configured.
+const collectionKeyMarker = "apisync-key: "
+
+// WithCollectionKeyField makes the client match existing collections by the
+// value of a spec field, given as a dotted path such as "info.x-collection-id",
+// instead of by collection name.
+func WithCollectionKeyField(path string) ClientOption {
+	return func(c *APIClient) {
+		c.collectionKeyField = path
+	}
+}
+
+// specField returns the string value at a dotted path in a parsed spec.
+func specField(spec map[string]any, path string) (string, bool) {
+	var current any = spec
+	for part := range strings.SplitSeq(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		current = object[part]
+	}
+
+	value, ok := current.(string)
+	return value, ok && value != ""
+}
+
+// embedCollectionKey extracts the key from the spec and appends it to
+// info.description, which Postman copies into the collection description.
+func embedCollectionKey(doc, keyField string) (string, string, error) {
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	key, ok := specField(spec, keyField)
+	if !ok {
+		return "", "", fmt.Errorf("spec has no string value at %s", keyField)
+	}
+
+	info, _ := spec["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+		spec["info"] = info
+	}
+
+	description, _ := info["description"].(string)
+	if description != "" {
+		description += "\n\n"
+	}
+	info["description"] = description + collectionKeyMarker + key
+
+	embedded, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return string(embedded), key, nil
+}
+
+// collectionKey returns the key embedded in a collection description, if any.
+func collectionKey(description any) string {
+	var text string
+	switch d := description.(type) {
+	case string:
+		text = d
+	case map[string]any:
+		text, _ = d["content"].(string)
+	}
+
+	for line := range strings.SplitSeq(text, "\n") {
+		if key, ok := strings.CutPrefix(strings.TrimSpace(line), collectionKeyMarker); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// getCollection returns the body of the single-collection response.
+func (c *APIClient) getCollection(ctx context.Context, ref CollectionRef) ([]byte, error) {
+	url := fmt.Sprintf("%s/collections/%s", c.postmanBaseURL, ref.UpdateKey())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, postmanStatusError(resp.StatusCode, fmt.Errorf("failed to get collection: %d %s", resp.StatusCode, string(body)))
+	}
+	return body, nil
+}
+
+func (c *APIClient) getCollectionDescription(ctx context.Context, ref CollectionRef) (any, error) {
+	body, err := c.getCollection(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Collection struct {
+			Info struct {
+				Description any `json:"description"`
+			} `json:"info"`
+		} `json:"collection"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return result.Collection.Info.Description, nil
+}
+
+// collectionKeyIndex holds, per workspace, the collections carrying each
+// collection key, so every collection's description is fetched once per run
+// however many modules look up their key. It is shared by all copies of a
+// client.
+type collectionKeyIndex struct {
+	mu         sync.Mutex
+	workspaces map[string]map[string][]CollectionRef
+}
+
+// added records collections imported with the key into an indexed
+// workspace.
+func (i *collectionKeyIndex) added(workspaceID, key string, refs []CollectionRef) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if keys, ok := i.workspaces[workspaceID]; ok {
+		keys[key] = append(keys[key], refs...)
+	}
+}
+
+// removed drops a deleted collection from the index.
+func (i *collectionKeyIndex) removed(ref CollectionRef) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, keys := range i.workspaces {
+		for key, refs := range keys {
+			keys[key] = slices.DeleteFunc(refs, func(r CollectionRef) bool { return r.DeleteKey() == ref.DeleteKey() })
+		}
+	}
+}
+
+// getCollectionsByKey returns the workspace collections whose description
+// carries the given collection key, whatever their display name. The
+// workspace is indexed on its first lookup.
+func (c *APIClient) getCollectionsByKey(ctx context.Context, key, workspaceID string) ([]CollectionRef, error) {
+	index := c.keyIndex
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	keys, ok := index.workspaces[workspaceID]
+	if !ok {
+		var err error
+		if keys, err = c.indexCollectionKeys(ctx, workspaceID); err != nil {
+			return nil, err
+		}
+		if index.workspaces == nil {
+			index.workspaces = map[string]map[string][]CollectionRef{}
+		}
+		index.workspaces[workspaceID] = keys
+	}
+
+	return slices.Clone(keys[key]), nil
+}
+
+// indexCollectionKeys fetches the description of every workspace collection
+// and groups the collections by the key they carry.
+func (c *APIClient) indexCollectionKeys(ctx context.Context, workspaceID string) (map[string][]CollectionRef, error) {
+	collections, err := c.listCollections(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string][]CollectionRef{}
+	for _, ref := range collections {
+		if ref.DeleteKey() == "" {
+			c.log.WarnContext(ctx, "skipping listed collection without an id", "name", ref.Name)
+			continue
+		}
+		description, err := c.getCollectionDescription(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if key := collectionKey(description); key != "" {
+			keys[key] = append(keys[key], ref)
+		}
+	}
+
+	return keys, nil
+}
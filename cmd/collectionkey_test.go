@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSpecField(t *testing.T) {
+	spec := map[string]any{
+		"info": map[string]any{"title": "Customers", "x-collection-id": "customers", "version": 1.0},
+	}
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{path: "info.title", want: "Customers", wantOK: true},
+		{path: "info.x-collection-id", want: "customers", wantOK: true},
+		{path: "info.version", wantOK: false},
+		{path: "info.missing", wantOK: false},
+		{path: "info.title.nested", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := specField(spec, tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("specField(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestEmbedCollectionKey(t *testing.T) {
+	doc := `{"openapi":"3.0.0","info":{"title":"Customers","description":"Customer APIs","x-collection-id":"customers"}}`
+
+	embedded, key, err := embedCollectionKey(doc, "info.x-collection-id")
+	if err != nil {
+		t.Fatalf("embedCollectionKey() error = %v", err)
+	}
+	if key != "customers" {
+		t.Errorf("key = %q, want customers", key)
+	}
+
+	var spec map[string]any
+	json.Unmarshal([]byte(embedded), &spec)
+	description := spec["info"].(map[string]any)["description"]
+	if description != "Customer APIs\n\napisync-key: customers" {
+		t.Errorf("description = %q", description)
+	}
+	if collectionKey(description) != "customers" {
+		t.Errorf("collectionKey() = %q, want customers", collectionKey(description))
+	}
+
+	if _, _, err := embedCollectionKey(`{"info":{}}`, "info.x-collection-id"); err == nil {
+		t.Error("embedCollectionKey() error = nil, want error for a missing key field")
+	}
+}
+
+func TestAPIClient_PrepareModuleMatchesByKeyField(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Customers v2","x-collection-id":"customers"},"paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collections":
+			w.Write([]byte(`{"collections":[
+				{"id":"a","uid":"1-a","name":"Customers Module API"},
+				{"id":"b","uid":"1-b","name":"Renamed by someone"}
+			]}`))
+		case "/collections/1-a":
+			w.Write([]byte(`{"collection":{"info":{"description":"apisync-key: brands"}}}`))
+		case "/collections/1-b":
+			w.Write([]byte(`{"collection":{"info":{"description":{"content":"Docs\n\napisync-key: customers","type":"text/plain"}}}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithCollectionKeyField("info.x-collection-id"))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	prepared, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("PrepareModule() error = %v", err)
+	}
+
+	if len(prepared.Stale) != 1 || prepared.Stale[0].ID != "b" {
+		t.Errorf("Stale = %+v, want only the collection carrying the key", prepared.Stale)
+	}
+	if !strings.Contains(prepared.Doc, "apisync-key: customers") {
+		t.Errorf("prepared doc should embed the key:\n%s", prepared.Doc)
+	}
+}
+
+func TestAPIClient_PrepareModuleFetchesEachCollectionOnce(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"openapi":"3.0.0","info":{"title":"%s","x-collection-id":"%s"},"paths":{"/a":{"get":{}}}}`, r.URL.Path[1:], strings.ToLower(r.URL.Path[1:]))
+	}))
+	defer docServer.Close()
+
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/collections":
+			w.Write([]byte(`{"collections":[
+				{"id":"a","uid":"1-a","name":"Customers Module API"},
+				{"id":"b","uid":"1-b","name":"Brands Module API"}
+			]}`))
+		case "/collections/1-a":
+			w.Write([]byte(`{"collection":{"info":{"description":"apisync-key: customers"}}}`))
+		case "/collections/1-b":
+			w.Write([]byte(`{"collection":{"info":{"description":"apisync-key: brands"}}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithCollectionKeyField("info.x-collection-id"), WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(module string) string { return docServer.URL + "/" + module }
+
+	for module, want := range map[string]string{"Customers": "a", "Brands": "b"} {
+		prepared, err := client.PrepareModule(t.Context(), module, module+" Module API", "workspace")
+		if err != nil {
+			t.Fatalf("PrepareModule(%s) error = %v", module, err)
+		}
+		if len(prepared.Stale) != 1 || prepared.Stale[0].ID != want {
+			t.Errorf("%s Stale = %+v, want collection %s", module, prepared.Stale, want)
+		}
+	}
+	for _, request := range []string{"GET /collections", "GET /collections/1-a", "GET /collections/1-b"} {
+		if fetches[request] != 1 {
+			t.Errorf("%s sent %d times, want once per run", request, fetches[request])
+		}
+	}
+
+	// A deleted collection is no longer found by its key.
+	if err := client.DeleteCollections(t.Context(), []CollectionRef{{ID: "a", UID: "1-a"}}); err != nil {
+		t.Fatalf("DeleteCollections() error = %v", err)
+	}
+	prepared, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("PrepareModule() error = %v", err)
+	}
+	if len(prepared.Stale) != 0 {
+		t.Errorf("Stale = %+v after the delete, want none", prepared.Stale)
+	}
+}
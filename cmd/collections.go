@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const defaultPostmanBaseURL = "https://api.getpostman.com"
+
+// errCollectionGone reports that a collection to update no longer exists.
+var errCollectionGone = errors.New("collection no longer exists")
+
+// CollectionRef identifies a Postman collection. Postman returns both a plain
+// id and an owner-prefixed uid, and its endpoints do not accept them
+// interchangeably, so both are kept and callers pick the one an endpoint needs.
+type CollectionRef struct {
+	ID   string
+	UID  string
+	Name string
+	// Owner and UpdatedAt are reported by the list endpoint, when available,
+	// to tell collections of the same name apart.
+	Owner     string
+	UpdatedAt string
+}
+
+// DeleteKey returns the identifier used in delete requests.
+func (r CollectionRef) DeleteKey() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.UID
+}
+
+// UpdateKey returns the identifier used in update requests.
+func (r CollectionRef) UpdateKey() string {
+	if r.UID != "" {
+		return r.UID
+	}
+	return r.ID
+}
+
+// parseCollections extracts the collection references from a Postman
+// list-collections response body.
+func parseCollections(body []byte) ([]CollectionRef, error) {
+	var result struct {
+		Collections []struct {
+			ID        collectionID `json:"id"`
+			UID       collectionID `json:"uid"`
+			Name      string       `json:"name"`
+			Owner     string       `json:"owner"`
+			UpdatedAt string       `json:"updatedAt"`
+		} `json:"collections"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	refs := make([]CollectionRef, 0, len(result.Collections))
+	for _, col := range result.Collections {
+		refs = append(refs, CollectionRef{ID: string(col.ID), UID: string(col.UID), Name: col.Name, Owner: col.Owner, UpdatedAt: col.UpdatedAt})
+	}
+
+	return refs, nil
+}
+
+// collectionID is a collection identifier in a Postman response. Numbers are
+// taken as their decimal form; a missing identifier, or one of another type,
+// is left empty instead of failing the whole response.
+type collectionID string
+
+func (id *collectionID) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case string:
+		*id = collectionID(v)
+	case float64:
+		*id = collectionID(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		*id = ""
+	}
+	return nil
+}
+
+// collectionsPageSize is the number of collections requested per page.
+const collectionsPageSize = 100
+
+// listCollections returns every collection of the workspace, following the
+// offset pagination of the list endpoint until all pages are read.
+func (c *APIClient) listCollections(ctx context.Context, workspaceID string) ([]CollectionRef, error) {
+	var all []CollectionRef
+	for {
+		page, total, err := c.listCollectionsPage(ctx, workspaceID, len(all))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		if len(page) == 0 || len(all) >= total {
+			return all, nil
+		}
+	}
+}
+
+// listCollectionsPage returns the page of collections starting at offset and
+// the total number of collections in the workspace. Responses without
+// pagination metadata hold every collection.
+func (c *APIClient) listCollectionsPage(ctx context.Context, workspaceID string, offset int) ([]CollectionRef, int, error) {
+	url := fmt.Sprintf("%s/collections?workspace=%s&limit=%d&offset=%d", c.postmanBaseURL, workspaceID, collectionsPageSize, offset)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, postmanStatusError(resp.StatusCode, fmt.Errorf("failed to list collections: %d %s", resp.StatusCode, string(body)))
+	}
+
+	c.log.DebugContext(ctx, "collections response", "body", string(body))
+
+	refs, err := parseCollections(body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var page struct {
+		Meta *struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil || page.Meta == nil {
+		return refs, offset + len(refs), nil
+	}
+	return refs, page.Meta.Total, nil
+}
+
+// EnsureWorkspaceEmpty returns an error when a workspace the modules of
+// config are synced to already contains collections, so a first-time setup
+// never clobbers a populated workspace. Modules without a workspace of their
+// own are synced to workspaceID.
+func (c *APIClient) EnsureWorkspaceEmpty(ctx context.Context, config *ModuleConfig, workspaceID string) error {
+	var errs []error
+	for _, id := range config.targetWorkspaceIDs(workspaceID) {
+		collections, err := c.listCollections(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(collections) > 0 {
+			errs = append(errs, fmt.Errorf("workspace %s is not empty: it has %d collections", id, len(collections)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// EnsureCollectionCeiling returns an error when a workspace the modules of
+// config are synced to holds more than max collections, which usually means
+// earlier runs left duplicates behind.
+func (c *APIClient) EnsureCollectionCeiling(ctx context.Context, config *ModuleConfig, workspaceID string, max int) error {
+	var errs []error
+	for _, id := range config.targetWorkspaceIDs(workspaceID) {
+		collections, err := c.listCollections(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(collections) > max {
+			errs = append(errs, fmt.Errorf("workspace %s has %d collections, more than the maximum of %d", id, len(collections), max))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *APIClient) updateCollection(ctx context.Context, ref CollectionRef, collection any) error {
+	payloadJSON, err := json.Marshal(map[string]any{"collection": collection})
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s", c.postmanBaseURL, ref.UpdateKey())
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("updating collection %s: %w", ref.UpdateKey(), errCollectionGone)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return postmanStatusError(resp.StatusCode, fmt.Errorf("failed to update collection: %d %s", resp.StatusCode, string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const collectionsResponse = `{"collections":[
+	{"id":"c1a2b3","uid":"12345678-c1a2b3","name":"Customers Module API"},
+	{"id":"d4e5f6","uid":"12345678-d4e5f6","name":"Brands Module API"}
+]}`
+
+func TestParseCollections(t *testing.T) {
+	refs, err := parseCollections([]byte(collectionsResponse))
+	if err != nil {
+		t.Fatalf("parseCollections() error = %v", err)
+	}
+
+	want := []CollectionRef{
+		{ID: "c1a2b3", UID: "12345678-c1a2b3", Name: "Customers Module API"},
+		{ID: "d4e5f6", UID: "12345678-d4e5f6", Name: "Brands Module API"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("parseCollections() returned %d refs, want %d", len(refs), len(want))
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("parseCollections()[%d] = %+v, want %+v", i, refs[i], want[i])
+		}
+	}
+}
+
+func TestParseCollections_MalformedIDs(t *testing.T) {
+	refs, err := parseCollections([]byte(`{"collections":[
+		{"uid":"12345678-c1a2b3","name":"Customers Module API"},
+		{"id":42,"name":"Brands Module API"},
+		{"id":null,"name":"Home Module API"}
+	]}`))
+	if err != nil {
+		t.Fatalf("parseCollections() error = %v", err)
+	}
+
+	want := []CollectionRef{
+		{UID: "12345678-c1a2b3", Name: "Customers Module API"},
+		{ID: "42", Name: "Brands Module API"},
+		{Name: "Home Module API"},
+	}
+	if !slices.Equal(refs, want) {
+		t.Errorf("parseCollections() = %+v, want %+v", refs, want)
+	}
+}
+
+func TestAPIClient_GetCollectionsByNameSkipsMissingID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"collections":[
+			{"name":"Customers Module API"},
+			{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = server.URL
+
+	refs, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("getCollectionsByName() error = %v", err)
+	}
+	if want := []CollectionRef{{ID: "c2", UID: "1-c2", Name: "Customers Module API"}}; !slices.Equal(refs, want) {
+		t.Errorf("getCollectionsByName() = %+v, want only the collection with an id %+v", refs, want)
+	}
+}
+
+func TestCollectionRef_Keys(t *testing.T) {
+	ref := CollectionRef{ID: "c1a2b3", UID: "12345678-c1a2b3"}
+	if ref.DeleteKey() != "c1a2b3" {
+		t.Errorf("DeleteKey() = %q, want id", ref.DeleteKey())
+	}
+	if ref.UpdateKey() != "12345678-c1a2b3" {
+		t.Errorf("UpdateKey() = %q, want uid", ref.UpdateKey())
+	}
+
+	if got := (CollectionRef{UID: "12345678-c1a2b3"}).DeleteKey(); got != "12345678-c1a2b3" {
+		t.Errorf("DeleteKey() without id = %q, want uid fallback", got)
+	}
+	if got := (CollectionRef{ID: "c1a2b3"}).UpdateKey(); got != "c1a2b3" {
+		t.Errorf("UpdateKey() without uid = %q, want id fallback", got)
+	}
+}
+
+func TestAPIClient_DeleteAndUpdateUseCorrectIdentifier(t *testing.T) {
+	var deletePath, updatePath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(collectionsResponse))
+		case "DELETE":
+			deletePath = r.URL.Path
+			w.Write([]byte(`{}`))
+		case "PUT":
+			updatePath = r.URL.Path
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	refs, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("getCollectionsByName() error = %v", err)
+	}
+	if len(refs) != 1 {
+		t.Fatalf("getCollectionsByName() returned %d refs, want 1", len(refs))
+	}
+
+	if err := client.deleteCollection(t.Context(), refs[0]); err != nil {
+		t.Fatalf("deleteCollection() error = %v", err)
+	}
+	if deletePath != "/collections/c1a2b3" {
+		t.Errorf("delete path = %q, want /collections/c1a2b3", deletePath)
+	}
+
+	if err := client.updateCollection(t.Context(), refs[0], map[string]any{}); err != nil {
+		t.Fatalf("updateCollection() error = %v", err)
+	}
+	if updatePath != "/collections/12345678-c1a2b3" {
+		t.Errorf("update path = %q, want /collections/12345678-c1a2b3", updatePath)
+	}
+}
+
+// customersConfig syncs one module to the workspace given to the checks.
+var customersConfig = &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+
+func TestAPIClient_EnsureWorkspaceEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  bool
+	}{
+		{name: "empty workspace proceeds", response: `{"collections":[]}`, wantErr: false},
+		{name: "populated workspace aborts", response: collectionsResponse, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("workspace") != "workspace" {
+					t.Errorf("workspace query = %q, want workspace", r.URL.Query().Get("workspace"))
+				}
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key")
+			client.postmanBaseURL = server.URL
+
+			err := client.EnsureWorkspaceEmpty(t.Context(), customersConfig, "workspace")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EnsureWorkspaceEmpty() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIClient_EnsureCollectionCeiling(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int
+		wantErr bool
+	}{
+		{name: "below ceiling proceeds", max: 3, wantErr: false},
+		{name: "at ceiling proceeds", max: 2, wantErr: false},
+		{name: "above ceiling aborts", max: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(collectionsResponse))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key")
+			client.postmanBaseURL = server.URL
+
+			err := client.EnsureCollectionCeiling(t.Context(), customersConfig, "workspace", tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EnsureCollectionCeiling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIClient_WorkspaceChecksCoverModuleWorkspaces(t *testing.T) {
+	var listed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspace := r.URL.Query().Get("workspace")
+		listed = append(listed, workspace)
+		if workspace == "ws-partner" {
+			w.Write([]byte(collectionsResponse))
+			return
+		}
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	// Every module has a workspace of its own, so the default one is not
+	// synced to and not checked.
+	config := &ModuleConfig{
+		Modules:    map[string]string{"Customers": "Customers Module API", "Orders": "Orders Module API"},
+		Workspaces: map[string]string{"Customers": "ws-customers", "Orders": "ws-partner"},
+	}
+
+	err := client.EnsureWorkspaceEmpty(t.Context(), config, "workspace")
+	if err == nil || !strings.Contains(err.Error(), "workspace ws-partner is not empty") {
+		t.Errorf("EnsureWorkspaceEmpty() error = %v, want ws-partner reported", err)
+	}
+	err = client.EnsureCollectionCeiling(t.Context(), config, "workspace", 1)
+	if err == nil || !strings.Contains(err.Error(), "workspace ws-partner has 2 collections") {
+		t.Errorf("EnsureCollectionCeiling() error = %v, want ws-partner reported", err)
+	}
+
+	want := []string{"ws-customers", "ws-partner", "ws-customers", "ws-partner"}
+	if !slices.Equal(listed, want) {
+		t.Errorf("listed workspaces %v, want %v", listed, want)
+	}
+}
+
+func TestAPIClient_GetCollectionsByNamePaginates(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"collections":[
+			{"id":"c1","uid":"1-c1","name":"Customers Module API"},
+			{"id":"b1","uid":"1-b1","name":"Brands Module API"}
+		],"meta":{"total":4,"offset":0,"limit":2}}`,
+		"2": `{"collections":[
+			{"id":"h1","uid":"1-h1","name":"Home Module API"},
+			{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+		],"meta":{"total":4,"offset":2,"limit":2}}`,
+	}
+
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		if r.URL.Query().Get("limit") == "" {
+			t.Error("list request without a limit")
+		}
+		w.Write([]byte(pages[offset]))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = server.URL
+
+	refs, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("getCollectionsByName() error = %v", err)
+	}
+
+	want := []CollectionRef{
+		{ID: "c1", UID: "1-c1", Name: "Customers Module API"},
+		{ID: "c2", UID: "1-c2", Name: "Customers Module API"},
+	}
+	if !slices.Equal(refs, want) {
+		t.Errorf("getCollectionsByName() = %+v, want %+v", refs, want)
+	}
+	if !slices.Equal(offsets, []string{"0", "2"}) {
+		t.Errorf("requested offsets %v, want [0 2]", offsets)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithCollectionUIDs sets, per module, the uid of the collection the module
+// is synced into. A configured module's collection is updated in place
+// without listing or deleting the collections of its name; the other modules
+// are synced by name as usual.
+func WithCollectionUIDs(uids map[string]string) ClientOption {
+	return func(c *APIClient) {
+		c.collectionUIDs = uids
+	}
+}
+
+// checkConfiguredCollection fails with a clear error when the collection
+// configured for the module no longer exists, instead of the bare status of
+// the failed update.
+func (c *APIClient) checkConfiguredCollection(ctx context.Context, module, uid string) error {
+	url := fmt.Sprintf("%s/collections/%s", c.postmanBaseURL, uid)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("collection %s configured for module %s no longer exists", uid, module)
+	default:
+		return postmanStatusError(resp.StatusCode, fmt.Errorf("failed to get collection: %d %s", resp.StatusCode, string(body)))
+	}
+}
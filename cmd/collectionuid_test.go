@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_ProcessModuleConfiguredCollectionUID(t *testing.T) {
+	docURL, postmanURL, requests, puts := upsertServers(t)
+
+	client := NewAPIClient("doc-key", "pm-key", WithCollectionUIDs(map[string]string{"Customers": "1-c9"}), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	// The collection is checked and updated; nothing is listed, deleted or
+	// imported.
+	want := []string{"GET /collections/1-c9", "POST /import/openapi", "GET /collections/1-c2", "DELETE /collections/c2", "PUT /collections/1-c9"}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("Postman requests = %v, want %v", *requests, want)
+	}
+	if len(*puts) != 1 || !strings.Contains((*puts)[0], `"name":"Customers Module API"`) {
+		t.Errorf("update bodies = %v", *puts)
+	}
+}
+
+func TestAPIClient_ProcessModuleUnconfiguredModuleSyncsByName(t *testing.T) {
+	docURL, postmanURL, requests, _ := upsertServers(t)
+
+	client := NewAPIClient("doc-key", "pm-key", WithCollectionUIDs(map[string]string{"Orders": "1-c9"}), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	if len(*requests) == 0 || (*requests)[0] != "GET /collections" {
+		t.Errorf("Postman requests = %v, want the collections listed first", *requests)
+	}
+	for _, request := range *requests {
+		if request == "PUT /collections/1-c9" {
+			t.Errorf("Postman requests = %v, want no update of another module's collection", *requests)
+		}
+	}
+}
+
+func TestAPIClient_ProcessModuleConfiguredCollectionGone(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+
+	var requests []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"name":"instanceNotFoundError"}}`))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithCollectionUIDs(map[string]string{"Customers": "1-gone"}), WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err == nil || !strings.Contains(err.Error(), "collection 1-gone configured for module Customers no longer exists") {
+		t.Fatalf("ProcessModule() error = %v, want the configured collection reported missing", err)
+	}
+	if want := []string{"GET /collections/1-gone"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("Postman requests = %v, want %v", requests, want)
+	}
+}
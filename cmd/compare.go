@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+)
+
+// WorkspaceDiff counts the collections with one name in two workspaces.
+// Module is empty for collections that belong to no configured module.
+type WorkspaceDiff struct {
+	Module     string
+	Collection string
+	Left       int
+	Right      int
+}
+
+// Differs reports whether the workspaces hold a different number of the
+// collection. A module collection missing from both also differs.
+func (d WorkspaceDiff) Differs() bool {
+	return d.Left != d.Right || d.Left == 0
+}
+
+// difference describes how the workspaces differ, naming them left and right.
+func (d WorkspaceDiff) difference(left, right string) string {
+	switch {
+	case !d.Differs():
+		return ""
+	case d.Left == 0 && d.Right == 0:
+		return "missing in both"
+	case d.Right == 0:
+		return "missing in " + right
+	case d.Left == 0:
+		return "missing in " + left
+	default:
+		return "count differs"
+	}
+}
+
+// CompareWorkspaces lists the collections of two workspaces and compares,
+// by name, the collection of every configured module followed by every other
+// collection found in only one of them or in different numbers. Nothing is
+// changed.
+func (c *APIClient) CompareWorkspaces(ctx context.Context, config *ModuleConfig, left, right string) ([]WorkspaceDiff, error) {
+	leftCounts, err := c.countCollections(ctx, left)
+	if err != nil {
+		return nil, fmt.Errorf("listing workspace %s: %w", left, err)
+	}
+	rightCounts, err := c.countCollections(ctx, right)
+	if err != nil {
+		return nil, fmt.Errorf("listing workspace %s: %w", right, err)
+	}
+
+	var diffs []WorkspaceDiff
+	moduleCollections := map[string]bool{}
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		name := config.Modules[module]
+		moduleCollections[name] = true
+		diffs = append(diffs, WorkspaceDiff{Module: module, Collection: name, Left: leftCounts[name], Right: rightCounts[name]})
+	}
+
+	others := maps.Clone(leftCounts)
+	maps.Copy(others, rightCounts)
+	for _, name := range slices.Sorted(maps.Keys(others)) {
+		diff := WorkspaceDiff{Collection: name, Left: leftCounts[name], Right: rightCounts[name]}
+		if !moduleCollections[name] && diff.Differs() {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+// countCollections returns how many collections of each name the workspace holds.
+func (c *APIClient) countCollections(ctx context.Context, workspaceID string) (map[string]int, error) {
+	collections, err := c.listCollections(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, ref := range collections {
+		counts[ref.Name]++
+	}
+	return counts, nil
+}
+
+// WriteWorkspaceDiff writes the comparison of the left and right workspaces
+// as a table with a column of collection counts per workspace.
+func WriteWorkspaceDiff(w io.Writer, left, right string, diffs []WorkspaceDiff) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "MODULE\tCOLLECTION\t%s\t%s\tDIFFERENCE\n", left, right)
+
+	for _, d := range diffs {
+		module := d.Module
+		if module == "" {
+			module = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", module, d.Collection, d.Left, d.Right, d.difference(left, right))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_CompareWorkspaces(t *testing.T) {
+	workspaces := map[string]string{
+		"old": `{"collections":[
+			{"id":"1","name":"Customers Module API"},
+			{"id":"2","name":"Brands Module API"},
+			{"id":"3","name":"Brands Module API"},
+			{"id":"4","name":"Scratchpad"},
+			{"id":"5","name":"Shared"}
+		]}`,
+		"new": `{"collections":[
+			{"id":"6","name":"Customers Module API"},
+			{"id":"7","name":"Brands Module API"},
+			{"id":"8","name":"Home Module API"},
+			{"id":"9","name":"Shared"}
+		]}`,
+	}
+
+	var methods []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Write([]byte(workspaces[r.URL.Query().Get("workspace")]))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+
+	config := &ModuleConfig{Modules: map[string]string{
+		"Brands":    "Brands Module API",
+		"Classes":   "Classes Module API",
+		"Customers": "Customers Module API",
+		"Home":      "Home Module API",
+	}}
+
+	diffs, err := client.CompareWorkspaces(t.Context(), config, "old", "new")
+	if err != nil {
+		t.Fatalf("CompareWorkspaces() error = %v", err)
+	}
+
+	want := []WorkspaceDiff{
+		{Module: "Brands", Collection: "Brands Module API", Left: 2, Right: 1},
+		{Module: "Classes", Collection: "Classes Module API"},
+		{Module: "Customers", Collection: "Customers Module API", Left: 1, Right: 1},
+		{Module: "Home", Collection: "Home Module API", Right: 1},
+		{Collection: "Scratchpad", Left: 1},
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("CompareWorkspaces() = %+v, want %+v", diffs, want)
+	}
+
+	for _, method := range methods {
+		if method != "GET" {
+			t.Errorf("CompareWorkspaces() sent %s, want only GET requests", method)
+		}
+	}
+
+	var b strings.Builder
+	if err := WriteWorkspaceDiff(&b, "old", "new", diffs); err != nil {
+		t.Fatalf("WriteWorkspaceDiff() error = %v", err)
+	}
+
+	wantTable := `MODULE     COLLECTION            old  new  DIFFERENCE
+Brands     Brands Module API     2    1    count differs
+Classes    Classes Module API    0    0    missing in both
+Customers  Customers Module API  1    1    
+Home       Home Module API       0    1    missing in old
+-          Scratchpad            1    0    missing in new
+`
+	if b.String() != wantTable {
+		t.Errorf("WriteWorkspaceDiff() =\n%s\nwant\n%s", b.String(), wantTable)
+	}
+}
+
+func TestAPIClient_CompareWorkspacesListError(t *testing.T) {
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithRetry(0, 0))
+	client.postmanBaseURL = postman.URL
+
+	_, err := client.CompareWorkspaces(t.Context(), NewModuleConfig(), "old", "new")
+	if err == nil || !strings.Contains(err.Error(), "listing workspace old") {
+		t.Errorf("CompareWorkspaces() error = %v, want list error", err)
+	}
+}
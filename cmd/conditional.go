@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errNotModified is returned by a conditional doc fetch when the doc API
+// answers 304 Not Modified.
+var errNotModified = errors.New("doc not modified")
+
+// DocValidator holds the ETag and Last-Modified headers a doc was served
+// with, so the next run can fetch it conditionally. URL is the doc URL they
+// belong to; a validator is not sent to any other URL.
+type DocValidator struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// validatorOf returns the validator of a doc response, which is empty when
+// the response carries neither header.
+func validatorOf(url string, header http.Header) DocValidator {
+	v := DocValidator{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+	if v.isZero() {
+		return DocValidator{}
+	}
+	v.URL = url
+	return v
+}
+
+func (v DocValidator) isZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// setConditions makes req conditional on the doc having changed.
+func (v DocValidator) setConditions(req *http.Request) {
+	if v.URL != req.URL.String() {
+		return
+	}
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+// docValidator returns the validator to fetch the module's doc with. There is
+// none when the doc would be synced anyway, since a 304 leaves nothing to
+// sync, nor when no doc hash is recorded to mark the module unchanged with.
+func (c *APIClient) docValidator(module string) (DocValidator, string) {
+	if c.state == nil || c.force || c.offline() {
+		return DocValidator{}, ""
+	}
+	hash, ok := c.state.DocHash(module)
+	if !ok {
+		return DocValidator{}, ""
+	}
+	validator, _ := c.state.DocValidator(module)
+	return validator, hash
+}
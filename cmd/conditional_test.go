@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestAPIClient_ConditionalDocFetch(t *testing.T) {
+	const (
+		spec         = `{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`
+		etag         = `"v1"`
+		lastModified = "Mon, 12 Oct 2026 08:00:00 GMT"
+	)
+
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		condition string
+	}{
+		{name: "etag", header: "ETag", value: etag, condition: "If-None-Match"},
+		{name: "last-modified", header: "Last-Modified", value: lastModified, condition: "If-Modified-Since"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu         sync.Mutex
+				conditions []string
+			)
+			docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				conditions = append(conditions, r.Header.Get(tt.condition))
+				mu.Unlock()
+
+				if r.Header.Get(tt.condition) == tt.value {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(tt.header, tt.value)
+				w.Write([]byte(spec))
+			}))
+			defer docServer.Close()
+
+			postman := &mockPostman{collections: `{"collections":[]}`}
+			postmanServer := httptest.NewServer(postman)
+			defer postmanServer.Close()
+
+			state := &State{}
+			newClient := func(opts ...ClientOption) *APIClient {
+				return NewAPIClient("doc-key", "pm-key", append(opts,
+					WithState(state),
+					WithPostmanBaseURL(postmanServer.URL),
+					WithDocURLTemplate(docServer.URL+"/%s"),
+					WithOutput(io.Discard),
+				)...)
+			}
+
+			if err := newClient().ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+				t.Fatalf("first ProcessModule() error = %v", err)
+			}
+			validator, ok := state.DocValidator("Customers")
+			if !ok || validator.URL != docServer.URL+"/Customers" {
+				t.Fatalf("recorded validator = %+v, want one for the doc URL", validator)
+			}
+
+			config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+			results, err := NewSyncOrchestrator(newClient(), config).SyncAllModules(t.Context(), "workspace")
+			if err != nil {
+				t.Fatalf("SyncAllModules() error = %v", err)
+			}
+			if results[0].Status != StatusUnchanged {
+				t.Errorf("result = %+v, want unchanged", results[0])
+			}
+			if len(postman.imported) != 1 {
+				t.Errorf("imported %d times, want only by the first sync", len(postman.imported))
+			}
+
+			if err := newClient(WithForce(true)).ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+				t.Fatalf("forced ProcessModule() error = %v", err)
+			}
+			if len(postman.imported) != 2 {
+				t.Errorf("-force did not sync the unmodified doc")
+			}
+
+			want := []string{"", tt.value, ""}
+			if len(conditions) != len(want) {
+				t.Fatalf("doc requests sent %s %q, want %q", tt.condition, conditions, want)
+			}
+			for i := range want {
+				if conditions[i] != want[i] {
+					t.Errorf("doc request %d sent %s %q, want %q", i, tt.condition, conditions[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDocValidator_OtherURL(t *testing.T) {
+	v := DocValidator{URL: "https://docs.example.com/v1/customers", ETag: `"v1"`}
+
+	req := httptest.NewRequest("GET", "https://docs.example.com/v1/brands", nil)
+	v.setConditions(req)
+	if got := req.Header.Get("If-None-Match"); got != "" {
+		t.Errorf("If-None-Match = %q for another URL, want none", got)
+	}
+
+	req = httptest.NewRequest("GET", v.URL, nil)
+	v.setConditions(req)
+	if got := req.Header.Get("If-None-Match"); got != v.ETag {
+		t.Errorf("If-None-Match = %q, want %q", got, v.ETag)
+	}
+}
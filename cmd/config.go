@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// moduleEntry is one module in a config file. It is written either as the
+// collection name alone or as an object that also sets the module's
+// dependencies and HTTP client settings.
+type moduleEntry struct {
+	Collection string        `yaml:"collection"`
+	DocURL     string        `yaml:"docURL"`
+	DependsOn  []string      `yaml:"dependsOn"`
+	Client     *clientConfig `yaml:"client"`
+	// ImportOptions are passed to Postman's import of the module.
+	ImportOptions *ImportOptions `yaml:"importOptions"`
+	// Workspace is the Postman workspace of the module, when it is not the
+	// one given with -pm-workspace-id.
+	Workspace string `yaml:"workspace"`
+	// FeatureFlag must be on, when set, for the module to be synced.
+	FeatureFlag string `yaml:"featureFlag"`
+	// CollectionUID is the collection the module is updated into, instead
+	// of the collections found by name.
+	CollectionUID string `yaml:"collectionUID"`
+}
+
+type clientConfig struct {
+	Timeout            time.Duration `yaml:"timeout"`
+	ProxyURL           string        `yaml:"proxyURL"`
+	CACertFile         string        `yaml:"caCertFile"`
+	ClientCertFile     string        `yaml:"clientCertFile"`
+	ClientKeyFile      string        `yaml:"clientKeyFile"`
+	InsecureSkipVerify bool          `yaml:"insecureSkipVerify"`
+}
+
+func (e *moduleEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.Collection)
+	}
+
+	type plain moduleEntry
+	return node.Decode((*plain)(e))
+}
+
+// NewModuleConfigFromFile loads the module-to-collection mapping from a YAML
+// or JSON file, e.g.
+//
+//	Customers: Customers Module API
+//	Orders:
+//	  collection: Orders Module API
+//	  docURL: https://${ORDERS_HOST}/v1/internal-docs
+//	  dependsOn: [Customers]
+//	  workspace: 2f8a6c1e-partner
+//	  featureFlag: publish-orders-docs
+//	  collectionUID: 12345-6f1c2d3e-orders
+//	  client:
+//	    timeout: 1m
+func NewModuleConfigFromFile(path string) (*ModuleConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder handles both formats.
+	var entries map[string]moduleEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	return newModuleConfig(path, entries)
+}
+
+// newModuleConfig builds the config of the module entries read from path.
+func newModuleConfig(path string, entries map[string]moduleEntry) (*ModuleConfig, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("config file %s defines no modules", path)
+	}
+
+	config := &ModuleConfig{Modules: make(map[string]string, len(entries))}
+	var errs []error
+	for module, entry := range entries {
+		if strings.TrimSpace(module) == "" {
+			errs = append(errs, errors.New("module name must not be blank"))
+			continue
+		}
+		if strings.TrimSpace(entry.Collection) == "" {
+			errs = append(errs, fmt.Errorf("module %s: collection name must not be blank", module))
+			continue
+		}
+
+		config.Modules[module] = entry.Collection
+
+		if entry.DocURL != "" {
+			if config.DocURLs == nil {
+				config.DocURLs = map[string]string{}
+			}
+			config.DocURLs[module] = entry.DocURL
+		}
+
+		if len(entry.DependsOn) > 0 {
+			if config.DependsOn == nil {
+				config.DependsOn = map[string][]string{}
+			}
+			config.DependsOn[module] = entry.DependsOn
+		}
+
+		if entry.Client != nil {
+			if config.ClientSettings == nil {
+				config.ClientSettings = map[string]ClientSettings{}
+			}
+			config.ClientSettings[module] = ClientSettings(*entry.Client)
+		}
+
+		if entry.Workspace != "" {
+			if config.Workspaces == nil {
+				config.Workspaces = map[string]string{}
+			}
+			config.Workspaces[module] = entry.Workspace
+		}
+
+		if entry.FeatureFlag != "" {
+			if config.FeatureFlags == nil {
+				config.FeatureFlags = map[string]string{}
+			}
+			config.FeatureFlags[module] = entry.FeatureFlag
+		}
+
+		if entry.CollectionUID != "" {
+			if config.CollectionUIDs == nil {
+				config.CollectionUIDs = map[string]string{}
+			}
+			config.CollectionUIDs[module] = entry.CollectionUID
+		}
+
+		if entry.ImportOptions != nil {
+			if err := entry.ImportOptions.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("module %s: %w", module, err))
+				continue
+			}
+			if config.ImportOptions == nil {
+				config.ImportOptions = map[string]ImportOptions{}
+			}
+			config.ImportOptions[module] = *entry.ImportOptions
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if _, err := config.dependencyOrder(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewModuleConfigFromFile(t *testing.T) {
+	want := map[string]string{
+		"Customers": "Customers Module API",
+		"Orders":    "Orders Module API",
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name:    "yaml",
+			file:    "modules.yaml",
+			content: "Customers: Customers Module API\nOrders: Orders Module API\n",
+		},
+		{
+			name:    "json",
+			file:    "modules.json",
+			content: `{"Customers": "Customers Module API", "Orders": "Orders Module API"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewModuleConfigFromFile(writeConfigFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("NewModuleConfigFromFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(config.Modules, want) {
+				t.Errorf("Modules = %v, want %v", config.Modules, want)
+			}
+		})
+	}
+}
+
+func TestNewModuleConfigFromFile_ModuleSettings(t *testing.T) {
+	path := writeConfigFile(t, "modules.yaml", `
+Customers: Customers Module API
+Orders:
+  collection: Orders Module API
+  docURL: https://${ORDERS_HOST}/docs
+  dependsOn: [Customers]
+  workspace: ws-partner
+  featureFlag: publish-orders-docs
+  collectionUID: 1-orders
+  client:
+    timeout: 1m
+    proxyURL: http://proxy.internal:3128
+  importOptions:
+    folderStrategy: Tags
+    tags: [commerce]
+`)
+
+	config, err := NewModuleConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("NewModuleConfigFromFile() error = %v", err)
+	}
+
+	if config.Modules["Orders"] != "Orders Module API" {
+		t.Errorf("Orders collection = %q", config.Modules["Orders"])
+	}
+	if !reflect.DeepEqual(config.DocURLs, map[string]string{"Orders": "https://${ORDERS_HOST}/docs"}) {
+		t.Errorf("DocURLs = %v", config.DocURLs)
+	}
+	if !reflect.DeepEqual(config.DependsOn, map[string][]string{"Orders": {"Customers"}}) {
+		t.Errorf("DependsOn = %v", config.DependsOn)
+	}
+	if !reflect.DeepEqual(config.Workspaces, map[string]string{"Orders": "ws-partner"}) {
+		t.Errorf("Workspaces = %v", config.Workspaces)
+	}
+	if !reflect.DeepEqual(config.FeatureFlags, map[string]string{"Orders": "publish-orders-docs"}) {
+		t.Errorf("FeatureFlags = %v", config.FeatureFlags)
+	}
+	if !reflect.DeepEqual(config.CollectionUIDs, map[string]string{"Orders": "1-orders"}) {
+		t.Errorf("CollectionUIDs = %v", config.CollectionUIDs)
+	}
+	wantSettings := map[string]ClientSettings{
+		"Orders": {Timeout: time.Minute, ProxyURL: "http://proxy.internal:3128"},
+	}
+	if !reflect.DeepEqual(config.ClientSettings, wantSettings) {
+		t.Errorf("ClientSettings = %+v, want %+v", config.ClientSettings, wantSettings)
+	}
+	wantOptions := map[string]ImportOptions{"Orders": {FolderStrategy: "Tags", Tags: []string{"commerce"}}}
+	if !reflect.DeepEqual(config.ImportOptions, wantOptions) {
+		t.Errorf("ImportOptions = %+v, want %+v", config.ImportOptions, wantOptions)
+	}
+}
+
+func TestNewModuleConfigFromFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{name: "empty file", content: "", errContains: "defines no modules"},
+		{name: "empty mapping", content: "{}", errContains: "defines no modules"},
+		{name: "blank collection", content: "Customers: \"  \"\n", errContains: "module Customers: collection name must not be blank"},
+		{name: "blank module", content: "\"\": Customers Module API\n", errContains: "module name must not be blank"},
+		{name: "not a mapping", content: "- Customers\n", errContains: "parsing config file"},
+		{name: "invalid folder strategy", content: "Orders:\n  collection: Orders Module API\n  importOptions:\n    folderStrategy: Domains\n", errContains: `module Orders: invalid folderStrategy "Domains"`},
+		{name: "unknown dependency", content: "Orders:\n  collection: Orders Module API\n  dependsOn: [Billing]\n", errContains: "unknown module Billing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewModuleConfigFromFile(writeConfigFile(t, "modules.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("NewModuleConfigFromFile() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestNewModuleConfigFromFile_Missing(t *testing.T) {
+	if _, err := NewModuleConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("NewModuleConfigFromFile() error = nil, want read error")
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Credentials are the keys and workspace the tool reaches the doc API and
+// Postman with.
+type Credentials struct {
+	DocAPIKey          string `yaml:"docApiKey"`
+	PostmanAPIKey      string `yaml:"pmApiKey"`
+	PostmanWorkspaceID string `yaml:"pmWorkspaceId"`
+}
+
+// CredentialProvider supplies the credentials of a run. Fields a provider
+// cannot supply are left empty and reported as missing by GetParams.
+type CredentialProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// Sources selectable with -credential-source.
+const (
+	CredentialSourceFlag          = "flag"
+	CredentialSourceEnv           = "env"
+	CredentialSourceFile          = "file"
+	CredentialSourceSecretManager = "secret-manager"
+)
+
+var validCredentialSources = []string{CredentialSourceFlag, CredentialSourceEnv, CredentialSourceFile, CredentialSourceSecretManager}
+
+// NewCredentialProvider returns the provider of one of validCredentialSources.
+// flags holds the values of the credential flags and ref the file or secret
+// the provider reads from, when it needs one.
+func NewCredentialProvider(source string, flags Credentials, ref string) (CredentialProvider, error) {
+	switch source {
+	case CredentialSourceFlag:
+		return flagCredentials{flags}, nil
+	case CredentialSourceEnv:
+		return envCredentials{}, nil
+	case CredentialSourceFile:
+		if ref == "" {
+			return nil, errors.New("credential-source file requires -credentials-file")
+		}
+		return fileCredentials{ref}, nil
+	case CredentialSourceSecretManager:
+		if ref == "" {
+			return nil, errors.New("credential-source secret-manager requires -credentials-secret")
+		}
+		return secretManagerCredentials{ref}, nil
+	default:
+		return nil, fmt.Errorf("invalid credential-source %q, must be one of: %s", source, strings.Join(validCredentialSources, ", "))
+	}
+}
+
+// flagCredentials takes the credentials from the command line flags, whose
+// defaults come from the environment, and falls back to the Postman CLI
+// login for the Postman key and workspace.
+type flagCredentials struct {
+	flags Credentials
+}
+
+func (p flagCredentials) Credentials() (Credentials, error) {
+	creds := p.flags
+	if creds.PostmanAPIKey != "" && creds.PostmanWorkspaceID != "" {
+		return creds, nil
+	}
+
+	apiKey, workspaceID, err := loadPostmanCredentials()
+	if err != nil {
+		return Credentials{}, err
+	}
+	if creds.PostmanAPIKey == "" {
+		creds.PostmanAPIKey = apiKey
+	}
+	if creds.PostmanWorkspaceID == "" {
+		creds.PostmanWorkspaceID = workspaceID
+	}
+	return creds, nil
+}
+
+// envCredentials takes the credentials from DOC_API_KEY, PM_API_KEY and
+// PM_WORKSPACE_ID only.
+type envCredentials struct{}
+
+func (envCredentials) Credentials() (Credentials, error) {
+	return Credentials{
+		DocAPIKey:          os.Getenv("DOC_API_KEY"),
+		PostmanAPIKey:      os.Getenv("PM_API_KEY"),
+		PostmanWorkspaceID: os.Getenv("PM_WORKSPACE_ID"),
+	}, nil
+}
+
+// fileCredentials reads the credentials from a YAML or JSON file, e.g.
+//
+//	docApiKey: xxx
+//	pmApiKey: PMAK-xxx
+//	pmWorkspaceId: xxx
+type fileCredentials struct {
+	path string
+}
+
+func (p fileCredentials) Credentials() (Credentials, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	var creds Credentials
+	if err := yaml.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("parsing credentials file %s: %w", p.path, err)
+	}
+	return creds, nil
+}
+
+// secretManagerCredentials is the place for reading the credentials from a
+// secret manager. No secret manager is supported yet, so it always fails.
+type secretManagerCredentials struct {
+	secret string
+}
+
+func (p secretManagerCredentials) Credentials() (Credentials, error) {
+	return Credentials{}, fmt.Errorf("reading secret %q: the secret-manager credential source is not implemented yet", p.secret)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var wantCredentials = Credentials{DocAPIKey: "doc-key", PostmanAPIKey: "pm-key", PostmanWorkspaceID: "workspace"}
+
+func TestNewCredentialProvider(t *testing.T) {
+	tests := []struct {
+		source  string
+		ref     string
+		want    CredentialProvider
+		wantErr string
+	}{
+		{source: "flag", want: flagCredentials{wantCredentials}},
+		{source: "env", want: envCredentials{}},
+		{source: "file", ref: "creds.yaml", want: fileCredentials{"creds.yaml"}},
+		{source: "file", wantErr: "requires -credentials-file"},
+		{source: "secret-manager", ref: "sync/creds", want: secretManagerCredentials{"sync/creds"}},
+		{source: "secret-manager", wantErr: "requires -credentials-secret"},
+		{source: "vault", wantErr: `invalid credential-source "vault"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source+" "+tt.ref, func(t *testing.T) {
+			got, err := NewCredentialProvider(tt.source, wantCredentials, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewCredentialProvider() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCredentialProvider() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewCredentialProvider() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writePostmanRC(t, home, `{"login":{"_profiles":[{"postmanApiKey":"PMAK-from-file","workspaceId":"workspace-from-file"}]}}`)
+
+	got, err := flagCredentials{wantCredentials}.Credentials()
+	if err != nil || got != wantCredentials {
+		t.Errorf("Credentials() = %+v, %v, want the flags %+v", got, err, wantCredentials)
+	}
+
+	got, err = flagCredentials{Credentials{DocAPIKey: "doc-key"}}.Credentials()
+	want := Credentials{DocAPIKey: "doc-key", PostmanAPIKey: "PMAK-from-file", PostmanWorkspaceID: "workspace-from-file"}
+	if err != nil || got != want {
+		t.Errorf("Credentials() = %+v, %v, want the Postman CLI login %+v", got, err, want)
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("DOC_API_KEY", "doc-key")
+	t.Setenv("PM_API_KEY", "pm-key")
+	t.Setenv("PM_WORKSPACE_ID", "workspace")
+
+	got, err := envCredentials{}.Credentials()
+	if err != nil || got != wantCredentials {
+		t.Errorf("Credentials() = %+v, %v, want %+v", got, err, wantCredentials)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "creds.json")
+	if err := os.WriteFile(path, []byte(`{"docApiKey":"doc-key","pmApiKey":"pm-key","pmWorkspaceId":"workspace"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fileCredentials{path}.Credentials()
+	if err != nil || got != wantCredentials {
+		t.Errorf("Credentials() = %+v, %v, want %+v", got, err, wantCredentials)
+	}
+
+	if _, err := (fileCredentials{filepath.Join(dir, "missing.yaml")}).Credentials(); err == nil {
+		t.Error("Credentials() error = nil for a missing file")
+	}
+}
+
+func TestSecretManagerCredentials(t *testing.T) {
+	if _, err := (secretManagerCredentials{"sync/creds"}).Credentials(); err == nil || !strings.Contains(err.Error(), "not implemented") {
+		t.Errorf("Credentials() error = %v, want not implemented", err)
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import "context"
+
+// WithMaxConcurrentDeletes caps how many collection deletes may be in flight
+// at once across all modules, independently of how many modules are synced in
+// parallel. Clients derived with WithClientSettings share the cap. Zero leaves
+// deletes uncapped.
+func WithMaxConcurrentDeletes(limit int) ClientOption {
+	return func(c *APIClient) {
+		c.deleteSlots = nil
+		if limit > 0 {
+			c.deleteSlots = make(chan struct{}, limit)
+		}
+	}
+}
+
+// acquireDelete waits for a delete slot and returns the function releasing it.
+func (c *APIClient) acquireDelete(ctx context.Context) (func(), error) {
+	if c.deleteSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.deleteSlots <- struct{}{}:
+		return func() { <-c.deleteSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
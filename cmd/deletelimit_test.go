@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrentDeletes(t *testing.T) {
+	const limit = 2
+
+	var inFlight, peak, deleted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		deleted.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithMaxConcurrentDeletes(limit))
+	client.postmanBaseURL = server.URL
+
+	// Every module deletes through its own client, as with per-module
+	// client settings, and all of them share the cap.
+	var wg sync.WaitGroup
+	for m := range 4 {
+		processor, err := client.WithClientSettings(ClientSettings{})
+		if err != nil {
+			t.Fatalf("WithClientSettings() error = %v", err)
+		}
+		module := processor.(*APIClient)
+
+		var refs []CollectionRef
+		for i := range 3 {
+			id := fmt.Sprintf("m%d-c%d", m, i)
+			refs = append(refs, CollectionRef{ID: id, UID: "1-" + id})
+		}
+
+		wg.Go(func() {
+			if err := module.DeleteCollections(t.Context(), refs); err != nil {
+				t.Errorf("DeleteCollections() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := deleted.Load(); got != 12 {
+		t.Errorf("deleted %d collections, want 12", got)
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("%d deletes in flight at once, want at most %d", got, limit)
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// deleteModule deletes the collections named like the module's, for
+// ModeDeleteOnly. Unlike a sync, a failed delete fails the module. The
+// module's doc is not fetched, so collections are matched by name even when
+// a collection key field is set. The state and id map forget the module once
+// its collections are gone.
+func (c *APIClient) deleteModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	start := time.Now()
+	refs, err := c.getCollectionsByName(ctx, collectionName, workspaceID)
+	c.timePhase(ctx, phaseDelete, start)
+	if err != nil {
+		c.log.ErrorContext(ctx, "checking existing collections failed", "error", err)
+		return err
+	}
+	if len(refs) == 0 {
+		c.log.InfoContext(ctx, "no collection to delete", "name", collectionName)
+	}
+
+	if err := c.DeleteCollections(ctx, refs); err != nil {
+		c.log.ErrorContext(ctx, "deleting collections failed", "error", err)
+		return err
+	}
+	if c.state != nil && !c.dryRun {
+		c.state.Forget(moduleName)
+	}
+	if c.idMap != nil && !c.dryRun {
+		c.idMap.Forget(moduleName)
+	}
+
+	c.log.InfoContext(ctx, "processed module")
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestAPIClient_ProcessModuleDeleteOnly(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("doc fetched in delete-only mode: %s", r.URL)
+	}))
+	defer docServer.Close()
+
+	postman := &mockPostman{collections: `{"collections":[
+		{"id":"c1","uid":"1-c1","name":"Customers Module API"},
+		{"id":"b1","uid":"1-b1","name":"Brands Module API"},
+		{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+	]}`}
+	postmanServer := httptest.NewServer(postman)
+	defer postmanServer.Close()
+
+	state := &State{}
+	state.SetCollectionID("Customers", "1-c1")
+	state.SetDocHash("Customers", "hash")
+	ids := &IDMap{}
+	ids.SetCollectionID("Customers", "1-c1")
+
+	client := NewAPIClient("", "pm-key",
+		WithMode(ModeDeleteOnly),
+		WithState(state),
+		WithIDMap(ids),
+		WithPostmanBaseURL(postmanServer.URL),
+		WithDocURLTemplate(docServer.URL+"/%s"),
+		WithOutput(io.Discard),
+	)
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	slices.Sort(postman.deleted)
+	if want := []string{"c1", "c2"}; !slices.Equal(postman.deleted, want) {
+		t.Errorf("deleted %v, want %v", postman.deleted, want)
+	}
+	if len(postman.imported) != 0 {
+		t.Errorf("imported %d collections in delete-only mode", len(postman.imported))
+	}
+	if _, ok := state.CollectionID("Customers"); ok {
+		t.Error("state still records the deleted collection")
+	}
+	if _, ok := state.DocHash("Customers"); ok {
+		t.Error("state still records the doc hash, so the next sync would skip the module")
+	}
+	if _, ok := ids.CollectionID("Customers"); ok {
+		t.Error("id map still records the deleted collection")
+	}
+}
+
+func TestAPIClient_ProcessModuleDeleteOnlyFailure(t *testing.T) {
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			http.Error(w, `{"error":{"name":"forbiddenError"}}`, http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+	}))
+	defer postman.Close()
+
+	state := &State{}
+	state.SetCollectionID("Customers", "1-c1")
+	client := NewAPIClient("", "pm-key", WithMode(ModeDeleteOnly), WithState(state), WithPostmanBaseURL(postman.URL), WithOutput(io.Discard))
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err == nil {
+		t.Error("ProcessModule() error = nil, want the failed delete")
+	}
+	if _, ok := state.CollectionID("Customers"); !ok {
+		t.Error("state forgot a collection that was not deleted")
+	}
+}
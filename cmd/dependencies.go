@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// dependencyOrder returns the configured modules sorted so that every module
+// comes after the modules it depends on. Ties are broken alphabetically.
+// It fails on dependencies on unknown modules and on cycles.
+func (c *ModuleConfig) dependencyOrder() ([]string, error) {
+	indegree := make(map[string]int, len(c.Modules))
+	dependents := map[string][]string{}
+
+	for module := range c.Modules {
+		indegree[module] += 0
+		for _, dep := range c.DependsOn[module] {
+			if _, ok := c.Modules[dep]; !ok {
+				return nil, fmt.Errorf("module %s depends on unknown module %s", module, dep)
+			}
+			indegree[module]++
+			dependents[dep] = append(dependents[dep], module)
+		}
+	}
+
+	var ready, order []string
+	for module, n := range indegree {
+		if n == 0 {
+			ready = append(ready, module)
+		}
+	}
+
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		module := ready[0]
+		ready = ready[1:]
+		order = append(order, module)
+
+		for _, dependent := range dependents[module] {
+			indegree[dependent]--
+			if indegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(c.Modules) {
+		var cyclic []string
+		for module, n := range indegree {
+			if n > 0 {
+				cyclic = append(cyclic, module)
+			}
+		}
+		slices.Sort(cyclic)
+		return nil, fmt.Errorf("dependency cycle between modules: %v", cyclic)
+	}
+
+	return order, nil
+}
+
+// concurrency returns the configured concurrency, or the default when unset.
+func (c *ModuleConfig) concurrency() int {
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return defaultConcurrency
+}
+
+// runModules runs fn for every module of config through the orchestrator's
+// scheduler, by default at most config.Concurrency at a time. A module starts
+// as soon as the modules it depends on have finished, and is skipped when one
+// of them failed; of the modules ready to start, the first in alphabetical
+// order goes first. It returns a result per module, sorted by module name,
+// and all failures joined, each wrapped with the module and collection name.
+// Once ctx is done no further module is started and the context's error is
+// returned, unless a fail-fast sync cancelled it.
+//
+// A module whose fn returns ErrUnchanged counts as StatusUnchanged, which
+// dependents treat like success. One whose error wraps ErrFeatureDisabled or
+// ErrCircuitOpen is skipped, together with its dependents; only a disabled
+// feature flag does so without failing the run. A panic in fn fails only its
+// module. done, when not nil, is called
+// with every result as soon as it is known.
+func (s *SyncOrchestrator) runModules(ctx context.Context, config *ModuleConfig, fn func(moduleName string) error, done func(ModuleResult)) ([]ModuleResult, error) {
+	order, err := config.dependencyOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu        sync.Mutex
+		results   = make(map[string]ModuleResult, len(order))
+		errs      []error
+		scheduler = s.schedulerFor(config)
+	)
+
+	if done == nil {
+		done = func(ModuleResult) {}
+	}
+
+	record := func(mod string, status ModuleStatus, duration time.Duration, err error) {
+		result := ModuleResult{
+			Module:     mod,
+			Collection: config.Modules[mod],
+			Status:     status,
+			Duration:   duration,
+			Err:        err,
+			Phases:     s.phaseTimings(mod),
+		}
+		if status == StatusSucceeded {
+			result.CollectionUID = s.createdCollection(mod)
+		}
+
+		mu.Lock()
+		results[mod] = result
+		if err != nil && !errors.Is(err, ErrFeatureDisabled) {
+			errs = append(errs, fmt.Errorf("module %s (collection %q): %w", mod, config.Modules[mod], err))
+		}
+		mu.Unlock()
+
+		done(result)
+	}
+
+	resultOf := func(mod string) ModuleResult {
+		mu.Lock()
+		defer mu.Unlock()
+		return results[mod]
+	}
+
+	// Every module sends its name to ended once it has a result, which makes
+	// the modules depending on it ready once all their dependencies ended.
+	var ready []string
+	waiting := make(map[string]int, len(order))
+	dependents := map[string][]string{}
+	for _, mod := range order {
+		waiting[mod] = len(config.DependsOn[mod])
+		if waiting[mod] == 0 {
+			ready = append(ready, mod)
+		}
+		for _, dep := range config.DependsOn[mod] {
+			dependents[dep] = append(dependents[dep], mod)
+		}
+	}
+	ended := make(chan string, len(order))
+	unfinished := len(order)
+	release := func(mod string) {
+		unfinished--
+		for _, dependent := range dependents[mod] {
+			waiting[dependent]--
+			if waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	for unfinished > 0 {
+		// Release the modules that ended meanwhile before choosing the next
+		// one. A sequential scheduler has finished the last module by now, so
+		// with one module at a time the order does not depend on timing.
+		for drained := false; !drained; {
+			select {
+			case mod := <-ended:
+				release(mod)
+			default:
+				drained = true
+			}
+		}
+		if unfinished == 0 {
+			break
+		}
+		if len(ready) == 0 {
+			release(<-ended)
+			continue
+		}
+
+		slices.Sort(ready)
+		mod := ready[0]
+		ready = ready[1:]
+
+		var skip error
+		for _, dep := range config.DependsOn[mod] {
+			result := resultOf(dep)
+			if result.Status == StatusSucceeded || result.Status == StatusUnchanged {
+				continue
+			}
+			if cause := skipCause(result.Err); cause != nil {
+				skip = fmt.Errorf("skipped: dependency %s: %w", dep, cause)
+			} else {
+				skip = fmt.Errorf("skipped: dependency %s failed", dep)
+			}
+			break
+		}
+		if skip != nil {
+			record(mod, StatusSkipped, 0, skip)
+			ended <- mod
+			continue
+		}
+
+		err := scheduler.Start(ctx, func() {
+			defer func() { ended <- mod }()
+
+			start := time.Now()
+			err := s.recoverModule(ctx, mod, fn)
+			status := StatusSucceeded
+			switch {
+			case errors.Is(err, ErrUnchanged):
+				status, err = StatusUnchanged, nil
+			case skipCause(err) != nil:
+				status = StatusSkipped
+			case err != nil:
+				status = StatusFailed
+			}
+			record(mod, status, time.Since(start), err)
+		})
+		if err != nil {
+			break
+		}
+	}
+
+	scheduler.Wait()
+
+	sorted := make([]ModuleResult, 0, len(order))
+	for _, mod := range slices.Sorted(maps.Keys(config.Modules)) {
+		result, ok := results[mod]
+		if !ok {
+			result = ModuleResult{Module: mod, Collection: config.Modules[mod], Status: StatusSkipped, Err: context.Cause(ctx)}
+			done(result)
+		}
+		sorted = append(sorted, result)
+	}
+
+	// Failing fast is no shutdown: the run fails with its module errors.
+	if err := ctx.Err(); err != nil && !errors.Is(context.Cause(ctx), errFailedFast) {
+		return sorted, err
+	}
+
+	return sorted, errors.Join(errs...)
+}
+
+// skipCause returns ErrFeatureDisabled or ErrCircuitOpen when err wraps one
+// of them, which skips a module, and nil otherwise.
+func skipCause(err error) error {
+	for _, cause := range []error{ErrFeatureDisabled, ErrCircuitOpen} {
+		if errors.Is(err, cause) {
+			return cause
+		}
+	}
+	return nil
+}
+
+// recoverModule runs fn for the module and turns a panic into an error of
+// that module, so it does not take the other modules down with it.
+func (s *SyncOrchestrator) recoverModule(ctx context.Context, mod string, fn func(moduleName string) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.ErrorContext(withModule(ctx, mod), "module panicked", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(mod)
+}
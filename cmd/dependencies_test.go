@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingProcessor records when each module starts and finishes.
+type recordingProcessor struct {
+	mu     sync.Mutex
+	events []string
+	fail   map[string]bool
+	panics map[string]bool
+	// delay is how long each module takes.
+	delay time.Duration
+}
+
+func (p *recordingProcessor) record(event string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	p.record("start " + moduleName)
+	defer p.record("end " + moduleName)
+
+	time.Sleep(p.delay)
+	if p.fail[moduleName] {
+		return errors.New(moduleName + " failed")
+	}
+	if p.panics[moduleName] {
+		var collection map[string]any
+		_ = collection["id"].(string)
+	}
+	return nil
+}
+
+func (p *recordingProcessor) index(event string) int {
+	return slices.Index(p.events, event)
+}
+
+func TestModuleConfig_DependencyOrder(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{"Home": "", "Customers": "", "Brands": "", "Classes": ""},
+		DependsOn: map[string][]string{
+			"Home":      {"Customers", "Classes"},
+			"Customers": {"Brands"},
+		},
+	}
+
+	order, err := config.dependencyOrder()
+	if err != nil {
+		t.Fatalf("dependencyOrder() error = %v", err)
+	}
+
+	want := []string{"Brands", "Classes", "Customers", "Home"}
+	if !slices.Equal(order, want) {
+		t.Errorf("dependencyOrder() = %v, want %v", order, want)
+	}
+}
+
+func TestModuleConfig_DependencyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		dependsOn   map[string][]string
+		errContains string
+	}{
+		{
+			name:        "cycle",
+			dependsOn:   map[string][]string{"Home": {"Customers"}, "Customers": {"Brands"}, "Brands": {"Home"}},
+			errContains: "dependency cycle between modules: [Brands Customers Home]",
+		},
+		{
+			name:        "self dependency",
+			dependsOn:   map[string][]string{"Home": {"Home"}},
+			errContains: "dependency cycle",
+		},
+		{
+			name:        "unknown module",
+			dependsOn:   map[string][]string{"Home": {"Payments"}},
+			errContains: "module Home depends on unknown module Payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ModuleConfig{
+				Modules:   map[string]string{"Home": "", "Customers": "", "Brands": ""},
+				DependsOn: tt.dependsOn,
+			}
+
+			_, err := config.dependencyOrder()
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("dependencyOrder() error = %v, want error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestSyncAllModules_RespectsDependencies(t *testing.T) {
+	processor := &recordingProcessor{}
+	config := &ModuleConfig{
+		Modules: map[string]string{"Home": "", "Customers": "", "Brands": "", "Classes": ""},
+		DependsOn: map[string][]string{
+			"Home":      {"Customers", "Classes"},
+			"Customers": {"Brands"},
+		},
+	}
+
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	for module, deps := range config.DependsOn {
+		for _, dep := range deps {
+			if processor.index("end "+dep) > processor.index("start "+module) {
+				t.Errorf("module %s started before its dependency %s finished: %v", module, dep, processor.events)
+			}
+		}
+	}
+}
+
+func TestSyncAllModules_SkipsDependentsOfFailedModule(t *testing.T) {
+	processor := &recordingProcessor{fail: map[string]bool{"Brands": true}}
+	config := &ModuleConfig{
+		Modules:   map[string]string{"Customers": "", "Brands": "", "Classes": ""},
+		DependsOn: map[string][]string{"Customers": {"Brands"}},
+	}
+
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err == nil {
+		t.Fatal("SyncAllModules() error = nil, want error")
+	}
+
+	if processor.index("start Customers") != -1 {
+		t.Errorf("Customers should be skipped when Brands fails: %v", processor.events)
+	}
+	if processor.index("start Classes") == -1 {
+		t.Errorf("Classes does not depend on Brands and should still run: %v", processor.events)
+	}
+}
+
+func TestSyncAllModules_RecoversPanic(t *testing.T) {
+	processor := &recordingProcessor{panics: map[string]bool{"Brands": true}}
+	config := &ModuleConfig{
+		Modules:   map[string]string{"Customers": "", "Brands": "", "Classes": ""},
+		DependsOn: map[string][]string{"Customers": {"Brands"}},
+	}
+
+	orchestrator := NewSyncOrchestrator(processor, config)
+	orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+	results, err := orchestrator.SyncAllModules(t.Context(), "workspace")
+	if err == nil || !strings.Contains(err.Error(), "module Brands") || !strings.Contains(err.Error(), "panic: interface conversion") {
+		t.Fatalf("SyncAllModules() error = %v, want the panic attributed to Brands", err)
+	}
+
+	statuses := map[string]ModuleStatus{}
+	for _, result := range results {
+		statuses[result.Module] = result.Status
+	}
+	want := map[string]ModuleStatus{"Brands": StatusFailed, "Customers": StatusSkipped, "Classes": StatusSucceeded}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+}
+
+func TestSyncAllModules_ReportsCycle(t *testing.T) {
+	processor := &recordingProcessor{}
+	config := &ModuleConfig{
+		Modules:   map[string]string{"Customers": "", "Brands": ""},
+		DependsOn: map[string][]string{"Customers": {"Brands"}, "Brands": {"Customers"}},
+	}
+
+	_, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace")
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("SyncAllModules() error = %v, want cycle error", err)
+	}
+	if len(processor.events) != 0 {
+		t.Errorf("no module should run when dependencies are cyclic: %v", processor.events)
+	}
+}
+
+// concurrencyProcessor tracks the highest number of modules processed at once.
+type concurrencyProcessor struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (p *concurrencyProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	p.mu.Lock()
+	p.inFlight++
+	p.peak = max(p.peak, p.inFlight)
+	p.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return nil
+}
+
+func TestSyncAllModules_LimitsConcurrency(t *testing.T) {
+	modules := map[string]string{}
+	for _, name := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		modules[name] = name + " Module API"
+	}
+
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			processor := &concurrencyProcessor{}
+			config := &ModuleConfig{Modules: modules, Concurrency: concurrency}
+
+			if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+				t.Fatalf("SyncAllModules() error = %v", err)
+			}
+			if processor.peak > concurrency {
+				t.Errorf("peak concurrency = %d, want at most %d", processor.peak, concurrency)
+			}
+		})
+	}
+}
+
+func TestSyncAllModules_SequentialWithConcurrencyOne(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:     map[string]string{"Home": "", "Customers": "", "Brands": "", "Classes": "", "Zones": ""},
+		DependsOn:   map[string][]string{"Customers": {"Home"}},
+		Concurrency: 1,
+	}
+	// Customers sorts before Home, which it depends on, and Zones after it:
+	// Customers still goes before Zones, however long Home takes.
+	want := []string{
+		"start Brands", "end Brands",
+		"start Classes", "end Classes",
+		"start Home", "end Home",
+		"start Customers", "end Customers",
+		"start Zones", "end Zones",
+	}
+
+	// Map iteration order differs between runs, the module order must not.
+	for run := range 5 {
+		processor := &recordingProcessor{delay: 10 * time.Millisecond}
+		if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+			t.Fatalf("SyncAllModules() error = %v", err)
+		}
+		if !slices.Equal(processor.events, want) {
+			t.Fatalf("run %d: events = %v, want %v", run, processor.events, want)
+		}
+	}
+}
+
+// blockingProcessor holds Base until Extra has started.
+type blockingProcessor struct {
+	extraStarted chan struct{}
+}
+
+func (p *blockingProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	switch moduleName {
+	case "Base":
+		select {
+		case <-p.extraStarted:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("Extra did not start while Base was running")
+		}
+	case "Extra":
+		close(p.extraStarted)
+	}
+	return nil
+}
+
+func TestSyncAllModules_StartsModulesOnceTheirOwnDependenciesFinish(t *testing.T) {
+	// Dependent comes before Extra in dependency order, but only Dependent
+	// has to wait for Base.
+	config := &ModuleConfig{
+		Modules:   map[string]string{"Base": "", "Dependent": "", "Extra": ""},
+		DependsOn: map[string][]string{"Dependent": {"Base"}},
+	}
+
+	orchestrator := NewSyncOrchestrator(&blockingProcessor{extraStarted: make(chan struct{})}, config)
+	results, err := orchestrator.SyncAllModules(t.Context(), "workspace")
+	if err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+	for _, result := range results {
+		if result.Status != StatusSucceeded {
+			t.Errorf("%s status = %s (%v), want succeeded", result.Module, result.Status, result.Err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// diagnoseTimeout bounds each connectivity check.
+const diagnoseTimeout = 5 * time.Second
+
+// DiagnosticCheck is the outcome of one connectivity check of a host: dns,
+// tcp or tls. A check is skipped when an earlier one of the host failed.
+type DiagnosticCheck struct {
+	Host    string
+	Check   string
+	OK      bool
+	Skipped bool
+	Detail  string
+}
+
+// IsNetworkError reports whether err, or any error it wraps or joins, is a
+// failure to resolve, reach or securely connect to a host, rather than an
+// error response or a cancellation.
+func IsNetworkError(err error) bool {
+	var (
+		opErr     *net.OpError
+		dnsErr    *net.DNSError
+		certErr   *tls.CertificateVerificationError
+		recordErr tls.RecordHeaderError
+	)
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.As(err, &certErr) || errors.As(err, &recordErr)
+}
+
+// Diagnose resolves, connects to and, for https, completes a TLS handshake
+// with every doc API host and the Postman API host, so a failed run can be
+// told apart as a DNS, firewall or TLS problem.
+func (c *APIClient) Diagnose(ctx context.Context, config *ModuleConfig) []DiagnosticCheck {
+	targets := map[string]*url.URL{}
+	rawURLs := []string{c.postmanBaseURL}
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		if docURL, err := c.moduleDocURL(module); err == nil {
+			rawURLs = append(rawURLs, docURL)
+		}
+	}
+	for _, raw := range rawURLs {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			targets[hostPort(u)] = u
+		}
+	}
+
+	var checks []DiagnosticCheck
+	for _, address := range slices.Sorted(maps.Keys(targets)) {
+		checks = append(checks, diagnoseHost(ctx, address, targets[address])...)
+	}
+	return checks
+}
+
+// hostPort returns the host of u with the port implied by its scheme.
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	if u.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func diagnoseHost(ctx context.Context, address string, u *url.URL) []DiagnosticCheck {
+	checks := []DiagnosticCheck{{Host: address, Check: "dns"}, {Host: address, Check: "tcp"}}
+	if u.Scheme == "https" {
+		checks = append(checks, DiagnosticCheck{Host: address, Check: "tls"})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	steps := []func() (string, error){
+		func() (string, error) {
+			addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+			return fmt.Sprint(addrs), err
+		},
+		func() (string, error) {
+			var err error
+			conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address)
+			if err != nil {
+				return "", err
+			}
+			return "connected to " + conn.RemoteAddr().String(), nil
+		},
+		func() (string, error) {
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return "", err
+			}
+			return tls.VersionName(tlsConn.ConnectionState().Version), nil
+		},
+	}
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	failed := false
+	for i := range checks {
+		if failed {
+			checks[i].Skipped = true
+			continue
+		}
+		detail, err := steps[i]()
+		if err != nil {
+			checks[i].Detail = err.Error()
+			failed = true
+			continue
+		}
+		checks[i].OK = true
+		checks[i].Detail = detail
+	}
+	return checks
+}
+
+// WriteDiagnosis writes the connectivity checks as a table.
+func WriteDiagnosis(w io.Writer, checks []DiagnosticCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tCHECK\tRESULT\tDETAIL")
+
+	for _, check := range checks {
+		result := "failed"
+		switch {
+		case check.Skipped:
+			result = "skipped"
+		case check.OK:
+			result = "ok"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Host, check.Check, result, check.Detail)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_DiagnoseConnectionFailure(t *testing.T) {
+	// Reserve a port, then close it so connecting to it is refused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := listener.Addr().String()
+	listener.Close()
+
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithRetry(0, 0))
+	client.postmanBaseURL = "http://" + closed
+	client.docURL = func(string) string { return docServer.URL }
+
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+	err = client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if !IsNetworkError(err) {
+		t.Fatalf("ProcessModule() error = %v, want a network error", err)
+	}
+
+	checks := client.Diagnose(t.Context(), config)
+
+	var b strings.Builder
+	if err := WriteDiagnosis(&b, checks); err != nil {
+		t.Fatalf("WriteDiagnosis() error = %v", err)
+	}
+	output := b.String()
+
+	docHost := strings.TrimPrefix(docServer.URL, "http://")
+	for _, want := range []string{
+		closed + " dns ok",
+		closed + " tcp failed",
+		docHost + " dns ok",
+		docHost + " tcp ok",
+	} {
+		found := false
+		for line := range strings.Lines(output) {
+			if strings.HasPrefix(strings.Join(strings.Fields(line), " "), want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("diagnosis has no line starting %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestAPIClient_DiagnoseSkipsChecksAfterFailure(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = "https://postman.invalid"
+
+	checks := client.Diagnose(t.Context(), &ModuleConfig{})
+	if len(checks) != 3 {
+		t.Fatalf("got %d checks, want dns, tcp and tls: %+v", len(checks), checks)
+	}
+	if checks[0].OK || checks[0].Check != "dns" {
+		t.Errorf("dns check = %+v, want a failure", checks[0])
+	}
+	if !checks[1].Skipped || !checks[2].Skipped {
+		t.Errorf("tcp and tls checks = %+v, want them skipped", checks[1:])
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	if IsNetworkError(fmt.Errorf("failed to delete collection: 500")) {
+		t.Error("IsNetworkError() = true for an error response")
+	}
+	if !IsNetworkError(fmt.Errorf("making request: %w", &net.OpError{Op: "dial", Err: fmt.Errorf("refused")})) {
+		t.Error("IsNetworkError() = false for a dial error")
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Doc auth types select how the doc API credential is sent.
+const (
+	// DocAuthAPIKey sends the credential as the X-API-Key header.
+	DocAuthAPIKey = "apikey"
+	// DocAuthBearer sends it as a bearer token.
+	DocAuthBearer = "bearer"
+	// DocAuthBasic sends a user:pass credential with basic auth.
+	DocAuthBasic = "basic"
+)
+
+var validDocAuthTypes = []string{DocAuthAPIKey, DocAuthBearer, DocAuthBasic}
+
+// WithDocAuthType sets how the doc API key is sent, one of
+// validDocAuthTypes. The default is DocAuthAPIKey.
+func WithDocAuthType(authType string) ClientOption {
+	return func(c *APIClient) {
+		c.docAuthType = authType
+	}
+}
+
+// setDocAuth sets the credential of a doc request as the auth type demands.
+func (c *APIClient) setDocAuth(req *http.Request) {
+	switch c.docAuthType {
+	case DocAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.docAPIKey)
+	case DocAuthBasic:
+		user, pass, _ := strings.Cut(c.docAPIKey, ":")
+		req.SetBasicAuth(user, pass)
+	default:
+		req.Header.Set("X-API-Key", c.docAPIKey)
+	}
+}
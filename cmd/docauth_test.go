@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_fetchDocAuth(t *testing.T) {
+	tests := []struct {
+		authType      string
+		key           string
+		wantAPIKey    string
+		wantAuthorize string
+	}{
+		{authType: "", key: "doc-key", wantAPIKey: "doc-key"},
+		{authType: DocAuthAPIKey, key: "doc-key", wantAPIKey: "doc-key"},
+		{authType: DocAuthBearer, key: "token", wantAuthorize: "Bearer token"},
+		// dXNlcjpwYTpzcw== is base64 of user:pa:ss.
+		{authType: DocAuthBasic, key: "user:pa:ss", wantAuthorize: "Basic dXNlcjpwYTpzcw=="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.authType, func(t *testing.T) {
+			var got http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.Write([]byte(`{"openapi":"3.0.0"}`))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient(tt.key, "pm-key", WithOutput(io.Discard), WithDocAuthType(tt.authType))
+			if _, err := client.fetchDoc(t.Context(), server.URL); err != nil {
+				t.Fatalf("fetchDoc() error = %v", err)
+			}
+
+			if got.Get("X-API-Key") != tt.wantAPIKey {
+				t.Errorf("X-API-Key = %q, want %q", got.Get("X-API-Key"), tt.wantAPIKey)
+			}
+			if got.Get("Authorization") != tt.wantAuthorize {
+				t.Errorf("Authorization = %q, want %q", got.Get("Authorization"), tt.wantAuthorize)
+			}
+		})
+	}
+}
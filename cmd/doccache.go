@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrUnchanged is returned for a module whose spec has the same hash as when
+// it was last synced. The module is reported as StatusUnchanged, not failed.
+var ErrUnchanged = errors.New("spec unchanged since the last sync")
+
+// WithForce makes the client sync every module, even when its spec has not
+// changed since the hash recorded in its state.
+func WithForce(force bool) ClientOption {
+	return func(c *APIClient) {
+		c.force = force
+	}
+}
+
+// docHash returns the hex-encoded SHA-256 hash of a fetched doc.
+func docHash(doc string) string {
+	sum := sha256.Sum256([]byte(doc))
+	return hex.EncodeToString(sum[:])
+}
+
+// unchanged reports whether the module's doc hash matches the one recorded
+// when it was last synced. It is always false without a state, with force or
+// when nothing is synced to Postman.
+func (c *APIClient) unchanged(module, hash string) bool {
+	if c.state == nil || c.force || c.offline() {
+		return false
+	}
+	recorded, ok := c.state.DocHash(module)
+	return ok && recorded == hash
+}
+
+// rememberDoc records the hash of a module's doc once it is synced, so the
+// next run can skip it while the doc stays the same, and its surface, so the
+// next run can report what changed.
+func (c *APIClient) rememberDoc(prepared *PreparedModule) {
+	if c.state == nil || c.dryRun || prepared.DocHash == "" {
+		return
+	}
+	c.state.SetDocHash(prepared.ModuleName, prepared.DocHash)
+	c.state.SetDocValidator(prepared.ModuleName, prepared.Validator)
+	if prepared.Surface != nil {
+		c.state.SetSurface(prepared.ModuleName, *prepared.Surface)
+	}
+}
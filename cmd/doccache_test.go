@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+)
+
+func TestAPIClient_SkipsUnchangedSpec(t *testing.T) {
+	docURL, postmanURL, requests, _ := upsertServers(t)
+
+	state := &State{}
+	newClient := func(opts ...ClientOption) *APIClient {
+		client := NewAPIClient("doc-key", "pm-key", append(opts, WithState(state), WithOutput(io.Discard))...)
+		client.postmanBaseURL = postmanURL
+		client.docURL = func(string) string { return docURL }
+		return client
+	}
+
+	if err := newClient().ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("first ProcessModule() error = %v", err)
+	}
+	if _, ok := state.DocHash("Customers"); !ok {
+		t.Error("no doc hash recorded after the sync")
+	}
+	synced := len(*requests)
+
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+	results, err := NewSyncOrchestrator(newClient(), config).SyncAllModules(t.Context(), "workspace")
+	if err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+	if results[0].Status != StatusUnchanged || results[0].Err != nil {
+		t.Errorf("result = %+v, want unchanged without error", results[0])
+	}
+	if len(*requests) != synced {
+		t.Errorf("unchanged spec sent Postman requests %v", (*requests)[synced:])
+	}
+
+	err = newClient(WithForce(true)).ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("forced ProcessModule() error = %v", err)
+	}
+	if len(*requests) == synced {
+		t.Error("-force did not sync the unchanged spec")
+	}
+}
+
+func TestAPIClient_SyncsChangedSpec(t *testing.T) {
+	docURL, postmanURL, requests, _ := upsertServers(t)
+
+	state := &State{}
+	old := docHash("an older spec")
+	state.SetDocHash("Customers", old)
+	client := NewAPIClient("doc-key", "pm-key", WithState(state), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("ProcessModule() error = %v, want the changed spec synced", err)
+	}
+	if len(*requests) == 0 {
+		t.Error("changed spec sent no Postman requests")
+	}
+	if hash, _ := state.DocHash("Customers"); hash == old {
+		t.Error("recorded hash is still the old spec's")
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeDoc parses a doc response body. YAML is detected from the content
+// type or, failing that, from a body that does not start like JSON.
+func decodeDoc(body []byte, contentType string) (any, error) {
+	if !isYAML(body, contentType) {
+		var data any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, fmt.Errorf("decoding JSON: %w", err)
+		}
+		return data, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(body, &node); err != nil {
+		return nil, fmt.Errorf("decoding YAML: %w", err)
+	}
+
+	data, err := yamlValue(&node)
+	if err != nil {
+		return nil, fmt.Errorf("decoding YAML: %w", err)
+	}
+	return data, nil
+}
+
+func isYAML(body []byte, contentType string) bool {
+	if strings.Contains(contentType, "yaml") {
+		return true
+	}
+	if strings.Contains(contentType, "json") {
+		return false
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// yamlValue converts a YAML node to the values encoding/json produces, so the
+// result marshals to JSON. Mapping keys are always strings, as in JSON, and
+// timestamps keep their literal text.
+func yamlValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(node.Content[0])
+
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+
+	case yaml.MappingNode:
+		object := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = value
+		}
+		return object, nil
+
+	case yaml.SequenceNode:
+		array := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+
+	default:
+		if node.Tag == "!!timestamp" {
+			return node.Value, nil
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
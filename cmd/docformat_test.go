@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const yamlSpec = `openapi: 3.0.0
+info:
+  title: Customers
+  version: "1.2"
+  x-released: 2024-01-15
+paths:
+  /customers/{id}:
+    get:
+      tags: [customers, read]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Customer'
+components:
+  schemas:
+    Customer:
+      type: object
+      required:
+        - id
+      properties:
+        id: &id
+          type: string
+        referrerId: *id
+`
+
+func TestAPIClient_FetchDocYAML(t *testing.T) {
+	for _, contentType := range []string{"application/yaml", "text/plain"} {
+		t.Run(contentType, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", contentType)
+				w.Write([]byte(yamlSpec))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+			doc, err := client.fetchDoc(t.Context(), server.URL)
+			if err != nil {
+				t.Fatalf("fetchDoc() error = %v", err)
+			}
+
+			var spec map[string]any
+			if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+				t.Fatalf("fetchDoc() did not return JSON: %v\n%s", err, doc)
+			}
+
+			info := spec["info"].(map[string]any)
+			if info["version"] != "1.2" || info["x-released"] != "2024-01-15" {
+				t.Errorf("info = %v, want string version and date", info)
+			}
+
+			get := spec["paths"].(map[string]any)["/customers/{id}"].(map[string]any)["get"].(map[string]any)
+			if !reflect.DeepEqual(get["tags"], []any{"customers", "read"}) {
+				t.Errorf("tags = %v", get["tags"])
+			}
+			params := get["parameters"].([]any)
+			if len(params) != 1 || params[0].(map[string]any)["required"] != true {
+				t.Errorf("parameters = %v", params)
+			}
+			if _, ok := get["responses"].(map[string]any)["200"]; !ok {
+				t.Errorf("responses = %v, want the numeric key as a string", get["responses"])
+			}
+
+			properties := spec["components"].(map[string]any)["schemas"].(map[string]any)["Customer"].(map[string]any)["properties"].(map[string]any)
+			if !reflect.DeepEqual(properties["referrerId"], properties["id"]) {
+				t.Errorf("alias not resolved: %v", properties)
+			}
+		})
+	}
+}
+
+func TestAPIClient_FetchDocJSONUnchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Customers"}}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	doc, err := client.fetchDoc(t.Context(), server.URL)
+	if err != nil {
+		t.Fatalf("fetchDoc() error = %v", err)
+	}
+
+	want := "{\n  \"info\": {\n    \"title\": \"Customers\"\n  },\n  \"openapi\": \"3.0.0\"\n}"
+	if doc != want {
+		t.Errorf("fetchDoc() = %q, want %q", doc, want)
+	}
+}
+
+func TestDecodeDoc_InvalidYAML(t *testing.T) {
+	if _, err := decodeDoc([]byte("openapi: [unclosed"), "application/yaml"); err == nil {
+		t.Error("decodeDoc() error = nil, want YAML error")
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithDocHeaders makes the client send the given headers, in addition to
+// the doc API credential, with every doc request.
+func WithDocHeaders(headers map[string]string) ClientOption {
+	return func(c *APIClient) {
+		c.docHeaders = headers
+	}
+}
+
+// setDocHeaders sets the credential and the extra headers of a doc request.
+func (c *APIClient) setDocHeaders(req *http.Request) {
+	c.setDocAuth(req)
+	for key, value := range c.docHeaders {
+		req.Header.Set(key, value)
+	}
+}
+
+// parseDocHeader splits a -doc-header value of the form key=value.
+func parseDocHeader(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t:") {
+		return "", "", fmt.Errorf("invalid doc-header %q: want key=value, e.g. X-Tenant-ID=acme", s)
+	}
+	return key, strings.TrimSpace(value), nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_fetchDocHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"openapi":"3.0.0"}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithDocHeaders(map[string]string{
+		"X-Tenant-ID": "acme",
+		"x-region":    "eu-west",
+	}))
+	if _, err := client.fetchDoc(t.Context(), server.URL); err != nil {
+		t.Fatalf("fetchDoc() error = %v", err)
+	}
+
+	for key, want := range map[string]string{"X-API-Key": "doc-key", "X-Tenant-ID": "acme", "X-Region": "eu-west"} {
+		if got.Get(key) != want {
+			t.Errorf("header %s = %q, want %q", key, got.Get(key), want)
+		}
+	}
+}
+
+func TestParseDocHeader(t *testing.T) {
+	for _, tt := range []struct {
+		in, key, value string
+		wantErr        bool
+	}{
+		{in: "X-Tenant-ID=acme", key: "X-Tenant-ID", value: "acme"},
+		{in: "X-Filter=a=b", key: "X-Filter", value: "a=b"},
+		{in: "X-Empty=", key: "X-Empty", value: ""},
+		{in: "X-Tenant-ID", wantErr: true},
+		{in: "=acme", wantErr: true},
+		{in: "X-Tenant-ID: acme", wantErr: true},
+	} {
+		key, value, err := parseDocHeader(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDocHeader(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if key != tt.key || value != tt.value {
+			t.Errorf("parseDocHeader(%q) = %q, %q, want %q, %q", tt.in, key, value, tt.key, tt.value)
+		}
+	}
+}
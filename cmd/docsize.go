@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errEmptyDoc is returned for docs that decode to nothing worth importing.
+var errEmptyDoc = errors.New("empty doc")
+
+// WithMinDocSize makes the client reject docs whose body is smaller than
+// minBytes, e.g. a truncated response, instead of importing them over the
+// existing collection. Zero, the default, only rejects docs that decode to
+// null or an empty object or array.
+func WithMinDocSize(minBytes int) ClientOption {
+	return func(c *APIClient) {
+		c.minDocSize = minBytes
+	}
+}
+
+// checkDoc rejects a fetched doc that is empty or below the minimum size, so
+// a doc API answering 200 with no content cannot wipe a collection.
+func (c *APIClient) checkDoc(body []byte, data any) error {
+	switch v := data.(type) {
+	case nil:
+		return fmt.Errorf("%w: doc API returned null", errEmptyDoc)
+	case map[string]any:
+		if len(v) == 0 {
+			return fmt.Errorf("%w: doc API returned an empty object", errEmptyDoc)
+		}
+	case []any:
+		if len(v) == 0 {
+			return fmt.Errorf("%w: doc API returned an empty array", errEmptyDoc)
+		}
+	}
+
+	if len(body) < c.minDocSize {
+		return fmt.Errorf("%w: doc is %d bytes, want at least %d", errEmptyDoc, len(body), c.minDocSize)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_ProcessModuleRejectsEmptyDoc(t *testing.T) {
+	const spec = `{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`
+
+	tests := []struct {
+		name       string
+		doc        string
+		minDocSize int
+		wantErr    bool
+	}{
+		{name: "empty object", doc: `{}`, wantErr: true},
+		{name: "empty array", doc: `[]`, wantErr: true},
+		{name: "null", doc: `null`, wantErr: true},
+		{name: "whitespace", doc: " \n", wantErr: true},
+		{name: "below the minimum size", doc: spec, minDocSize: len(spec) + 1, wantErr: true},
+		{name: "at the minimum size", doc: spec, minDocSize: len(spec)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.doc))
+			}))
+			defer docServer.Close()
+
+			postman := &mockPostman{collections: `{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`}
+			postmanServer := httptest.NewServer(postman)
+			defer postmanServer.Close()
+
+			client := NewAPIClient("doc-key", "pm-key",
+				WithPostmanBaseURL(postmanServer.URL),
+				WithDocURLTemplate(docServer.URL+"/%s"),
+				WithMinDocSize(tt.minDocSize),
+				WithOutput(io.Discard),
+			)
+
+			err := client.ProcessModule(t.Context(), "customers", "Customers Module API", "workspace")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ProcessModule() error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, errEmptyDoc) {
+				t.Fatalf("ProcessModule() error = %v, want an empty doc error", err)
+			}
+			if len(postman.deleted) != 0 || len(postman.imported) != 0 {
+				t.Errorf("deleted %v and imported %d collections for an empty doc", postman.deleted, len(postman.imported))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WithDocURLTemplates sets per-module doc URL templates that replace the
+// built-in doc URL. Templates may reference environment variables as $VAR or
+// ${VAR}, and ${VAR:-default} supplies a value for an unset variable.
+func WithDocURLTemplates(templates map[string]string) ClientOption {
+	return func(c *APIClient) {
+		c.docURLTemplates = templates
+	}
+}
+
+// WithDocURLTemplate makes the client build the doc URL of every module
+// without a per-module template from template, with %s standing for the
+// module name.
+func WithDocURLTemplate(template string) ClientOption {
+	return func(c *APIClient) {
+		if template != "" && template != DefaultDocURLTemplate {
+			c.docURL = func(moduleName string) string {
+				return fmt.Sprintf(template, moduleName)
+			}
+		}
+	}
+}
+
+// docURL returns the doc URL of a module according to DocURLTemplate. Under
+// the default template Home is served from the bare API host.
+func (c *ModuleConfig) docURL(moduleName string) string {
+	if c.DocURLTemplate == "" || c.DocURLTemplate == DefaultDocURLTemplate {
+		return docURL(moduleName)
+	}
+	return fmt.Sprintf(c.DocURLTemplate, moduleName)
+}
+
+// validateDocURLTemplate checks that template has exactly one %s and no
+// other formatting verbs.
+func validateDocURLTemplate(template string) error {
+	if strings.Count(template, "%s") != 1 {
+		return fmt.Errorf("doc-url-template %q must contain exactly one %%s for the module name", template)
+	}
+	if strings.Contains(strings.NewReplacer("%s", "", "%%", "").Replace(template), "%") {
+		return fmt.Errorf("doc-url-template %q must not contain formatting verbs other than %%s", template)
+	}
+	return nil
+}
+
+// moduleDocURL returns the doc URL of a module, expanding its template when
+// one is configured.
+func (c *APIClient) moduleDocURL(moduleName string) (string, error) {
+	template, ok := c.docURLTemplates[moduleName]
+	if !ok {
+		return c.docURL(moduleName), nil
+	}
+
+	url, err := expandEnv(template)
+	if err != nil {
+		return "", fmt.Errorf("doc URL of module %s: %w", moduleName, err)
+	}
+	return url, nil
+}
+
+// expandEnv replaces environment variable references in s. Variables without
+// a default must be set.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	expanded := os.Expand(s, func(name string) string {
+		name, fallback, hasDefault := strings.Cut(name, ":-")
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			return value
+		}
+		if !hasDefault {
+			missing = append(missing, name)
+		}
+		return fallback
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable not set: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_ModuleDocURLFromEnv(t *testing.T) {
+	var docPath string
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		docPath = r.URL.Path
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	t.Setenv("ORDERS_DOC_HOST", strings.TrimPrefix(docServer.URL, "http://"))
+
+	client := NewAPIClient("doc-key", "pm-key",
+		WithOutput(io.Discard),
+		WithDocURLTemplates(map[string]string{"Orders": "http://${ORDERS_DOC_HOST}/${ORDERS_DOC_PATH:-v2}/docs"}),
+	)
+	client.postmanBaseURL = postman.URL
+
+	if err := client.ProcessModule(t.Context(), "Orders", "Orders Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+	if docPath != "/v2/docs" {
+		t.Errorf("doc path = %q, want /v2/docs", docPath)
+	}
+}
+
+func TestAPIClient_ModuleDocURLMissingEnv(t *testing.T) {
+	t.Setenv("ORDERS_DOC_HOST", "")
+
+	client := NewAPIClient("doc-key", "pm-key",
+		WithOutput(io.Discard),
+		WithDocURLTemplates(map[string]string{"Orders": "https://${ORDERS_DOC_HOST}/docs"}),
+	)
+	client.docURL = func(string) string {
+		t.Error("built-in doc URL used for a module with a template")
+		return ""
+	}
+
+	err := client.ProcessModule(t.Context(), "Orders", "Orders Module API", "workspace")
+	if err == nil || !strings.Contains(err.Error(), "ORDERS_DOC_HOST") {
+		t.Errorf("ProcessModule() error = %v, want it to name ORDERS_DOC_HOST", err)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DOC_HOST", "docs.internal")
+
+	tests := []struct {
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{template: "https://$DOC_HOST/v1", want: "https://docs.internal/v1"},
+		{template: "https://${DOC_HOST}/v1", want: "https://docs.internal/v1"},
+		{template: "https://${DOC_HOST}/${DOC_VERSION:-v1}", want: "https://docs.internal/v1"},
+		{template: "https://${DOC_HOST}/${DOC_VERSION}", wantErr: true},
+		{template: "https://api.example.com/v1", want: "https://api.example.com/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := expandEnv(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expandEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDocURLTemplate(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key",
+		WithDocURLTemplate("https://api.%s.vivalabs-staging.link/v1/internal-docs"),
+		WithDocURLTemplates(map[string]string{"Orders": "https://orders.internal/docs"}),
+	)
+
+	tests := map[string]string{
+		"Customers": "https://api.Customers.vivalabs-staging.link/v1/internal-docs",
+		"Home":      "https://api.Home.vivalabs-staging.link/v1/internal-docs",
+		"Orders":    "https://orders.internal/docs",
+	}
+	for module, want := range tests {
+		if got, err := client.moduleDocURL(module); err != nil || got != want {
+			t.Errorf("moduleDocURL(%s) = %q, %v, want %q", module, got, err, want)
+		}
+	}
+
+	defaults := NewAPIClient("doc-key", "pm-key", WithDocURLTemplate(DefaultDocURLTemplate))
+	if got, _ := defaults.moduleDocURL("Home"); got != "https://api.vivalabs-dev.link/v1/internal-docs" {
+		t.Errorf("moduleDocURL(Home) = %q, want the bare dev host", got)
+	}
+}
+
+func TestValidateDocURLTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{template: DefaultDocURLTemplate},
+		{template: "https://%s.example.com/docs?q=100%%"},
+		{template: "https://docs.example.com", wantErr: true},
+		{template: "https://%s.example.com/%s", wantErr: true},
+		{template: "https://%s.example.com/%d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if err := validateDocURLTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("validateDocURLTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WithFailOnDuplicates makes the client fail a module whose collection name
+// is already used by more than one collection, instead of warning and
+// deleting all of them. One of them may have been created by hand.
+func WithFailOnDuplicates(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.failOnDuplicates = enabled
+	}
+}
+
+// checkDuplicateCollections warns when more than one existing collection
+// would be replaced by the module's collection, listing each of them, and
+// fails when duplicates are not allowed.
+func (c *APIClient) checkDuplicateCollections(ctx context.Context, name string, refs []CollectionRef) error {
+	if len(refs) < 2 {
+		return nil
+	}
+
+	described := make([]string, len(refs))
+	for i, ref := range refs {
+		described[i] = describeCollection(ref)
+	}
+	list := strings.Join(described, "; ")
+
+	if c.failOnDuplicates {
+		return fmt.Errorf("%d collections named %q: %s", len(refs), name, list)
+	}
+	c.log.WarnContext(ctx, "several collections share the name, all of them will be replaced", "name", name, "count", len(refs), "collections", list)
+	return nil
+}
+
+// describeCollection returns the collection's uid followed by its owner and
+// last update, when known.
+func describeCollection(ref CollectionRef) string {
+	var details []string
+	if ref.Owner != "" {
+		details = append(details, "owner "+ref.Owner)
+	}
+	if ref.UpdatedAt != "" {
+		details = append(details, "updated "+ref.UpdatedAt)
+	}
+	if len(details) == 0 {
+		return ref.UpdateKey()
+	}
+	return fmt.Sprintf("%s (%s)", ref.UpdateKey(), strings.Join(details, ", "))
+}
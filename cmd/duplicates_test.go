@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func duplicateServers(t *testing.T) (*APIClient, *strings.Builder, *[]string) {
+	t.Helper()
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/members":{"get":{}}}}`))
+	}))
+	t.Cleanup(docServer.Close)
+
+	var calls []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" {
+			w.Write([]byte(`{"collections":[
+				{"id":"a","uid":"1-a","name":"Members Module API","owner":"1","updatedAt":"2024-05-01T10:00:00.000Z"},
+				{"id":"b","uid":"2-b","name":"Members Module API","owner":"2","updatedAt":"2024-06-01T10:00:00.000Z"}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Members Module API"}]}`))
+	}))
+	t.Cleanup(postman.Close)
+
+	var out strings.Builder
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(&out))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+	return client, &out, &calls
+}
+
+func TestAPIClient_DuplicateCollectionsWarn(t *testing.T) {
+	client, out, calls := duplicateServers(t)
+
+	if err := client.ProcessModule(t.Context(), "Members", "Members Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"several collections share the name",
+		"1-a (owner 1, updated 2024-05-01T10:00:00.000Z)",
+		"2-b (owner 2, updated 2024-06-01T10:00:00.000Z)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain %q:\n%s", want, out.String())
+		}
+	}
+	if !strings.Contains(strings.Join(*calls, ","), "DELETE /collections/b") {
+		t.Errorf("calls = %v, want both duplicates deleted", *calls)
+	}
+}
+
+func TestAPIClient_DuplicateCollectionsFail(t *testing.T) {
+	client, _, calls := duplicateServers(t)
+	client.failOnDuplicates = true
+
+	err := client.ProcessModule(t.Context(), "Members", "Members Module API", "workspace")
+	if err == nil || !strings.Contains(err.Error(), `2 collections named "Members Module API"`) {
+		t.Fatalf("ProcessModule() error = %v, want a duplicates error", err)
+	}
+	if strings.Join(*calls, ",") != "GET /collections" {
+		t.Errorf("calls = %v, want only the listing", *calls)
+	}
+}
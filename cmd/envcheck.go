@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// envAliases maps other spellings of an environment in a host name to the
+// entry of validEnvs they stand for.
+var envAliases = map[string]string{
+	"development": "dev",
+	"stage":       "staging",
+	"stg":         "staging",
+	"production":  "prod",
+}
+
+// docEnvironment returns the environment named by a label of the doc URL's
+// host, e.g. dev for api.customers.vivalabs-dev.link, or "" when the host
+// names none.
+func docEnvironment(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	labels := strings.FieldsFunc(strings.ToLower(u.Hostname()), func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+	for _, label := range labels {
+		if slices.Contains(validEnvs, label) {
+			return label
+		}
+		if env, ok := envAliases[label]; ok {
+			return env
+		}
+	}
+	return ""
+}
+
+// CheckDocEnvironment fails when the doc URL of a module names another
+// environment than env, the environment of the Postman workspace, so dev docs
+// are never published into a prod workspace or the other way round. Doc URLs
+// whose host names no environment pass.
+func (c *APIClient) CheckDocEnvironment(config *ModuleConfig, env string) error {
+	var mismatches []string
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		docURL, err := c.moduleDocURL(module)
+		if err != nil {
+			return err
+		}
+		if docEnv := docEnvironment(docURL); docEnv != "" && docEnv != env {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s docs at %s)", module, docEnv, docURL))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("env %s does not match the docs of %d modules: %s", env, len(mismatches), strings.Join(mismatches, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocEnvironment(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://api.customers.vivalabs-dev.link/v1/internal-docs", want: "dev"},
+		{url: "https://api.customers.staging.example.com/docs", want: "staging"},
+		{url: "https://docs-production.example.com/customers", want: "prod"},
+		{url: "https://api.customers.example.com/docs", want: ""},
+		{url: "https://example.com/dev/docs", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := docEnvironment(tt.url); got != tt.want {
+			t.Errorf("docEnvironment(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestAPIClient_CheckDocEnvironment(t *testing.T) {
+	config := &ModuleConfig{Modules: map[string]string{
+		"Customers": "Customers Module API",
+		"Orders":    "Orders Module API",
+	}}
+
+	t.Run("dev docs into a prod workspace abort", func(t *testing.T) {
+		client := NewAPIClient("doc", "pm")
+
+		err := client.CheckDocEnvironment(config, "prod")
+		if err == nil {
+			t.Fatal("CheckDocEnvironment() error = nil, want a mismatch")
+		}
+		if !strings.Contains(err.Error(), "Customers (dev docs at https://api.Customers.vivalabs-dev.link/v1/internal-docs)") {
+			t.Errorf("error = %v, want it to name the mismatched module", err)
+		}
+	})
+
+	t.Run("dev docs into a dev workspace pass", func(t *testing.T) {
+		if err := NewAPIClient("doc", "pm").CheckDocEnvironment(config, "dev"); err != nil {
+			t.Errorf("CheckDocEnvironment() error = %v", err)
+		}
+	})
+
+	t.Run("docs whose host names no environment pass", func(t *testing.T) {
+		client := NewAPIClient("doc", "pm", WithDocURLTemplate("https://docs.example.com/%s"))
+		if err := client.CheckDocEnvironment(config, "prod"); err != nil {
+			t.Errorf("CheckDocEnvironment() error = %v", err)
+		}
+	})
+}
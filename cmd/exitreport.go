@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ExitReport is the record of how the process ended, written to the
+// -report-file path on every exit once the flags are parsed.
+type ExitReport struct {
+	Status   string         `json:"status"`
+	ExitCode int            `json:"exitCode"`
+	Error    string         `json:"error,omitempty"`
+	Modules  []ModuleResult `json:"modules,omitempty"`
+}
+
+// NewExitReport returns the report of a process exiting with exitCode after
+// err, which is nil on success, and the given module results.
+func NewExitReport(exitCode int, err error, results []ModuleResult) ExitReport {
+	report := ExitReport{Status: "success", ExitCode: exitCode, Modules: results}
+	if exitCode != 0 || err != nil {
+		report.Status = "failed"
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// WriteExitReport writes the exit report as JSON to path.
+func WriteExitReport(path string, report ExitReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling exit report: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing exit report: %w", err)
+	}
+	return nil
+}
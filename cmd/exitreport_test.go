@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteExitReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		exitCode   int
+		err        error
+		results    []ModuleResult
+		wantStatus string
+		wantError  string
+	}{
+		{
+			name:       "success",
+			results:    []ModuleResult{{Module: "Brands", Status: StatusSucceeded}},
+			wantStatus: "success",
+		},
+		{
+			name:       "failed module",
+			exitCode:   1,
+			err:        errors.New("module Brands: import failed"),
+			results:    []ModuleResult{{Module: "Brands", Status: StatusFailed, Err: errors.New("import failed")}},
+			wantStatus: "failed",
+			wantError:  "module Brands: import failed",
+		},
+		{
+			name:       "failed before the sync",
+			exitCode:   1,
+			err:        errors.New("reading config"),
+			wantStatus: "failed",
+			wantError:  "reading config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "exit.json")
+			if err := WriteExitReport(path, NewExitReport(tt.exitCode, tt.err, tt.results)); err != nil {
+				t.Fatalf("WriteExitReport() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading exit report: %v", err)
+			}
+			var got struct {
+				Status   string            `json:"status"`
+				ExitCode int               `json:"exitCode"`
+				Error    string            `json:"error"`
+				Modules  []json.RawMessage `json:"modules"`
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("exit report is not JSON: %v\n%s", err, data)
+			}
+			if got.Status != tt.wantStatus || got.ExitCode != tt.exitCode || got.Error != tt.wantError {
+				t.Errorf("exit report = %+v, want status %q, exit code %d and error %q", got, tt.wantStatus, tt.exitCode, tt.wantError)
+			}
+			if len(got.Modules) != len(tt.results) {
+				t.Errorf("got %d modules, want %d", len(got.Modules), len(tt.results))
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errFailedFast is the cause of the cancellation of a fail-fast sync.
+var errFailedFast = errors.New("failing fast")
+
+// SetFailFast makes SyncAllModules cancel the modules in flight, and start no
+// further module, as soon as one module fails. By default every module runs
+// and the failures are reported together at the end, unless Postman rejects
+// the API key, which fails the sync fast either way.
+func (s *SyncOrchestrator) SetFailFast(enabled bool) {
+	s.failFast = enabled
+}
+
+// failFastContexts derives from ctx and work the contexts of a sync that can
+// fail fast, together with the function cancelling both. Any sync can: it
+// fails fast on a rejected Postman API key even without fail-fast.
+func (s *SyncOrchestrator) failFastContexts(ctx, work context.Context) (context.Context, context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	work, cancelWork := context.WithCancelCause(work)
+	return ctx, work, func(cause error) {
+		cancel(cause)
+		cancelWork(cause)
+	}
+}
+
+// abortOnFailure wraps done so that a failed module calls abort before done
+// gets its result, when the sync fails fast or Postman rejected the API key.
+func (s *SyncOrchestrator) abortOnFailure(abort context.CancelCauseFunc, done func(ModuleResult)) func(ModuleResult) {
+	return func(result ModuleResult) {
+		switch {
+		case result.Status != StatusFailed:
+		case errors.Is(result.Err, ErrPostmanUnauthorized):
+			// The other modules would be refused the same key.
+			abort(fmt.Errorf("%w: module %s: %w", errFailedFast, result.Module, ErrPostmanUnauthorized))
+		case s.failFast:
+			abort(fmt.Errorf("%w: module %s failed", errFailedFast, result.Module))
+		}
+		done(result)
+	}
+}
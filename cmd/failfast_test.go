@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingProcessor fails Brands. Customers, running next to it, finishes a
+// while after Brands failed unless cancelled first; Orders succeeds.
+type failingProcessor struct {
+	failed chan struct{}
+}
+
+func (p *failingProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	switch moduleName {
+	case "Brands":
+		close(p.failed)
+		return errors.New("import failed")
+	case "Customers":
+		<-p.failed
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestSyncAllModules_FailFast(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast bool
+		want     map[string]ModuleStatus
+	}{
+		{
+			name: "continue runs every module",
+			want: map[string]ModuleStatus{"Brands": StatusFailed, "Customers": StatusSucceeded, "Orders": StatusSucceeded},
+		},
+		{
+			name:     "fail-fast cancels the remaining modules",
+			failFast: true,
+			want:     map[string]ModuleStatus{"Brands": StatusFailed, "Customers": StatusFailed, "Orders": StatusSkipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ModuleConfig{
+				Modules:     map[string]string{"Brands": "Brands Module API", "Customers": "Customers Module API", "Orders": "Orders Module API"},
+				Concurrency: 2,
+			}
+			orchestrator := NewSyncOrchestrator(&failingProcessor{failed: make(chan struct{})}, config)
+			orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+			orchestrator.SetFailFast(tt.failFast)
+
+			results, err := orchestrator.SyncAllModules(t.Context(), "workspace")
+			if err == nil || !strings.Contains(err.Error(), "module Brands") {
+				t.Errorf("SyncAllModules() error = %v, want the Brands failure", err)
+			}
+			if errors.Is(err, context.Canceled) && !tt.failFast {
+				t.Errorf("SyncAllModules() error = %v, want no cancellation", err)
+			}
+
+			for _, result := range results {
+				if result.Status != tt.want[result.Module] {
+					t.Errorf("%s status = %s (%v), want %s", result.Module, result.Status, result.Err, tt.want[result.Module])
+				}
+			}
+			if tt.failFast && !errors.Is(results[2].Err, errFailedFast) {
+				t.Errorf("Orders error = %v, want it skipped for failing fast", results[2].Err)
+			}
+		})
+	}
+}
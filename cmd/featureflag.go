@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ErrFeatureDisabled marks modules skipped because their feature flag, or the
+// flag of a module they depend on, is off. Such modules do not fail the sync.
+var ErrFeatureDisabled = errors.New("feature flag is off")
+
+// FeatureFlagChecker is implemented by processors that can look up whether a
+// feature flag is on.
+type FeatureFlagChecker interface {
+	FeatureEnabled(ctx context.Context, flag string) (bool, error)
+}
+
+// WithFlagCheckURL sets the feature flag service queried for the modules that
+// name a feature flag. A flag is looked up with a GET request to the URL with
+// the flag's name in the flag query parameter, answered with
+// {"enabled": true} or {"enabled": false}.
+func WithFlagCheckURL(checkURL string) ClientOption {
+	return func(c *APIClient) {
+		c.flagCheckURL = checkURL
+	}
+}
+
+// FeatureEnabled reports whether the feature flag is on. Without a flag
+// service every flag is on. When the service cannot answer the flag counts as
+// on, with a warning, unless the client is strict.
+func (c *APIClient) FeatureEnabled(ctx context.Context, flag string) (bool, error) {
+	if c.flagCheckURL == "" {
+		return true, nil
+	}
+
+	enabled, err := c.queryFeatureFlag(ctx, flag)
+	if err != nil {
+		if c.strict {
+			return false, err
+		}
+		c.log.WarnContext(ctx, "feature flag service unavailable, syncing anyway", "flag", flag, "error", err)
+		return true, nil
+	}
+	return enabled, nil
+}
+
+func (c *APIClient) queryFeatureFlag(ctx context.Context, flag string) (bool, error) {
+	checkURL, err := url.Parse(c.flagCheckURL)
+	if err != nil {
+		return false, fmt.Errorf("parsing flag check URL: %w", err)
+	}
+	query := checkURL.Query()
+	query.Set("flag", flag)
+	checkURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", checkURL.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.Enabled == nil {
+		return false, fmt.Errorf("unexpected flag response: %s", string(body))
+	}
+	return *result.Enabled, nil
+}
+
+// checkFeatureFlag returns an error wrapping ErrFeatureDisabled when the
+// module names a feature flag that is off.
+func (s *SyncOrchestrator) checkFeatureFlag(ctx context.Context, moduleName string, processor any) error {
+	flag, ok := s.config.FeatureFlags[moduleName]
+	if !ok {
+		return nil
+	}
+	checker, ok := processor.(FeatureFlagChecker)
+	if !ok {
+		return nil
+	}
+
+	ctx = withModule(ctx, moduleName)
+	enabled, err := checker.FeatureEnabled(ctx, flag)
+	if err != nil {
+		return fmt.Errorf("checking feature flag %s: %w", flag, err)
+	}
+	if !enabled {
+		s.log.InfoContext(ctx, "feature flag is off, skipping", "flag", flag)
+		return fmt.Errorf("skipped: %w (%s)", ErrFeatureDisabled, flag)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// flaggedProcessor records module syncs and looks feature flags up with a
+// real client.
+type flaggedProcessor struct {
+	recordingProcessor
+	flags *APIClient
+}
+
+func (p *flaggedProcessor) FeatureEnabled(ctx context.Context, flag string) (bool, error) {
+	return p.flags.FeatureEnabled(ctx, flag)
+}
+
+// flagServer serves the given flags; unknown flags get a 500.
+func flagServer(t *testing.T, flags map[string]bool) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enabled, ok := flags[r.URL.Query().Get("flag")]
+		switch {
+		case !ok:
+			http.Error(w, "unknown flag", http.StatusInternalServerError)
+		case enabled:
+			w.Write([]byte(`{"enabled": true}`))
+		default:
+			w.Write([]byte(`{"enabled": false}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestSyncAllModules_FeatureFlags(t *testing.T) {
+	checkURL := flagServer(t, map[string]bool{"brands-docs": true, "classes-docs": false})
+	processor := &flaggedProcessor{flags: NewAPIClient("", "", WithFlagCheckURL(checkURL), WithOutput(io.Discard))}
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Brands":    "Brands Module API",
+			"Classes":   "Classes Module API",
+			"Customers": "Customers Module API",
+			"Home":      "Home Module API",
+		},
+		DependsOn:    map[string][]string{"Home": {"Classes"}},
+		FeatureFlags: map[string]string{"Brands": "brands-docs", "Classes": "classes-docs"},
+	}
+
+	results, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace")
+	if err != nil {
+		t.Fatalf("SyncAllModules() error = %v, want disabled modules not to fail the sync", err)
+	}
+
+	want := map[string]ModuleStatus{
+		"Brands":    StatusSucceeded,
+		"Classes":   StatusSkipped,
+		"Customers": StatusSucceeded,
+		"Home":      StatusSkipped,
+	}
+	for _, r := range results {
+		if r.Status != want[r.Module] {
+			t.Errorf("%s status = %s, want %s", r.Module, r.Status, want[r.Module])
+		}
+		if r.Status == StatusSkipped && !errors.Is(r.Err, ErrFeatureDisabled) {
+			t.Errorf("%s skip reason = %v, want the feature flag", r.Module, r.Err)
+		}
+	}
+	for _, mod := range []string{"Classes", "Home"} {
+		if processor.index("start "+mod) >= 0 {
+			t.Errorf("disabled module %s was synced", mod)
+		}
+	}
+}
+
+func TestAPIClient_FeatureEnabledUnavailable(t *testing.T) {
+	checkURL := flagServer(t, nil)
+
+	lenient := NewAPIClient("", "", WithFlagCheckURL(checkURL), WithOutput(io.Discard))
+	if enabled, err := lenient.FeatureEnabled(t.Context(), "brands-docs"); err != nil || !enabled {
+		t.Errorf("FeatureEnabled() = %v, %v, want the module synced anyway", enabled, err)
+	}
+
+	strict := NewAPIClient("", "", WithFlagCheckURL(checkURL), WithStrict(true), WithOutput(io.Discard))
+	if _, err := strict.FeatureEnabled(t.Context(), "brands-docs"); err == nil {
+		t.Error("FeatureEnabled() error = nil, want the unavailable service with -strict")
+	}
+}
@@ -5,21 +5,263 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Params struct {
 	DocAPIKey          string
 	PostmanAPIKey      string
 	PostmanWorkspaceID string
+	// PostmanBaseURL replaces https://api.getpostman.com when set.
+	PostmanBaseURL string
+	Env            string
+	// CheckEnv aborts the run when a doc URL names another environment
+	// than Env.
+	CheckEnv       bool
+	ConfirmProd    bool
+	EmitScript     bool
+	OnlyIfEmpty    bool
+	RetryBodyCodes []string
+	WorkspaceType  string
+	BatchCleanup   bool
+	StatusOutput   string
+	JSONOutput     bool
+	Probe          bool
+	// Check verifies the doc and Postman API keys without syncing.
+	Check              bool
+	CollectionKeyField string
+	GzipImport         bool
+	RecordFile         string
+	ReplayFile         string
+	Share              string
+	StateFile          string
+	BatchSize          int
+	DryRun             bool
+	InjectSecurity     string
+	ForceSecurity      bool
+	Canonical          bool
+	ConfigFile         string
+	// Profile selects a profile of ConfigFile, whose modules, doc URL
+	// template and workspace apply unless given explicitly.
+	Profile    string
+	MaxRetries int
+	// ImportMaxRetries is how many times imports are retried, which
+	// requires VerifyImport.
+	ImportMaxRetries  int
+	RetryDelay        time.Duration
+	PostmanAPIVersion string
+	Upsert            bool
+	Concurrency       int
+	// Ordered syncs the modules one at a time in alphabetical order, after
+	// the modules they depend on.
+	Ordered bool
+	// MaxWorkspaceCollections aborts the run when the workspace already
+	// holds more collections. Zero disables the check.
+	MaxWorkspaceCollections int
+	// MaxConcurrentDeletes caps the collection deletes in flight across all
+	// modules; zero means no cap.
+	MaxConcurrentDeletes int
+	// ConcurrencyPerHost caps the connections open to any one host; zero
+	// means no cap.
+	ConcurrencyPerHost int
+	// RateLimit caps the Postman requests per minute across all modules;
+	// zero means no cap.
+	RateLimit int
+	Force     bool
+	Strategy  string
+	// CompareWorkspace is compared with PostmanWorkspaceID instead of
+	// syncing.
+	CompareWorkspace string
+	// Timeout limits every HTTP request, not the whole run.
+	Timeout time.Duration
+	// VerifyImport compares every imported collection with its spec, in
+	// one of validVerifyModes.
+	VerifyImport string
+	// FailOnDuplicates fails a module whose collection name is used by more
+	// than one collection instead of deleting all of them.
+	FailOnDuplicates bool
+	// DiagnoseOnFailure checks the connectivity to every host when the run
+	// fails with a network error.
+	DiagnoseOnFailure bool
+	// BrandingFile holds the branding added to collection descriptions.
+	BrandingFile string
+	// Proxy overrides the proxy named by HTTP_PROXY and HTTPS_PROXY.
+	Proxy *url.URL
+	// ShutdownGrace is how long modules in flight may finish after SIGINT
+	// or SIGTERM.
+	ShutdownGrace time.Duration
+	// StartJitter is the window of the random wait before each module.
+	StartJitter time.Duration
+	// FailFast cancels the sync once a module fails instead of running all
+	// modules.
+	FailFast bool
+	// LogLevel is the lowest level logged, one of validLogLevels.
+	LogLevel string
+	// Quiet writes errors only: the log level is error and the result tables
+	// are left out.
+	Quiet bool
+	// JSONLogs writes every log record as one JSON object per line.
+	JSONLogs bool
+	// ReportFile receives a JSON report of the run.
+	ReportFile string
+	// MaskPatterns redact their matches in spec descriptions and examples.
+	MaskPatterns []*regexp.Regexp
+	// Mode is one of validModes and decides which credentials are required.
+	Mode string
+	// ExitReportFile receives the status, module outcomes and exit code of
+	// the process on every exit.
+	ExitReportFile string
+	// BlockBreaking fails modules whose spec has a breaking change since the
+	// last run, unless AllowBreaking is set.
+	BlockBreaking bool
+	AllowBreaking bool
+	// DocAuthType is how DocAPIKey is sent, one of validDocAuthTypes.
+	DocAuthType string
+	// DocHeaders are sent with every doc request besides the credential.
+	DocHeaders map[string]string
+	// MinDocSize is the smallest doc body, in bytes, that is imported.
+	MinDocSize int
+	// CircuitFailures is how many failures in a row of a doc host skip its
+	// modules for CircuitWindow. Zero disables the circuit breaker.
+	CircuitFailures int
+	CircuitWindow   time.Duration
+	// WorkspaceName is resolved to PostmanWorkspaceID when no ID is given.
+	WorkspaceName string
+	// IDMapFile keeps the collection uid of every module between runs.
+	IDMapFile string
+	// FlagCheckURL is the feature flag service queried for the modules that
+	// name a feature flag.
+	FlagCheckURL string
+	// OutputDir receives the specs as <module>.json instead of Postman.
+	OutputDir string
+	// SummaryStreamFile receives each module's result as a JSON line as
+	// soon as the module completes.
+	SummaryStreamFile string
+	// Modules limits the sync to these modules. Empty means all modules.
+	Modules []string
+	// RequireOperationID checks that every operation has an operationId,
+	// in one of validOperationIDModes.
+	RequireOperationID  string
+	GenerateOperationID bool
+	// NoNormalize imports specs as published, without filling in a missing
+	// info.version or fixing missing and duplicate operationIds.
+	NoNormalize bool
+	// Strict fails modules on spec problems that are otherwise warnings.
+	Strict bool
+	// DocURLTemplate is the doc URL of a module, with %s standing for the
+	// module name.
+	DocURLTemplate string
+	// NotifyURL receives a JSON notification of the module outcomes.
+	NotifyURL string
+	// SlackWebhook receives a summary of the module outcomes.
+	SlackWebhook   string
+	NotifyOnChange bool
 }
 
+var validEnvs = []string{"dev", "staging", "prod"}
+
 func GetParams() (Params, error) {
 	var params Params
 
 	flag.StringVar(&params.DocAPIKey, "doc-api-key", os.Getenv("DOC_API_KEY"), "The OpenAPI doc API key")
-	flag.StringVar(&params.PostmanAPIKey, "pm-api-key", os.Getenv("PM_API_KEY"), "The Postman API key")
-	flag.StringVar(&params.PostmanWorkspaceID, "pm-workspace-id", os.Getenv("PM_WORKSPACE_ID"), "The Postman workspace ID")
+	flag.StringVar(&params.PostmanAPIKey, "pm-api-key", os.Getenv("PM_API_KEY"), "The Postman API key (defaults to the key of the Postman CLI login)")
+	flag.StringVar(&params.PostmanAPIVersion, "pm-api-version", DefaultPostmanAPIVersion, "The Postman API version requests are pinned to, sent in the Accept header")
+	flag.StringVar(&params.PostmanBaseURL, "postman-base-url", "", "Base URL of the Postman API, e.g. for an EU data residency tenant or a test server (default "+defaultPostmanBaseURL+")")
+	flag.StringVar(&params.PostmanWorkspaceID, "pm-workspace-id", os.Getenv("PM_WORKSPACE_ID"), "The Postman workspace ID (defaults to the workspace of the Postman CLI login, if set)")
+	flag.StringVar(&params.DocURLTemplate, "doc-url-template", envOrDefault("DOC_URL_TEMPLATE", DefaultDocURLTemplate), "The doc URL of a module, with exactly one %s standing for the module name")
+	flag.StringVar(&params.ConfigFile, "config", "", "YAML or JSON file mapping module names to collection names (defaults to the built-in modules)")
+	flag.StringVar(&params.Profile, "profile", "", "Profile of the -config file to use, e.g. staging, with its own modules, doc URL template and workspace; flags and environment variables override them")
+	flag.StringVar(&params.Env, "env", envOrDefault("SYNC_ENV", "dev"), "The target environment: dev, staging or prod")
+	flag.StringVar(&params.WorkspaceName, "workspace-name", "", "Name of the Postman workspace, looked up when no -pm-workspace-id is given")
+	flag.BoolVar(&params.CheckEnv, "check-env", false, "Abort when a module's doc URL names another environment than -env, e.g. dev docs into a prod workspace")
+	flag.BoolVar(&params.ConfirmProd, "confirm-prod", false, "Confirm destructive operations when -env=prod")
+	flag.BoolVar(&params.EmitScript, "emit-script", false, "Print the equivalent curl commands instead of running the sync")
+	flag.BoolVar(&params.OnlyIfEmpty, "only-if-empty", false, "Abort unless every Postman workspace modules are synced to has no collections yet")
+	flag.StringVar(&params.WorkspaceType, "workspace-type", "", "Warn unless the Postman workspace is of this type: personal or team")
+	flag.BoolVar(&params.BatchCleanup, "batch-cleanup", false, "Delete stale collections of all modules in one phase before importing")
+	flag.StringVar(&params.StatusOutput, "status-output", "stdout", "Where to write human-readable status messages: stdout, stderr or none")
+	flag.DurationVar(&params.ShutdownGrace, "shutdown-grace", 0, "On SIGINT or SIGTERM, start no new module and give the modules in flight this long to finish before cancelling them")
+	flag.DurationVar(&params.StartJitter, "start-jitter", DefaultStartJitter, "Delay the start of every module by a random time below this, so parallel modules do not hit Postman at once (0 disables it)")
+	flag.BoolVar(&params.FailFast, "fail-fast", false, "Cancel the modules in flight and start no further module as soon as one module fails")
+	continueAll := flag.Bool("continue", false, "Sync every module even when some fail, and report all failures at the end (the default)")
+	flag.StringVar(&params.LogLevel, "log-level", "info", "Lowest level of log messages to write: "+strings.Join(validLogLevels, ", ")+"; debug includes request URLs and response sizes")
+	flag.BoolVar(&params.Quiet, "quiet", false, "Write nothing but errors: log at error level and leave out the result tables")
+	flag.BoolVar(&params.JSONLogs, "json-logs", false, "Write log records as JSON objects, one per line, with time, level, msg and module, and leave out the result tables")
+	flag.BoolVar(&params.JSONOutput, "json", false, "Write a JSON run status to stdout")
+	flag.StringVar(&params.CompareWorkspace, "compare-workspaces", "", "Compare the module collections of -pm-workspace-id with this workspace, without syncing")
+	flag.BoolVar(&params.Check, "check", false, "Check that the doc API and Postman accept the API keys, without syncing; exits non-zero when either does not")
+	flag.BoolVar(&params.Probe, "probe", false, "Check that every module doc URL and the Postman workspace are reachable, without syncing")
+	flag.StringVar(&params.CollectionKeyField, "collection-key-field", "", "Spec field, e.g. info.x-collection-id, used instead of the name to match existing collections")
+	flag.BoolVar(&params.GzipImport, "gzip-import", false, "Send the import request body gzip-compressed")
+	flag.StringVar(&params.RecordFile, "record", "", "Record all HTTP interactions, with keys redacted, to this cassette file")
+	flag.StringVar(&params.ReplayFile, "replay", "", "Serve HTTP responses from this cassette file instead of the network")
+	flag.StringVar(&params.Share, "share", "", "Share imported collections with the team: team-view or team-edit")
+	flag.StringVar(&params.ReportFile, "report", "", "Write a JSON report of the run, with the collections deleted and created per module, to this file")
+	flag.StringVar(&params.SummaryStreamFile, "summary-stream-file", "", "Append each module's result to this file as a JSON line as soon as the module completes")
+	var maskPatterns []string
+	flag.Func("mask-pattern", "Replace the matches of this `regexp` in spec descriptions and examples with "+maskReplacement+" before import; may be repeated", func(pattern string) error {
+		maskPatterns = append(maskPatterns, pattern)
+		return nil
+	})
+	flag.IntVar(&params.MinDocSize, "min-doc-size", 0, "Fail modules whose doc body is smaller than this many bytes instead of importing it; docs that are null or empty always fail")
+	flag.StringVar(&params.DocAuthType, "doc-auth-type", DocAuthAPIKey, "How the doc API key is sent: apikey as X-API-Key, bearer as a bearer token, or basic with a user:pass key")
+	flag.IntVar(&params.CircuitFailures, "circuit-failures", 0, "Skip the modules of a doc host that failed this many times in a row, remembered across runs with -state-file (0 disables it)")
+	flag.DurationVar(&params.CircuitWindow, "circuit-window", DefaultCircuitWindow, "How long a doc host stays skipped after its last failure; failures further apart do not count as in a row")
+	var docHeaders []string
+	flag.Func("doc-header", "Extra `key=value` header sent with every doc request, e.g. X-Tenant-ID=acme; may be repeated", func(header string) error {
+		docHeaders = append(docHeaders, header)
+		return nil
+	})
+	flag.StringVar(&params.Mode, "mode", ModeSync, "What to do with the fetched docs: sync to Postman, fetch-only, or validate, where only sync needs Postman credentials; delete-only deletes the module collections without fetching any doc")
+	deleteOnly := flag.Bool("delete-only", false, "Only delete the collections of the modules, e.g. of a decommissioned service with -modules, without fetching docs or importing; same as -mode=delete-only")
+	flag.StringVar(&params.OutputDir, "output-dir", "", "Write every module's spec to <dir>/<module>.json instead of syncing it to Postman (implies -mode=fetch-only)")
+	flag.StringVar(&params.ExitReportFile, "report-file", "", "Write the overall status, module outcomes and exit code as JSON to this file when the process exits, also on failure")
+	flag.StringVar(&params.StateFile, "state-file", "", "File that keeps state between runs, including the hash of every synced spec so unchanged modules are skipped")
+	flag.StringVar(&params.IDMapFile, "id-map-file", "", "JSON file mapping every module to its collection uid; mapped collections are updated in place, keeping their uid, and only other collections of the same name are deleted")
+	flag.IntVar(&params.BatchSize, "batch-size", 0, "Sync at most this many modules per run, continuing with the next ones on the following run (requires -state-file)")
+	flag.BoolVar(&params.DryRun, "dry-run", envBool("DRY_RUN"), "Fetch docs and list collections, but only print what would be deleted and imported")
+	flag.StringVar(&params.InjectSecurity, "inject-security", "", "Add this security scheme and a global requirement for it to every spec: "+strings.Join(securitySchemeNames(), ", "))
+	flag.BoolVar(&params.ForceSecurity, "force-security", false, "Replace an existing scheme or global requirement when injecting security")
+	flag.BoolVar(&params.Canonical, "canonical", false, "Rewrite each spec in a byte-stable form, with sorted keys and set-like arrays")
+	flag.IntVar(&params.MaxRetries, "max-retries", defaultMaxRetries, "How many times to retry Postman requests, other than imports, that fail with 429, 502, 503 or 504")
+	flag.IntVar(&params.ImportMaxRetries, "import-max-retries", 0, "How many times to retry imports that fail with 429, 502, 503 or 504, deleting any duplicate collection this creates (requires -verify-import)")
+	flag.DurationVar(&params.RetryDelay, "retry-delay", defaultRetryDelay, "Base delay between retries, doubled after every attempt unless the response sets Retry-After")
+	flag.BoolVar(&params.Upsert, "upsert", false, "Update the collection recorded in the state file in place instead of deleting and re-importing it (requires -state-file); each update imports, reads and deletes a temporary collection for Postman's conversion, then puts it over the recorded one")
+	flag.IntVar(&params.Concurrency, "concurrency", defaultConcurrency, "How many modules to sync in parallel; 1 syncs them one at a time in dependency order")
+	flag.BoolVar(&params.Ordered, "ordered", false, "Sync modules one at a time in alphabetical order, after the modules they depend on, so logs and dry-run output are reproducible; same as -concurrency=1")
+	flag.IntVar(&params.MaxWorkspaceCollections, "max-workspace-collections", 0, "Abort when a workspace modules are synced to already holds more than this many collections (0 disables the check)")
+	flag.IntVar(&params.MaxConcurrentDeletes, "max-concurrent-deletes", 0, "How many collection deletes may run at once across all modules (0 means no cap)")
+	flag.IntVar(&params.ConcurrencyPerHost, "concurrency-per-host", 0, "How many connections may be open to any one host, such as the Postman API, whatever -concurrency is (0 means no cap)")
+	flag.IntVar(&params.RateLimit, "rate-limit", 0, "Most Postman requests to send per minute across all modules, spaced evenly (0 means no cap)")
+	flag.BoolVar(&params.Force, "force", false, "Sync modules whose spec is unchanged since the last run, and only warn, instead of aborting, when a safety check such as -max-workspace-collections fails")
+	flag.StringVar(&params.Strategy, "strategy", StrategyDeleteFirst, "Order of the delete and import steps: "+strings.Join(validStrategies, ", "))
+	flag.StringVar(&params.NotifyURL, "notify-url", os.Getenv("NOTIFY_URL"), "Webhook URL that receives a JSON notification of the module outcomes after the sync")
+	flag.StringVar(&params.SlackWebhook, "slack-webhook", os.Getenv("SLACK_WEBHOOK_URL"), "Slack incoming webhook URL that receives a summary of the module outcomes after the sync")
+	flag.BoolVar(&params.NotifyOnChange, "notify-on-change", false, "Only notify about modules whose outcome changed since the last run (requires -notify-url and -state-file)")
+	flag.StringVar(&params.RequireOperationID, "require-operation-id", "", "Check that every operation has an operationId, and warn or fail when one is missing: "+strings.Join(validOperationIDModes, ", "))
+	flag.BoolVar(&params.GenerateOperationID, "generate-operation-id", false, "Fill in missing operationIds from the method and path, e.g. getCustomersById, before importing")
+	flag.BoolVar(&params.NoNormalize, "no-normalize", false, "Import specs as published, without filling in a missing info.version or making operationIds present and unique")
+	flag.BoolVar(&params.Strict, "strict", false, "Fail modules whose spec has duplicate operationIds, or whose feature flag cannot be checked, instead of only warning")
+	flag.BoolVar(&params.BlockBreaking, "block-breaking", false, "Fail modules whose spec removes an endpoint or a required field, or changes a field's type, since the last run (requires -state-file)")
+	flag.BoolVar(&params.AllowBreaking, "allow-breaking", false, "Sync modules despite breaking changes blocked by -block-breaking, e.g. for an announced release")
+	flag.StringVar(&params.FlagCheckURL, "flag-check-url", "", "Feature flag service URL, queried with ?flag=<name> for every module whose config names a featureFlag; modules whose flag is off are skipped")
+	modules := flag.String("modules", "", "Comma-separated modules to sync, e.g. Customers,Brands (defaults to all configured modules)")
+	timeout := flag.String("timeout", envOrDefault("HTTP_TIMEOUT", defaultTimeout.String()), "Time limit of each HTTP request, e.g. 2m; it applies per request, not to the whole run")
+	flag.StringVar(&params.VerifyImport, "verify-import", "", "Fetch every imported collection and compare its requests with the spec's operations, and warn or fail on a mismatch: "+strings.Join(validVerifyModes, ", "))
+	flag.BoolVar(&params.FailOnDuplicates, "fail-on-duplicates", false, "Fail a module, instead of only warning, when several collections already have its name")
+	flag.BoolVar(&params.DiagnoseOnFailure, "diagnose-on-failure", false, "When the run fails with a network error, check DNS, TCP and TLS connectivity to every host and print the results")
+	flag.StringVar(&params.BrandingFile, "branding", "", "YAML or JSON file with a header and links added to the description of every collection")
+	proxy := flag.String("proxy", "", "Proxy URL for all requests, e.g. http://proxy.internal:3128 (default: HTTP_PROXY, HTTPS_PROXY and NO_PROXY)")
+	credentialSource := flag.String("credential-source", CredentialSourceFlag, "Where to read the API keys and workspace from: "+strings.Join(validCredentialSources, ", ")+"; flag also falls back to the environment and the Postman CLI login")
+	credentialsFile := flag.String("credentials-file", "", "YAML or JSON file with docApiKey, pmApiKey and pmWorkspaceId (for -credential-source=file)")
+	credentialsSecret := flag.String("credentials-secret", "", "Secret holding the credentials (for -credential-source=secret-manager)")
+	retryBodyCodes := flag.String("retry-on-body-code", "", "Comma-separated response body error codes to retry, e.g. TRY_AGAIN")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "API sync tool that imports OpenAPI documentation to Postman collections.\n\n")
@@ -29,17 +271,359 @@ func GetParams() (Params, error) {
 
 	flag.Parse()
 
-	if params.DocAPIKey == "" {
+	params.RetryBodyCodes = splitList(*retryBodyCodes)
+	params.Modules = splitList(*modules)
+
+	var err error
+	params.Timeout, err = time.ParseDuration(*timeout)
+	if err != nil || params.Timeout <= 0 {
+		return Params{}, fmt.Errorf("invalid timeout %q, must be a positive duration such as 30s or 2m", *timeout)
+	}
+
+	if *proxy != "" {
+		if params.Proxy, err = ParseProxyURL(*proxy); err != nil {
+			return Params{}, err
+		}
+	}
+
+	if params.PostmanBaseURL != "" {
+		if baseURL, err := url.Parse(params.PostmanBaseURL); err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+			return Params{}, fmt.Errorf("invalid postman-base-url %q: want scheme://host[/path]", params.PostmanBaseURL)
+		}
+	}
+
+	if params.FlagCheckURL != "" {
+		if checkURL, err := url.Parse(params.FlagCheckURL); err != nil || checkURL.Scheme == "" || checkURL.Host == "" {
+			return Params{}, fmt.Errorf("invalid flag-check-url %q: want scheme://host/path", params.FlagCheckURL)
+		}
+	}
+
+	for _, pattern := range maskPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Params{}, fmt.Errorf("invalid mask pattern %q: %w", pattern, err)
+		}
+		params.MaskPatterns = append(params.MaskPatterns, re)
+	}
+
+	for _, header := range docHeaders {
+		key, value, err := parseDocHeader(header)
+		if err != nil {
+			return Params{}, err
+		}
+		if params.DocHeaders == nil {
+			params.DocHeaders = map[string]string{}
+		}
+		params.DocHeaders[key] = value
+	}
+
+	if params.Profile != "" {
+		if err := applyProfile(&params); err != nil {
+			return Params{}, err
+		}
+	}
+
+	credentialRef := *credentialsFile
+	if *credentialSource == CredentialSourceSecretManager {
+		credentialRef = *credentialsSecret
+	}
+	provider, err := NewCredentialProvider(*credentialSource, Credentials{
+		DocAPIKey:          params.DocAPIKey,
+		PostmanAPIKey:      params.PostmanAPIKey,
+		PostmanWorkspaceID: params.PostmanWorkspaceID,
+	}, credentialRef)
+	if err != nil {
+		return Params{}, err
+	}
+	creds, err := provider.Credentials()
+	if err != nil {
+		return Params{}, err
+	}
+	params.DocAPIKey = creds.DocAPIKey
+	params.PostmanAPIKey = creds.PostmanAPIKey
+	// The workspace of the Postman CLI login is only a fallback and yields to
+	// a workspace name; an ID given by flag, environment or file wins.
+	if params.WorkspaceName == "" || *credentialSource != CredentialSourceFlag || params.PostmanWorkspaceID != "" {
+		params.PostmanWorkspaceID = creds.PostmanWorkspaceID
+	}
+
+	if !slices.Contains(validModes, params.Mode) {
+		return Params{}, fmt.Errorf("invalid mode %q, must be one of: %s", params.Mode, strings.Join(validModes, ", "))
+	}
+	if *deleteOnly {
+		if params.Mode != ModeSync && params.Mode != ModeDeleteOnly {
+			return Params{}, fmt.Errorf("delete-only cannot be combined with mode %s", params.Mode)
+		}
+		params.Mode = ModeDeleteOnly
+	}
+	if params.OutputDir != "" {
+		switch params.Mode {
+		case ModeSync:
+			params.Mode = ModeFetchOnly
+		case ModeValidate, ModeDeleteOnly:
+			return Params{}, fmt.Errorf("output-dir cannot be combined with mode %s", params.Mode)
+		}
+	}
+	if params.Mode == ModeDeleteOnly && params.BatchCleanup {
+		return Params{}, errors.New("batch-cleanup cannot be combined with mode delete-only")
+	}
+
+	if params.DocAPIKey == "" && params.needsAPIKeys() && params.Mode != ModeDeleteOnly {
 		return Params{}, errors.New("doc-api-key is required")
 	}
 
-	if params.PostmanAPIKey == "" {
+	if !slices.Contains(validDocAuthTypes, params.DocAuthType) {
+		return Params{}, fmt.Errorf("invalid doc-auth-type %q, must be one of: %s", params.DocAuthType, strings.Join(validDocAuthTypes, ", "))
+	}
+	if params.DocAuthType == DocAuthBasic && params.DocAPIKey != "" && !strings.Contains(params.DocAPIKey, ":") {
+		return Params{}, errors.New("doc-auth-type basic requires a doc-api-key of the form user:pass")
+	}
+
+	if params.PostmanAPIKey == "" && params.needsAPIKeys() && (params.usesPostman() || params.Check) {
 		return Params{}, errors.New("pm-api-key is required")
 	}
 
-	if params.PostmanWorkspaceID == "" {
+	// Checking the keys needs no workspace.
+	if params.PostmanWorkspaceID == "" && params.WorkspaceName == "" && params.usesPostman() && !params.Check {
 		return Params{}, errors.New("pm-workspace-id is required")
 	}
 
+	if params.PostmanWorkspaceID != "" {
+		if err := validateWorkspaceID("pm-workspace-id", params.PostmanWorkspaceID); err != nil {
+			return Params{}, err
+		}
+	}
+
+	if params.PostmanWorkspaceID == "" && params.WorkspaceName != "" && params.EmitScript {
+		return Params{}, errors.New("emit-script requires pm-workspace-id, workspace-name is only looked up when running")
+	}
+
+	if !slices.Contains(validEnvs, params.Env) {
+		return Params{}, fmt.Errorf("invalid env %q, must be one of: %s", params.Env, strings.Join(validEnvs, ", "))
+	}
+
+	if err := validateDocURLTemplate(params.DocURLTemplate); err != nil {
+		return Params{}, err
+	}
+
+	if !slices.Contains(validStatusOutputs, params.StatusOutput) {
+		return Params{}, fmt.Errorf("invalid status-output %q, must be one of: %s", params.StatusOutput, strings.Join(validStatusOutputs, ", "))
+	}
+
+	if _, err := ParseLogLevel(params.LogLevel); err != nil {
+		return Params{}, err
+	}
+
+	if params.Quiet && params.JSONLogs {
+		return Params{}, errors.New("quiet and json-logs cannot be combined")
+	}
+
+	if params.Quiet {
+		logLevelSet := false
+		flag.Visit(func(f *flag.Flag) { logLevelSet = logLevelSet || f.Name == "log-level" })
+		if logLevelSet && params.LogLevel != "error" {
+			return Params{}, fmt.Errorf("quiet cannot be combined with log-level %s", params.LogLevel)
+		}
+		params.LogLevel = "error"
+	}
+
+	if params.WorkspaceType != "" && !slices.Contains(validWorkspaceTypes, params.WorkspaceType) {
+		return Params{}, fmt.Errorf("invalid workspace-type %q, must be one of: %s", params.WorkspaceType, strings.Join(validWorkspaceTypes, ", "))
+	}
+
+	if _, ok := shareRoles[params.Share]; params.Share != "" && !ok {
+		return Params{}, fmt.Errorf("invalid share %q, must be one of: team-view, team-edit", params.Share)
+	}
+
+	if _, ok := securitySchemes[params.InjectSecurity]; params.InjectSecurity != "" && !ok {
+		return Params{}, fmt.Errorf("invalid inject-security %q, must be one of: %s", params.InjectSecurity, strings.Join(securitySchemeNames(), ", "))
+	}
+
+	if params.ForceSecurity && params.InjectSecurity == "" {
+		return Params{}, errors.New("force-security requires inject-security")
+	}
+
+	if params.RequireOperationID != "" && !slices.Contains(validOperationIDModes, params.RequireOperationID) {
+		return Params{}, fmt.Errorf("invalid require-operation-id %q, must be one of: %s", params.RequireOperationID, strings.Join(validOperationIDModes, ", "))
+	}
+
+	if params.VerifyImport != "" && !slices.Contains(validVerifyModes, params.VerifyImport) {
+		return Params{}, fmt.Errorf("invalid verify-import %q, must be one of: %s", params.VerifyImport, strings.Join(validVerifyModes, ", "))
+	}
+
+	if params.MaxRetries < 0 {
+		return Params{}, errors.New("max-retries must not be negative")
+	}
+
+	if params.ImportMaxRetries < 0 {
+		return Params{}, errors.New("import-max-retries must not be negative")
+	}
+
+	if params.ImportMaxRetries > 0 && params.VerifyImport == "" {
+		return Params{}, errors.New("import-max-retries requires verify-import, so a retried import cannot leave duplicate collections")
+	}
+
+	if params.RetryDelay < 0 {
+		return Params{}, errors.New("retry-delay must not be negative")
+	}
+
+	if params.Concurrency < 1 {
+		return Params{}, errors.New("concurrency must be at least 1")
+	}
+
+	if params.Ordered {
+		concurrencySet := false
+		flag.Visit(func(f *flag.Flag) { concurrencySet = concurrencySet || f.Name == "concurrency" })
+		if concurrencySet && params.Concurrency != 1 {
+			return Params{}, fmt.Errorf("ordered cannot be combined with concurrency %d", params.Concurrency)
+		}
+		params.Concurrency = 1
+	}
+
+	if params.StartJitter < 0 {
+		return Params{}, errors.New("start-jitter must not be negative")
+	}
+
+	if params.MinDocSize < 0 {
+		return Params{}, errors.New("min-doc-size must not be negative")
+	}
+
+	if params.CircuitFailures < 0 {
+		return Params{}, errors.New("circuit-failures must not be negative")
+	}
+	if params.CircuitWindow <= 0 {
+		return Params{}, errors.New("circuit-window must be positive")
+	}
+
+	if params.ShutdownGrace < 0 {
+		return Params{}, errors.New("shutdown-grace must not be negative")
+	}
+
+	if params.MaxWorkspaceCollections < 0 {
+		return Params{}, errors.New("max-workspace-collections must not be negative")
+	}
+	if params.MaxConcurrentDeletes < 0 {
+		return Params{}, errors.New("max-concurrent-deletes must not be negative")
+	}
+	if params.ConcurrencyPerHost < 0 {
+		return Params{}, errors.New("concurrency-per-host must not be negative")
+	}
+	if params.RateLimit < 0 {
+		return Params{}, errors.New("rate-limit must not be negative")
+	}
+
+	if !slices.Contains(validStrategies, params.Strategy) {
+		return Params{}, fmt.Errorf("invalid strategy %q, must be one of: %s", params.Strategy, strings.Join(validStrategies, ", "))
+	}
+
+	if params.BatchCleanup && params.Strategy == StrategyImportFirst {
+		return Params{}, errors.New("batch-cleanup deletes before importing and cannot be used with strategy import-first")
+	}
+
+	if params.BatchSize < 0 {
+		return Params{}, errors.New("batch-size must not be negative")
+	}
+
+	if params.BatchSize > 0 && params.StateFile == "" {
+		return Params{}, errors.New("batch-size requires state-file to remember the position")
+	}
+
+	if params.Upsert && params.StateFile == "" {
+		return Params{}, errors.New("upsert requires state-file to remember the collections")
+	}
+
+	if params.BlockBreaking && params.StateFile == "" {
+		return Params{}, errors.New("block-breaking requires state-file to remember the last specs")
+	}
+
+	if params.AllowBreaking && !params.BlockBreaking {
+		return Params{}, errors.New("allow-breaking requires block-breaking")
+	}
+
+	if params.NotifyOnChange && params.NotifyURL == "" {
+		return Params{}, errors.New("notify-on-change requires notify-url")
+	}
+
+	if params.NotifyOnChange && params.StateFile == "" {
+		return Params{}, errors.New("notify-on-change requires state-file to remember the last outcomes")
+	}
+
+	if params.FailFast && *continueAll {
+		return Params{}, errors.New("fail-fast and continue cannot be used together")
+	}
+
+	if params.RecordFile != "" && params.ReplayFile != "" {
+		return Params{}, errors.New("record and replay cannot be used together")
+	}
+
+	if params.Env == "prod" && !params.ConfirmProd && params.mutatesPostman() {
+		return Params{}, errors.New("env prod deletes and imports collections, pass -confirm-prod to proceed")
+	}
+
 	return params, nil
 }
+
+// needsAPIKeys reports whether the selected mode sends authenticated requests.
+// The emitted script reads the keys from the environment when it runs, and a
+// replay never reaches the real APIs.
+func (p Params) needsAPIKeys() bool {
+	return !p.EmitScript && p.ReplayFile == ""
+}
+
+// usesPostman reports whether the selected mode talks to Postman at all.
+func (p Params) usesPostman() bool {
+	return p.Mode == ModeSync || p.Mode == ModeDeleteOnly
+}
+
+// mutatesPostman reports whether the selected mode deletes or imports collections.
+func (p Params) mutatesPostman() bool {
+	return !p.EmitScript && !p.Probe && !p.Check && p.CompareWorkspace == "" && !p.DryRun && p.ReplayFile == "" && p.usesPostman()
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envBool reports whether the environment variable holds a true value.
+func envBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
+
+// splitList splits a comma-separated flag value, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// applyProfile fills in the doc URL template and workspace of the selected
+// profile, unless they are set by flag or environment variable. A workspace
+// name given by flag wins over the profile's workspace too.
+func applyProfile(params *Params) error {
+	if params.ConfigFile == "" {
+		return errors.New("profile requires config")
+	}
+	p, err := loadProfile(params.ConfigFile, params.Profile)
+	if err != nil {
+		return err
+	}
+
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if p.DocURLTemplate != "" && !explicit["doc-url-template"] && os.Getenv("DOC_URL_TEMPLATE") == "" {
+		params.DocURLTemplate = p.DocURLTemplate
+	}
+	if p.Workspace != "" && params.PostmanWorkspaceID == "" && params.WorkspaceName == "" {
+		params.PostmanWorkspaceID = p.Workspace
+	}
+	return nil
+}
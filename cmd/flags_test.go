@@ -3,8 +3,11 @@ package cmd
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func resetFlags() {
@@ -33,6 +36,20 @@ func TestGetParams(t *testing.T) {
 				DocAPIKey:          "doc-key-123",
 				PostmanAPIKey:      "pm-key-456",
 				PostmanWorkspaceID: "workspace-789",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
 			},
 		},
 		{
@@ -48,6 +65,20 @@ func TestGetParams(t *testing.T) {
 				DocAPIKey:          "doc-key-cli",
 				PostmanAPIKey:      "pm-key-cli",
 				PostmanWorkspaceID: "workspace-cli",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
 			},
 		},
 		{
@@ -67,6 +98,20 @@ func TestGetParams(t *testing.T) {
 				DocAPIKey:          "doc-key-cli",
 				PostmanAPIKey:      "pm-key-cli",
 				PostmanWorkspaceID: "workspace-cli",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
 			},
 		},
 		{
@@ -109,6 +154,1260 @@ func TestGetParams(t *testing.T) {
 				DocAPIKey:          "doc-key-env",
 				PostmanAPIKey:      "pm-key-cli",
 				PostmanWorkspaceID: "workspace-cli",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "invalid env",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-env=qa",
+			},
+			wantErr:     true,
+			errContains: "invalid env",
+		},
+		{
+			name: "prod without confirmation aborts",
+			envVars: map[string]string{
+				"DOC_API_KEY":     "doc-key",
+				"PM_API_KEY":      "pm-key",
+				"PM_WORKSPACE_ID": "workspace",
+				"SYNC_ENV":        "prod",
+			},
+			args:        []string{},
+			wantErr:     true,
+			errContains: "-confirm-prod",
+		},
+		{
+			name:    "prod with confirmation proceeds",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-env=prod",
+				"-confirm-prod",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "prod",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				ConfirmProd:        true,
+			},
+		},
+		{
+			name:    "compare-workspaces in prod needs no confirmation",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-env=prod",
+				"-compare-workspaces=other",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "prod",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				CompareWorkspace:   "other",
+			},
+		},
+		{
+			name:    "delete-only does not require a doc API key",
+			envVars: map[string]string{},
+			args: []string{
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-delete-only",
+			},
+			wantErr: false,
+			expected: Params{
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeDeleteOnly,
+			},
+		},
+		{
+			name:    "ordered syncs one module at a time",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-ordered",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        1,
+				Ordered:            true,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "check does not require a workspace",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-check",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:         "doc-key",
+				PostmanAPIKey:     "pm-key",
+				Check:             true,
+				Env:               "dev",
+				StatusOutput:      "stdout",
+				MaxRetries:        defaultMaxRetries,
+				RetryDelay:        defaultRetryDelay,
+				PostmanAPIVersion: DefaultPostmanAPIVersion,
+				Concurrency:       defaultConcurrency,
+				Strategy:          StrategyDeleteFirst,
+				DocURLTemplate:    DefaultDocURLTemplate,
+				LogLevel:          "info",
+				Timeout:           defaultTimeout,
+				StartJitter:       DefaultStartJitter,
+				DocAuthType:       DocAuthAPIKey,
+				CircuitWindow:     DefaultCircuitWindow,
+				Mode:              ModeSync,
+			},
+		},
+		{
+			name:    "quiet logs errors only",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-quiet",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "error",
+				Quiet:              true,
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "json-logs",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-json-logs",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				JSONLogs:           true,
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "no-normalize imports specs as published",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-no-normalize",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				NoNormalize:        true,
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "emit-script does not require API keys",
+			envVars: map[string]string{},
+			args: []string{
+				"-pm-workspace-id=workspace",
+				"-emit-script",
+			},
+			wantErr: false,
+			expected: Params{
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				EmitScript:         true,
+			},
+		},
+		{
+			name:    "validate mode requires only the doc API key",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-mode=validate",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:         "doc-key",
+				Env:               "dev",
+				StatusOutput:      "stdout",
+				MaxRetries:        defaultMaxRetries,
+				RetryDelay:        defaultRetryDelay,
+				PostmanAPIVersion: DefaultPostmanAPIVersion,
+				Concurrency:       defaultConcurrency,
+				Strategy:          StrategyDeleteFirst,
+				DocURLTemplate:    DefaultDocURLTemplate,
+				LogLevel:          "info",
+				Timeout:           defaultTimeout,
+				StartJitter:       DefaultStartJitter,
+				DocAuthType:       DocAuthAPIKey,
+				CircuitWindow:     DefaultCircuitWindow,
+				Mode:              ModeValidate,
+			},
+		},
+		{
+			name:        "fetch-only mode still requires the doc API key",
+			envVars:     map[string]string{},
+			args:        []string{"-mode=fetch-only"},
+			wantErr:     true,
+			errContains: "doc-api-key is required",
+		},
+		{
+			name:    "output-dir does not require Postman credentials",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-output-dir=specs",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:         "doc-key",
+				Env:               "dev",
+				StatusOutput:      "stdout",
+				MaxRetries:        defaultMaxRetries,
+				RetryDelay:        defaultRetryDelay,
+				PostmanAPIVersion: DefaultPostmanAPIVersion,
+				Concurrency:       defaultConcurrency,
+				Strategy:          StrategyDeleteFirst,
+				DocURLTemplate:    DefaultDocURLTemplate,
+				LogLevel:          "info",
+				Timeout:           defaultTimeout,
+				StartJitter:       DefaultStartJitter,
+				DocAuthType:       DocAuthAPIKey,
+				CircuitWindow:     DefaultCircuitWindow,
+				Mode:              ModeFetchOnly,
+				OutputDir:         "specs",
+			},
+		},
+		{
+			name:    "retry body codes are split",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-retry-on-body-code=TRY_AGAIN, BUSY,",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				RetryBodyCodes:     []string{"TRY_AGAIN", "BUSY"},
+			},
+		},
+		{
+			name:    "module subset",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-modules=customers, Brands",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				Modules:            []string{"customers", "Brands"},
+			},
+		},
+		{
+			name:    "invalid status output",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-status-output=file",
+			},
+			wantErr:     true,
+			errContains: "invalid status-output",
+		},
+		{
+			name:    "malformed workspace id",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=1f0df51a 8658",
+			},
+			wantErr:     true,
+			errContains: `invalid pm-workspace-id "1f0df51a 8658": contains whitespace`,
+		},
+		{
+			name:    "negative start-jitter",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-start-jitter=-1s",
+			},
+			wantErr:     true,
+			errContains: "start-jitter must not be negative",
+		},
+		{
+			name:    "profile without config",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-profile=staging",
+			},
+			wantErr:     true,
+			errContains: "profile requires config",
+		},
+		{
+			name:    "delete-only with another mode",
+			envVars: map[string]string{},
+			args: []string{
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-delete-only",
+				"-mode=validate",
+			},
+			wantErr:     true,
+			errContains: "delete-only cannot be combined with mode validate",
+		},
+		{
+			name:    "delete-only with batch-cleanup",
+			envVars: map[string]string{},
+			args: []string{
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-delete-only",
+				"-batch-cleanup",
+			},
+			wantErr:     true,
+			errContains: "batch-cleanup cannot be combined with mode delete-only",
+		},
+		{
+			name:    "negative min-doc-size",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-min-doc-size=-1",
+			},
+			wantErr:     true,
+			errContains: "min-doc-size must not be negative",
+		},
+		{
+			name:    "ordered with concurrency",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-ordered",
+				"-concurrency=4",
+			},
+			wantErr:     true,
+			errContains: "ordered cannot be combined with concurrency 4",
+		},
+		{
+			name:    "invalid doc-auth-type",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-doc-auth-type=digest",
+			},
+			wantErr:     true,
+			errContains: `invalid doc-auth-type "digest"`,
+		},
+		{
+			name:    "basic doc auth without a password",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-doc-auth-type=basic",
+			},
+			wantErr:     true,
+			errContains: "doc-auth-type basic requires a doc-api-key of the form user:pass",
+		},
+		{
+			name:    "check requires the Postman API key",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-mode=fetch-only",
+				"-check",
+			},
+			wantErr:     true,
+			errContains: "pm-api-key is required",
+		},
+		{
+			name:    "negative circuit-failures",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-circuit-failures=-1",
+			},
+			wantErr:     true,
+			errContains: "circuit-failures must not be negative",
+		},
+		{
+			name:    "quiet and json-logs together",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-quiet",
+				"-json-logs",
+			},
+			wantErr:     true,
+			errContains: "quiet and json-logs cannot be combined",
+		},
+		{
+			name:    "quiet with log-level",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-quiet",
+				"-log-level=debug",
+			},
+			wantErr:     true,
+			errContains: "quiet cannot be combined with log-level debug",
+		},
+		{
+			name:    "fail-fast and continue together",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-fail-fast",
+				"-continue",
+			},
+			wantErr:     true,
+			errContains: "fail-fast and continue cannot be used together",
+		},
+		{
+			name:    "record and replay together",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-record=run.json",
+				"-replay=run.json",
+			},
+			wantErr:     true,
+			errContains: "record and replay cannot be used together",
+		},
+		{
+			name:    "invalid share visibility",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-share=public",
+			},
+			wantErr:     true,
+			errContains: "invalid share",
+		},
+		{
+			name:    "config file",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-config=modules.yaml",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				ConfigFile:         "modules.yaml",
+			},
+		},
+		{
+			name:    "invalid inject-security scheme",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-inject-security=digest",
+			},
+			wantErr:     true,
+			errContains: "invalid inject-security",
+		},
+		{
+			name:    "force-security without inject-security",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-force-security",
+			},
+			wantErr:     true,
+			errContains: "force-security requires inject-security",
+		},
+		{
+			name:    "retry settings",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-max-retries=5",
+				"-retry-delay=250ms",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         5,
+				RetryDelay:         250 * time.Millisecond,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "pinned Postman API version",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-pm-api-version=11",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  "11",
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "timeout from environment",
+			envVars: map[string]string{"HTTP_TIMEOUT": "2m"},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+			},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            2 * time.Minute,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "invalid timeout",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-timeout=soon",
+			},
+			wantErr:     true,
+			errContains: "invalid timeout",
+		},
+		{
+			name:    "negative max-concurrent-deletes",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-max-concurrent-deletes=-1",
+			},
+			wantErr:     true,
+			errContains: "max-concurrent-deletes must not be negative",
+		},
+		{
+			name:    "invalid proxy",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-proxy=proxy.internal:3128",
+			},
+			wantErr:     true,
+			errContains: "invalid proxy URL",
+		},
+		{
+			name:    "invalid postman-base-url",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-postman-base-url=api.eu.getpostman.com",
+			},
+			wantErr:     true,
+			errContains: "invalid postman-base-url",
+		},
+		{
+			name:    "invalid verify-import",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-verify-import=strict",
+			},
+			wantErr:     true,
+			errContains: "invalid verify-import",
+		},
+		{
+			name:    "import-max-retries without verify-import",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-import-max-retries=2",
+			},
+			wantErr:     true,
+			errContains: "import-max-retries requires verify-import",
+		},
+		{
+			name:    "invalid mask pattern",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-mask-pattern=tok_[",
+			},
+			wantErr:     true,
+			errContains: "invalid mask pattern",
+		},
+		{
+			name:    "malformed doc header",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-doc-header=X-Tenant-ID=acme",
+				"-doc-header=X-Region",
+			},
+			wantErr:     true,
+			errContains: `invalid doc-header "X-Region": want key=value`,
+		},
+		{
+			name:    "doc headers",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-doc-header=X-Tenant-ID=acme",
+				"-doc-header", "X-Region = eu=west",
+			},
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				DocHeaders:         map[string]string{"X-Tenant-ID": "acme", "X-Region": "eu=west"},
+			},
+		},
+		{
+			name:    "workspace name instead of ID",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-workspace-name=Internal APIs",
+			},
+			expected: Params{
+				DocAPIKey:         "doc-key",
+				PostmanAPIKey:     "pm-key",
+				WorkspaceName:     "Internal APIs",
+				Env:               "dev",
+				StatusOutput:      "stdout",
+				MaxRetries:        defaultMaxRetries,
+				RetryDelay:        defaultRetryDelay,
+				PostmanAPIVersion: DefaultPostmanAPIVersion,
+				Concurrency:       defaultConcurrency,
+				Strategy:          StrategyDeleteFirst,
+				DocURLTemplate:    DefaultDocURLTemplate,
+				LogLevel:          "info",
+				Timeout:           defaultTimeout,
+				StartJitter:       DefaultStartJitter,
+				DocAuthType:       DocAuthAPIKey,
+				CircuitWindow:     DefaultCircuitWindow,
+				Mode:              ModeSync,
+			},
+		},
+		{
+			name:    "workspace ID wins over name",
+			envVars: map[string]string{"PM_WORKSPACE_ID": "workspace-env"},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-workspace-name=Internal APIs",
+			},
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace-env",
+				WorkspaceName:      "Internal APIs",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+			},
+		},
+		{
+			name:    "emit-script with workspace name",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-workspace-name=Internal APIs",
+				"-emit-script",
+			},
+			wantErr:     true,
+			errContains: "emit-script requires pm-workspace-id",
+		},
+		{
+			name:    "invalid mode",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-mode=import",
+			},
+			wantErr:     true,
+			errContains: "invalid mode",
+		},
+		{
+			name:    "output-dir in validate mode",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-mode=validate",
+				"-output-dir=specs",
+			},
+			wantErr:     true,
+			errContains: "output-dir cannot be combined with mode validate",
+		},
+		{
+			name:    "invalid flag-check-url",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-flag-check-url=flags.internal/check",
+			},
+			wantErr:     true,
+			errContains: "invalid flag-check-url",
+		},
+		{
+			name:    "block-breaking without state-file",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-block-breaking",
+			},
+			wantErr:     true,
+			errContains: "block-breaking requires state-file",
+		},
+		{
+			name:    "allow-breaking without block-breaking",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-allow-breaking",
+			},
+			wantErr:     true,
+			errContains: "allow-breaking requires block-breaking",
+		},
+		{
+			name:    "negative concurrency-per-host",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-concurrency-per-host=-1",
+			},
+			wantErr:     true,
+			errContains: "concurrency-per-host must not be negative",
+		},
+		{
+			name:    "upsert without state-file",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-upsert",
+			},
+			wantErr:     true,
+			errContains: "upsert requires state-file",
+		},
+		{
+			name:    "zero concurrency",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-concurrency=0",
+			},
+			wantErr:     true,
+			errContains: "concurrency must be at least 1",
+		},
+		{
+			name:    "negative max-workspace-collections",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-max-workspace-collections=-5",
+			},
+			wantErr:     true,
+			errContains: "max-workspace-collections must not be negative",
+		},
+		{
+			name:    "invalid strategy",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-strategy=upsert-first",
+			},
+			wantErr:     true,
+			errContains: "invalid strategy",
+		},
+		{
+			name:    "doc-url-template without placeholder",
+			envVars: map[string]string{"DOC_URL_TEMPLATE": "https://api.vivalabs.link/v1/internal-docs"},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+			},
+			wantErr:     true,
+			errContains: "must contain exactly one %s",
+		},
+		{
+			name:    "invalid log level",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-log-level=verbose",
+			},
+			wantErr:     true,
+			errContains: "invalid log-level",
+		},
+		{
+			name:    "invalid require-operation-id",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-require-operation-id=error",
+			},
+			wantErr:     true,
+			errContains: "invalid require-operation-id",
+		},
+		{
+			name:    "notify-on-change without state-file",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-notify-url=https://hooks.example.com/sync",
+				"-notify-on-change",
+			},
+			wantErr:     true,
+			errContains: "notify-on-change requires state-file",
+		},
+		{
+			name:    "notify-on-change without notify-url",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-state-file=state.json",
+				"-notify-on-change",
+			},
+			wantErr:     true,
+			errContains: "notify-on-change requires notify-url",
+		},
+		{
+			name:    "import-first with batch cleanup",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-strategy=import-first",
+				"-batch-cleanup",
+			},
+			wantErr:     true,
+			errContains: "cannot be used with strategy import-first",
+		},
+		{
+			name:    "negative max-retries",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-max-retries=-1",
+			},
+			wantErr:     true,
+			errContains: "max-retries must not be negative",
+		},
+		{
+			name:    "batch-size without state-file",
+			envVars: map[string]string{},
+			args: []string{
+				"-doc-api-key=doc-key",
+				"-pm-api-key=pm-key",
+				"-pm-workspace-id=workspace",
+				"-batch-size=2",
+			},
+			wantErr:     true,
+			errContains: "batch-size requires state-file",
+		},
+		{
+			name: "dry-run from environment skips prod confirmation",
+			envVars: map[string]string{
+				"DOC_API_KEY":     "doc-key",
+				"PM_API_KEY":      "pm-key",
+				"PM_WORKSPACE_ID": "workspace",
+				"SYNC_ENV":        "prod",
+				"DRY_RUN":         "true",
+			},
+			args:    []string{},
+			wantErr: false,
+			expected: Params{
+				DocAPIKey:          "doc-key",
+				PostmanAPIKey:      "pm-key",
+				PostmanWorkspaceID: "workspace",
+				Env:                "prod",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				DryRun:             true,
+			},
+		},
+		{
+			name:    "replay does not require API keys",
+			envVars: map[string]string{},
+			args: []string{
+				"-pm-workspace-id=workspace",
+				"-replay=run.json",
+			},
+			wantErr: false,
+			expected: Params{
+				PostmanWorkspaceID: "workspace",
+				Env:                "dev",
+				StatusOutput:       "stdout",
+				MaxRetries:         defaultMaxRetries,
+				RetryDelay:         defaultRetryDelay,
+				PostmanAPIVersion:  DefaultPostmanAPIVersion,
+				Concurrency:        defaultConcurrency,
+				Strategy:           StrategyDeleteFirst,
+				DocURLTemplate:     DefaultDocURLTemplate,
+				LogLevel:           "info",
+				Timeout:            defaultTimeout,
+				StartJitter:        DefaultStartJitter,
+				DocAuthType:        DocAuthAPIKey,
+				CircuitWindow:      DefaultCircuitWindow,
+				Mode:               ModeSync,
+				ReplayFile:         "run.json",
 			},
 		},
 	}
@@ -120,6 +1419,13 @@ func TestGetParams(t *testing.T) {
 			os.Unsetenv("DOC_API_KEY")
 			os.Unsetenv("PM_API_KEY")
 			os.Unsetenv("PM_WORKSPACE_ID")
+			os.Unsetenv("SYNC_ENV")
+			os.Unsetenv("DRY_RUN")
+			os.Unsetenv("NOTIFY_URL")
+			os.Unsetenv("SLACK_WEBHOOK_URL")
+			os.Unsetenv("DOC_URL_TEMPLATE")
+			os.Unsetenv("HTTP_TIMEOUT")
+			t.Setenv("HOME", t.TempDir())
 
 			// Set up environment variables
 			for key, value := range tt.envVars {
@@ -149,9 +1455,211 @@ func TestGetParams(t *testing.T) {
 				return
 			}
 
-			if got != tt.expected {
+			if !reflect.DeepEqual(got, tt.expected) {
 				t.Errorf("GetParams() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
+
+func TestGetParams_PostmanConfigFallback(t *testing.T) {
+	home := t.TempDir()
+	writePostmanRC(t, home, `{"login":{"_profiles":[{"alias":"PostmanDefault","postmanApiKey":"PMAK-from-file","workspaceId":"workspace-from-file"}]}}`)
+
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		args          []string
+		wantKey       string
+		wantWorkspace string
+	}{
+		{
+			name:          "flags and env absent",
+			args:          []string{"-doc-api-key=doc-key"},
+			wantKey:       "PMAK-from-file",
+			wantWorkspace: "workspace-from-file",
+		},
+		{
+			name:          "env takes precedence",
+			envVars:       map[string]string{"PM_API_KEY": "pm-key-env"},
+			args:          []string{"-doc-api-key=doc-key"},
+			wantKey:       "pm-key-env",
+			wantWorkspace: "workspace-from-file",
+		},
+		{
+			name:          "workspace name takes precedence over the login workspace",
+			args:          []string{"-doc-api-key=doc-key", "-workspace-name=Internal APIs"},
+			wantKey:       "PMAK-from-file",
+			wantWorkspace: "",
+		},
+		{
+			name:          "flags take precedence",
+			envVars:       map[string]string{"PM_API_KEY": "pm-key-env"},
+			args:          []string{"-doc-api-key=doc-key", "-pm-api-key=pm-key-cli", "-pm-workspace-id=workspace-cli"},
+			wantKey:       "pm-key-cli",
+			wantWorkspace: "workspace-cli",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			t.Setenv("HOME", home)
+			for _, key := range []string{"DOC_API_KEY", "PM_API_KEY", "PM_WORKSPACE_ID", "SYNC_ENV", "DRY_RUN", "NOTIFY_URL", "SLACK_WEBHOOK_URL", "DOC_URL_TEMPLATE", "HTTP_TIMEOUT"} {
+				t.Setenv(key, tt.envVars[key])
+			}
+
+			originalArgs := os.Args
+			os.Args = append([]string{"test"}, tt.args...)
+			defer func() { os.Args = originalArgs }()
+
+			got, err := GetParams()
+			if err != nil {
+				t.Fatalf("GetParams() error = %v", err)
+			}
+			if got.PostmanAPIKey != tt.wantKey || got.PostmanWorkspaceID != tt.wantWorkspace {
+				t.Errorf("GetParams() key, workspace = %q, %q, want %q, %q", got.PostmanAPIKey, got.PostmanWorkspaceID, tt.wantKey, tt.wantWorkspace)
+			}
+		})
+	}
+}
+
+func TestGetParams_CredentialsFile(t *testing.T) {
+	resetFlags()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PM_API_KEY", "pm-key-env")
+
+	path := filepath.Join(t.TempDir(), "creds.yaml")
+	if err := os.WriteFile(path, []byte("docApiKey: doc-key\npmApiKey: pm-key\npmWorkspaceId: workspace\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	originalArgs := os.Args
+	os.Args = []string{"test", "-credential-source=file", "-credentials-file=" + path}
+	defer func() { os.Args = originalArgs }()
+
+	got, err := GetParams()
+	if err != nil {
+		t.Fatalf("GetParams() error = %v", err)
+	}
+	if got.DocAPIKey != "doc-key" || got.PostmanAPIKey != "pm-key" || got.PostmanWorkspaceID != "workspace" {
+		t.Errorf("GetParams() credentials = %q, %q, %q, want those of the file", got.DocAPIKey, got.PostmanAPIKey, got.PostmanWorkspaceID)
+	}
+}
+
+func TestGetParams_Profile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	profiles := `profiles:
+  staging:
+    docURLTemplate: https://api.%s.vivalabs-staging.link/v1/internal-docs
+    workspace: workspace-staging
+    modules:
+      Customers: Customers Module API
+  prod:
+    modules:
+      Customers: Customers Module API
+`
+	if err := os.WriteFile(path, []byte(profiles), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		args          []string
+		wantTemplate  string
+		wantWorkspace string
+		errContains   string
+	}{
+		{
+			name:          "profile values",
+			args:          []string{"-profile=staging"},
+			wantTemplate:  "https://api.%s.vivalabs-staging.link/v1/internal-docs",
+			wantWorkspace: "workspace-staging",
+		},
+		{
+			name:          "flags override the profile",
+			args:          []string{"-profile=staging", "-doc-url-template=https://%s.example.com/docs", "-pm-workspace-id=workspace-cli"},
+			wantTemplate:  "https://%s.example.com/docs",
+			wantWorkspace: "workspace-cli",
+		},
+		{
+			name:          "environment overrides the profile",
+			envVars:       map[string]string{"DOC_URL_TEMPLATE": "https://%s.example.com/docs", "PM_WORKSPACE_ID": "workspace-env"},
+			args:          []string{"-profile=staging"},
+			wantTemplate:  "https://%s.example.com/docs",
+			wantWorkspace: "workspace-env",
+		},
+		{
+			name:          "profile without template or workspace",
+			args:          []string{"-profile=prod", "-pm-workspace-id=workspace-cli"},
+			wantTemplate:  DefaultDocURLTemplate,
+			wantWorkspace: "workspace-cli",
+		},
+		{
+			name:        "unknown profile",
+			args:        []string{"-profile=qa"},
+			errContains: `profile "qa" not found in ` + path + ", want one of: prod, staging",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("DOC_API_KEY", "doc-key")
+			t.Setenv("PM_API_KEY", "pm-key")
+			t.Setenv("DOC_URL_TEMPLATE", "")
+			t.Setenv("PM_WORKSPACE_ID", "")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			originalArgs := os.Args
+			os.Args = append([]string{"test", "-config=" + path}, tt.args...)
+			defer func() { os.Args = originalArgs }()
+
+			got, err := GetParams()
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("GetParams() error = %v, want it to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetParams() error = %v", err)
+			}
+			if got.DocURLTemplate != tt.wantTemplate || got.PostmanWorkspaceID != tt.wantWorkspace {
+				t.Errorf("GetParams() template, workspace = %q, %q, want %q, %q", got.DocURLTemplate, got.PostmanWorkspaceID, tt.wantTemplate, tt.wantWorkspace)
+			}
+		})
+	}
+}
+
+func TestGetParams_MalformedPostmanConfig(t *testing.T) {
+	resetFlags()
+	home := t.TempDir()
+	writePostmanRC(t, home, `{"login":`)
+	t.Setenv("HOME", home)
+	t.Setenv("PM_API_KEY", "")
+	t.Setenv("PM_WORKSPACE_ID", "")
+
+	originalArgs := os.Args
+	os.Args = []string{"test", "-doc-api-key=doc-key"}
+	defer func() { os.Args = originalArgs }()
+
+	if _, err := GetParams(); err == nil || !strings.Contains(err.Error(), "parsing Postman config file") {
+		t.Errorf("GetParams() error = %v, want parse error", err)
+	}
+}
+
+func writePostmanRC(t *testing.T, home, content string) {
+	t.Helper()
+	dir := filepath.Join(home, ".postman")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "postmanrc"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
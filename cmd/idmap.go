@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// IDMap maps each module to the uid of its Postman collection and is kept in
+// the -id-map-file between runs, so collections keep their uid. It is safe
+// for use by the concurrently processed modules of a run.
+type IDMap struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// WithIDMap makes the client update the collection mapped to a module in
+// place, deleting only the other collections of the same name, and record
+// the collection it imports for every module that has none yet.
+func WithIDMap(ids *IDMap) ClientOption {
+	return func(c *APIClient) {
+		c.idMap = ids
+	}
+}
+
+// LoadIDMap reads an id map file. A missing file yields an empty map.
+func LoadIDMap(path string) (*IDMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &IDMap{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading id map file: %w", err)
+	}
+
+	var ids map[string]string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("parsing id map file: %w", err)
+	}
+
+	return &IDMap{ids: ids}, nil
+}
+
+// Save writes the id map file as a JSON object of module names to uids.
+func (m *IDMap) Save(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := m.ids
+	if ids == nil {
+		ids = map[string]string{}
+	}
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling id map: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// CollectionID returns the uid mapped to the module.
+func (m *IDMap) CollectionID(module string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.ids[module]
+	return id, ok
+}
+
+// SetCollectionID maps the module to the uid of its collection.
+func (m *IDMap) SetCollectionID(module, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ids == nil {
+		m.ids = map[string]string{}
+	}
+	m.ids[module] = id
+}
+
+// Forget removes the module's mapping, whose collection no longer exists.
+func (m *IDMap) Forget(module string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ids, module)
+}
+
+// recordedCollectionGone reports whether err says the prepared target no
+// longer exists while it was only recorded, by the id map or the upsert
+// state, and not configured. The recording is then forgotten, so the module
+// can be imported again instead of failing on every run.
+func (c *APIClient) recordedCollectionGone(ctx context.Context, prepared *PreparedModule, err error) bool {
+	if !errors.Is(err, errCollectionGone) {
+		return false
+	}
+	if _, configured := c.collectionUIDs[prepared.ModuleName]; configured {
+		return false
+	}
+
+	c.log.WarnContext(ctx, "recorded collection no longer exists, importing it again", "collection", prepared.Target.UpdateKey())
+	if c.idMap != nil {
+		c.idMap.Forget(prepared.ModuleName)
+	}
+	if c.state != nil {
+		c.state.Forget(prepared.ModuleName)
+	}
+	return true
+}
+
+// strayCollections returns the collections named like the module's other
+// than its mapped collection id, which are deleted as duplicates.
+func (c *APIClient) strayCollections(ctx context.Context, name, workspaceID, id string) ([]CollectionRef, error) {
+	refs, err := c.getCollectionsByName(ctx, name, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var strays []CollectionRef
+	for _, ref := range refs {
+		if ref.UID != id && ref.ID != id {
+			strays = append(strays, ref)
+		}
+	}
+	return strays, nil
+}
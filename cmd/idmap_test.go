@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAPIClient_IDMapKeepsCollectionUID(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+
+	listed := `{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`
+	created := `{"collections":[{"id":"c2","uid":"1-c2","name":"Customers Module API"}]}`
+	var requests []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections":
+			w.Write([]byte(listed))
+		case r.Method == "GET":
+			w.Write([]byte(convertedCollection))
+		case r.Method == "POST":
+			w.Write([]byte(created))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	path := filepath.Join(t.TempDir(), "ids.json")
+	run := func() {
+		t.Helper()
+		ids, err := LoadIDMap(path)
+		if err != nil {
+			t.Fatalf("LoadIDMap() error = %v", err)
+		}
+		client := NewAPIClient("doc-key", "pm-key", WithIDMap(ids), WithOutput(io.Discard))
+		client.postmanBaseURL = postman.URL
+		client.docURL = func(string) string { return docServer.URL }
+
+		requests = nil
+		if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+			t.Fatalf("ProcessModule() error = %v", err)
+		}
+		if err := ids.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	run()
+	if want := []string{"GET /collections", "DELETE /collections/c1", "POST /import/openapi"}; !slices.Equal(requests, want) {
+		t.Errorf("first run requests = %v, want %v", requests, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"Customers\": \"1-c2\"\n}"; string(data) != want {
+		t.Errorf("id map file = %s, want %s", data, want)
+	}
+
+	// A stray copy appeared next to the mapped collection since.
+	listed = `{"collections":[
+		{"id":"c2","uid":"1-c2","name":"Customers Module API"},
+		{"id":"c3","uid":"1-c3","name":"Customers Module API"}
+	]}`
+	created = `{"collections":[{"id":"c4","uid":"1-c4","name":"Customers"}]}`
+	run()
+	want := []string{"GET /collections", "DELETE /collections/c3", "POST /import/openapi", "GET /collections/1-c4", "DELETE /collections/c4", "PUT /collections/1-c2"}
+	if !slices.Equal(requests, want) {
+		t.Errorf("second run requests = %v, want %v", requests, want)
+	}
+}
+
+func TestAPIClient_IDMapReimportsDeletedCollection(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+
+	imports := 0
+	var requests []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections":
+			w.Write([]byte(`{"collections":[]}`))
+		case r.Method == "GET":
+			w.Write([]byte(convertedCollection))
+		case r.Method == "POST":
+			imports++
+			fmt.Fprintf(w, `{"collections":[{"id":"c%d","uid":"1-c%d","name":"Customers"}]}`, imports, imports)
+		case r.Method == "PUT":
+			// The mapped collection was deleted in Postman.
+			http.NotFound(w, r)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	ids := &IDMap{}
+	ids.SetCollectionID("Customers", "1-c9")
+	client := NewAPIClient("doc-key", "pm-key", WithIDMap(ids), WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v, want the module imported again", err)
+	}
+
+	want := []string{
+		"GET /collections",
+		"POST /import/openapi", "GET /collections/1-c1", "DELETE /collections/c1", "PUT /collections/1-c9",
+		"POST /import/openapi", "PATCH /collections/1-c2",
+	}
+	if !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	if id, _ := ids.CollectionID("Customers"); id != "1-c2" {
+		t.Errorf("mapped collection = %q, want the imported 1-c2", id)
+	}
+}
+
+func TestLoadIDMap(t *testing.T) {
+	ids, err := LoadIDMap(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadIDMap() of a missing file error = %v", err)
+	}
+	if _, ok := ids.CollectionID("Customers"); ok {
+		t.Error("missing file yielded a mapped collection")
+	}
+
+	path := filepath.Join(t.TempDir(), "ids.json")
+	if err := os.WriteFile(path, []byte(`not json`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadIDMap(path); err == nil {
+		t.Error("LoadIDMap() of a malformed file error = nil")
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ImportOptions are the options of a module's OpenAPI import. The zero value
+// sends none, so Postman imports the collection with its defaults.
+type ImportOptions struct {
+	// FolderStrategy groups the requests of the collection into folders by
+	// path or by tag, one of validFolderStrategies.
+	FolderStrategy string `yaml:"folderStrategy" json:"folderStrategy,omitempty"`
+	// Tags are attached to the imported collection.
+	Tags []string `yaml:"tags" json:"tags,omitempty"`
+}
+
+var validFolderStrategies = []string{"Paths", "Tags"}
+
+func (o ImportOptions) isZero() bool {
+	return o.FolderStrategy == "" && len(o.Tags) == 0
+}
+
+func (o ImportOptions) validate() error {
+	if o.FolderStrategy != "" && !slices.Contains(validFolderStrategies, o.FolderStrategy) {
+		return fmt.Errorf("invalid folderStrategy %q, must be one of: %s", o.FolderStrategy, strings.Join(validFolderStrategies, ", "))
+	}
+	return nil
+}
+
+// WithImportOptions sets the import options per module. Modules without an
+// entry are imported without options.
+func WithImportOptions(options map[string]ImportOptions) ClientOption {
+	return func(c *APIClient) {
+		c.importOptions = options
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportPayload_Options(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeImportPayload(&b, `{"openapi":"3.0.0"}`, ImportOptions{}); err != nil {
+		t.Fatalf("writeImportPayload() error = %v", err)
+	}
+	payload := b.Bytes()
+	if strings.Contains(string(payload), "options") {
+		t.Errorf("payload = %s, want no options by default", payload)
+	}
+
+	b.Reset()
+	if err := writeImportPayload(&b, `{"openapi":"3.0.0"}`, ImportOptions{FolderStrategy: "Tags", Tags: []string{"commerce"}}); err != nil {
+		t.Fatalf("writeImportPayload() error = %v", err)
+	}
+	payload = b.Bytes()
+	var got struct {
+		Options map[string]any `json:"options"`
+	}
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"folderStrategy": "Tags", "tags": []any{"commerce"}}
+	if !reflect.DeepEqual(got.Options, want) {
+		t.Errorf("options = %v, want %v", got.Options, want)
+	}
+}
+
+func TestAPIClient_ImportModuleOptions(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Orders Module API"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithImportOptions(map[string]ImportOptions{
+		"Orders": {FolderStrategy: "Paths"},
+	}))
+	client.postmanBaseURL = server.URL
+
+	err := client.ImportModule(t.Context(), &PreparedModule{
+		ModuleName:     "Orders",
+		CollectionName: "Orders Module API",
+		WorkspaceID:    "workspace",
+		Doc:            `{"openapi":"3.0.0","paths":{"/orders":{"get":{}}}}`,
+	})
+	if err != nil {
+		t.Fatalf("ImportModule() error = %v", err)
+	}
+
+	if options, _ := received["options"].(map[string]any); options["folderStrategy"] != "Paths" {
+		t.Errorf("import options = %v, want folderStrategy Paths", received["options"])
+	}
+}
+
+func TestWriteScript_ImportOptions(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:       map[string]string{"Orders": "Orders Module API"},
+		ImportOptions: map[string]ImportOptions{"Orders": {FolderStrategy: "Tags"}},
+	}
+
+	var b strings.Builder
+	if err := NewAPIClient("", "", WithOutput(io.Discard)).WriteScript(&b, config, "workspace"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+	if want := `--argjson options '{"folderStrategy":"Tags"}'`; !strings.Contains(b.String(), want) {
+		t.Errorf("script should contain %s:\n%s", want, b.String())
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// WithImportRetries sets how many times an import that fails transiently is
+// retried. An import is a POST that may have created its collection even when
+// its response was lost, so imports are only retried while verification is
+// enabled with WithVerifyImport, and the duplicates a retry created are then
+// deleted. To find them, a retried import is sent with a marked info.title. The default is not to retry imports at all.
+func WithImportRetries(maxRetries int) ClientOption {
+	return func(c *APIClient) {
+		c.importMaxRetries = maxRetries
+	}
+}
+
+// importRetries returns how many times an import may be retried.
+func (c *APIClient) importRetries() int {
+	if c.verifyImport == "" {
+		return 0
+	}
+	return c.importMaxRetries
+}
+
+// markImport returns doc with info.title set to a title no other collection
+// has, derived from collectionName. Postman names imported collections after
+// info.title, so every collection an import of the returned doc creates,
+// including those of attempts whose response was lost, carries that title
+// until renamed.
+func markImport(doc, collectionName string) (string, string, error) {
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	info, _ := spec["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+		spec["info"] = info
+	}
+	title := fmt.Sprintf("%s (import %s)", collectionName, rand.Text())
+	info["title"] = title
+
+	marked, err := json.Marshal(spec)
+	if err != nil {
+		return "", "", fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return string(marked), title, nil
+}
+
+// removeDuplicateImports deletes the collections titled title, the marker of
+// a retried import, that the import did not return: those were created by an
+// earlier attempt whose response was lost. No other collection carries the
+// title, so nothing else is touched.
+func (c *APIClient) removeDuplicateImports(ctx context.Context, workspaceID, title string, imported []CollectionRef) error {
+	marked, err := c.getCollectionsByName(ctx, title, workspaceID)
+	if err != nil {
+		return fmt.Errorf("listing collections after import: %w", err)
+	}
+
+	var errs []error
+	for _, ref := range marked {
+		if slices.ContainsFunc(imported, func(k CollectionRef) bool { return k.DeleteKey() == ref.DeleteKey() }) {
+			continue
+		}
+		c.log.WarnContext(ctx, "deleting collection left by a retried import", "collection", ref.DeleteKey())
+		if err := c.deleteCollection(ctx, ref); err != nil {
+			errs = append(errs, fmt.Errorf("deleting duplicate collection %s: %w", ref.DeleteKey(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
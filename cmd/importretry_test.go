@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyImportServer is a Postman server whose first lose imports create
+// their collection but answer 502, as when the response is lost on the way
+// back. Like Postman, it names imported collections after the spec's
+// info.title.
+type flakyImportServer struct {
+	mu          sync.Mutex
+	lose        int
+	imports     int
+	lists       int
+	collections []importedCollection
+}
+
+type importedCollection struct {
+	id, name string
+}
+
+func (s *flakyImportServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == "POST":
+		var payload struct {
+			Input string `json:"input"`
+		}
+		var spec struct {
+			Info struct {
+				Title string `json:"title"`
+			} `json:"info"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		json.Unmarshal([]byte(payload.Input), &spec)
+
+		s.imports++
+		created := importedCollection{id: fmt.Sprintf("c%d", s.imports), name: spec.Info.Title}
+		s.collections = append(s.collections, created)
+		if s.imports <= s.lose {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"collections":[{"id":%q,"uid":"1-%s","name":%q}]}`, created.id, created.id, created.name)
+	case r.Method == "DELETE":
+		id := strings.TrimPrefix(r.URL.Path, "/collections/")
+		s.collections = slices.DeleteFunc(s.collections, func(c importedCollection) bool { return c.id == id })
+		w.Write([]byte(`{}`))
+	case r.Method == "PATCH":
+		var patch struct {
+			Collection struct {
+				Info struct {
+					Name string `json:"name"`
+				} `json:"info"`
+			} `json:"collection"`
+		}
+		json.NewDecoder(r.Body).Decode(&patch)
+		id := strings.TrimPrefix(r.URL.Path, "/collections/1-")
+		for i := range s.collections {
+			if s.collections[i].id == id {
+				s.collections[i].name = patch.Collection.Info.Name
+			}
+		}
+		w.Write([]byte(`{}`))
+	case r.URL.Path == "/collections":
+		s.lists++
+		var items []string
+		for _, c := range s.collections {
+			items = append(items, fmt.Sprintf(`{"id":%q,"uid":"1-%s","name":%q}`, c.id, c.id, c.name))
+		}
+		fmt.Fprintf(w, `{"collections":[%s]}`, strings.Join(items, ","))
+	default:
+		w.Write([]byte(`{"collection":{"item":[]}}`))
+	}
+}
+
+func TestAPIClient_ImportRetries(t *testing.T) {
+	retry := []ClientOption{WithImportRetries(2), WithVerifyImport(VerifyWarn)}
+	manual := importedCollection{id: "m1", name: "Customers"}
+
+	tests := []struct {
+		name            string
+		opts            []ClientOption
+		lose            int
+		existing        []importedCollection
+		wantErr         bool
+		wantImports     int
+		wantLists       int
+		wantCollections []importedCollection
+	}{
+		{
+			name:            "not retried by default",
+			lose:            1,
+			wantErr:         true,
+			wantImports:     1,
+			wantCollections: []importedCollection{{id: "c1", name: "Customers"}},
+		},
+		{
+			name:            "not retried without verification",
+			opts:            []ClientOption{WithImportRetries(2)},
+			lose:            1,
+			wantErr:         true,
+			wantImports:     1,
+			wantCollections: []importedCollection{{id: "c1", name: "Customers"}},
+		},
+		{
+			name:            "retried with verification",
+			opts:            retry,
+			lose:            1,
+			wantImports:     2,
+			wantLists:       1,
+			wantCollections: []importedCollection{{id: "c2", name: "Customers Module API"}},
+		},
+		{
+			name:            "retry keeps a collection titled like the spec",
+			opts:            retry,
+			lose:            1,
+			existing:        []importedCollection{manual},
+			wantImports:     2,
+			wantLists:       1,
+			wantCollections: []importedCollection{manual, {id: "c2", name: "Customers Module API"}},
+		},
+		{
+			name:            "nothing to remove without a retry",
+			opts:            retry,
+			existing:        []importedCollection{manual},
+			wantImports:     1,
+			wantCollections: []importedCollection{manual, {id: "c1", name: "Customers Module API"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postman := &flakyImportServer{lose: tt.lose, collections: slices.Clone(tt.existing)}
+			server := httptest.NewServer(postman)
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", append(tt.opts, WithOutput(io.Discard))...)
+			client.postmanBaseURL = server.URL
+			client.sleep = func(context.Context, time.Duration) error { return nil }
+
+			err := client.ImportModule(t.Context(), &PreparedModule{
+				ModuleName:     "Customers",
+				CollectionName: "Customers Module API",
+				WorkspaceID:    "workspace",
+				Doc:            upsertSpec,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportModule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if postman.imports != tt.wantImports {
+				t.Errorf("imports = %d, want %d", postman.imports, tt.wantImports)
+			}
+			if postman.lists != tt.wantLists {
+				t.Errorf("collection lists = %d, want %d", postman.lists, tt.wantLists)
+			}
+			if !slices.Equal(postman.collections, tt.wantCollections) {
+				t.Errorf("collections = %v, want %v", postman.collections, tt.wantCollections)
+			}
+		})
+	}
+}
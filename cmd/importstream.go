@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// writeImportPayload writes the body of an OpenAPI import request to w, with
+// the options only when some are set. The spec is escaped as it is written,
+// so the payload is never held in memory as a whole. The bytes are those
+// json.Marshal produces for the payload.
+func writeImportPayload(w io.Writer, openAPIData string, options ImportOptions) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(`{"input":`)
+	writeJSONString(bw, openAPIData)
+	if !options.isZero() {
+		optionsJSON, err := json.Marshal(options)
+		if err != nil {
+			return fmt.Errorf("marshaling payload: %w", err)
+		}
+		bw.WriteString(`,"options":`)
+		bw.Write(optionsJSON)
+	}
+	bw.WriteString(`,"type":"string"}`)
+
+	return bw.Flush()
+}
+
+// importPayloadSize returns the length of the import payload without
+// building it.
+func importPayloadSize(openAPIData string, options ImportOptions) (int64, error) {
+	var n countingWriter
+	if err := writeImportPayload(&n, openAPIData, options); err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+type countingWriter int64
+
+func (n *countingWriter) Write(p []byte) (int, error) {
+	*n += countingWriter(len(p))
+	return len(p), nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString writes s as a JSON string, escaped the way encoding/json
+// escapes it: with HTML characters escaped and invalid UTF-8 replaced.
+func writeJSONString(w *bufio.Writer, s string) {
+	w.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			w.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				w.WriteByte('\\')
+				w.WriteByte(b)
+			case '\b':
+				w.WriteString(`\b`)
+			case '\f':
+				w.WriteString(`\f`)
+			case '\n':
+				w.WriteString(`\n`)
+			case '\r':
+				w.WriteString(`\r`)
+			case '\t':
+				w.WriteString(`\t`)
+			default:
+				w.WriteString(`\u00`)
+				w.WriteByte(hexDigits[b>>4])
+				w.WriteByte(hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			w.WriteString(s[start:i])
+			w.WriteString("\ufffd")
+		case r == '\u2028' || r == '\u2029':
+			w.WriteString(s[start:i])
+			w.WriteString(`\u202`)
+			w.WriteByte(hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	w.WriteString(s[start:])
+	w.WriteByte('"')
+}
+
+// importBody returns a function creating the import request body, which
+// writes the payload, gzip-compressed if asked, as the body is read. It
+// serves as the request's GetBody, so every retry gets a fresh body.
+func importBody(openAPIData string, options ImportOptions, compress bool) func() (io.ReadCloser, error) {
+	write := func(w io.Writer) error {
+		if !compress {
+			return writeImportPayload(w, openAPIData, options)
+		}
+
+		zw := gzip.NewWriter(w)
+		if err := writeImportPayload(zw, openAPIData, options); err != nil {
+			return fmt.Errorf("compressing payload: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compressing payload: %w", err)
+		}
+		return nil
+	}
+
+	return func() (io.ReadCloser, error) {
+		return &payloadStream{write: write}, nil
+	}
+}
+
+// payloadStream is a body produced by write while it is read. The writer
+// starts with the first Read or Close and stops once the body is closed, so
+// a body that is never sent leaves nothing behind.
+type payloadStream struct {
+	write func(io.Writer) error
+	once  sync.Once
+	pr    *io.PipeReader
+}
+
+func (s *payloadStream) pipe() *io.PipeReader {
+	s.once.Do(func() {
+		pr, pw := io.Pipe()
+		s.pr = pr
+		go func() { pw.CloseWithError(s.write(pw)) }()
+	})
+	return s.pr
+}
+
+func (s *payloadStream) Read(p []byte) (int, error) {
+	return s.pipe().Read(p)
+}
+
+func (s *payloadStream) Close() error {
+	return s.pipe().Close()
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// marshalImportPayload builds the import payload the simple way, which the
+// streamed payload must match byte for byte.
+func marshalImportPayload(t testing.TB, openAPIData string, options ImportOptions) []byte {
+	t.Helper()
+
+	payload := map[string]any{"type": "string", "input": openAPIData}
+	if !options.isZero() {
+		payload["options"] = options
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payloadJSON
+}
+
+func TestWriteImportPayload_MatchesMarshal(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		doc     string
+		options ImportOptions
+	}{
+		{name: "plain", doc: `{"openapi":"3.0.0"}`},
+		{name: "options", doc: `{"openapi":"3.0.0"}`, options: ImportOptions{FolderStrategy: "Tags", Tags: []string{"commerce"}}},
+		{name: "escapes", doc: "{\n\t\"description\": \"a \\\"quoted\\\" <b>&amp;</b>\r\b\f\x00\x1f\"\n}"},
+		{name: "unicode", doc: "café \u2028 \u2029 \U0001F600 日本"},
+		{name: "invalid UTF-8", doc: "a\xffb\xe2\x82c\xc3"},
+		{name: "empty", doc: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := writeImportPayload(&b, tt.doc, tt.options); err != nil {
+				t.Fatalf("writeImportPayload() error = %v", err)
+			}
+			if want := marshalImportPayload(t, tt.doc, tt.options); !bytes.Equal(b.Bytes(), want) {
+				t.Errorf("writeImportPayload() =\n%s\nwant\n%s", b.Bytes(), want)
+			}
+
+			size, err := importPayloadSize(tt.doc, tt.options)
+			if err != nil || size != int64(b.Len()) {
+				t.Errorf("importPayloadSize() = %d, %v, want %d", size, err, b.Len())
+			}
+		})
+	}
+}
+
+// largeSpec returns a spec of about size bytes with many operations.
+func largeSpec(size int) string {
+	var b strings.Builder
+	b.WriteString(`{"openapi":"3.0.0","info":{"title":"Large","version":"1.0.0"},"paths":{`)
+	for i := 0; b.Len() < size; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"/items/%d":{"get":{"description":"Returns <item> %d & \"friends\"","responses":{"200":{"description":"OK"}}}}`, i, i)
+	}
+	b.WriteString("}}")
+	return b.String()
+}
+
+func TestAPIClient_ImportStreamsLargePayload(t *testing.T) {
+	spec := largeSpec(8 << 20)
+
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzip=%t", compress), func(t *testing.T) {
+			var received int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := r.Body
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					body = zr
+				}
+				received, _ = io.Copy(io.Discard, body)
+				w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Large"}]}`))
+			}))
+			defer server.Close()
+
+			options := []ClientOption{WithPostmanBaseURL(server.URL), WithOutput(io.Discard)}
+			if compress {
+				options = append(options, WithGzipImport(true))
+			}
+			client := NewAPIClient("doc-key", "pm-key", options...)
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			if _, _, err := client.importToPostman(t.Context(), spec, "Large", "workspace", ImportOptions{}); err != nil {
+				t.Fatalf("importToPostman() error = %v", err)
+			}
+			runtime.ReadMemStats(&after)
+
+			size, _ := importPayloadSize(spec, ImportOptions{})
+			if received != size {
+				t.Errorf("server received %d bytes, want %d", received, size)
+			}
+			// Buffering the payload would allocate at least its size, and
+			// marshaling it twice that.
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(spec))/2 {
+				t.Errorf("import allocated %d bytes for a %d byte spec, want it streamed", allocated, len(spec))
+			}
+		})
+	}
+}
+
+func BenchmarkWriteImportPayload(b *testing.B) {
+	spec := largeSpec(1 << 20)
+	b.SetBytes(int64(len(spec)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if err := writeImportPayload(io.Discard, spec, ImportOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
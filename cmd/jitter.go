@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultStartJitter is the start jitter window of the command line.
+const DefaultStartJitter = 250 * time.Millisecond
+
+// SetStartJitter makes every module wait a random time below window before
+// it starts its work, so modules started together do not send their first
+// Postman requests at the same instant and trip the rate limit. Zero, the
+// default, starts modules right away.
+func (s *SyncOrchestrator) SetStartJitter(window time.Duration) {
+	s.startJitter = window
+}
+
+// waitStartJitter waits a random time below the start jitter window, or
+// until ctx is done.
+func (s *SyncOrchestrator) waitStartJitter(ctx context.Context) error {
+	if s.startJitter <= 0 {
+		return nil
+	}
+	return s.sleep(ctx, rand.N(s.startJitter))
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncAllModules_StartJitter(t *testing.T) {
+	for _, batch := range []bool{false, true} {
+		var processor ModuleProcessor = &recordingProcessor{}
+		if batch {
+			processor = &phasedProcessor{}
+		}
+		config := &ModuleConfig{
+			Modules:      map[string]string{"Brands": "Brands Module API", "Classes": "Classes Module API", "Customers": "Customers Module API"},
+			BatchCleanup: batch,
+		}
+		orchestrator := NewSyncOrchestrator(processor, config)
+		orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+		orchestrator.SetStartJitter(time.Second)
+
+		var (
+			mu     sync.Mutex
+			delays []time.Duration
+		)
+		orchestrator.sleep = func(_ context.Context, d time.Duration) error {
+			mu.Lock()
+			defer mu.Unlock()
+			delays = append(delays, d)
+			return nil
+		}
+
+		if _, err := orchestrator.SyncAllModules(t.Context(), "workspace"); err != nil {
+			t.Fatalf("SyncAllModules() with batch cleanup %v error = %v", batch, err)
+		}
+
+		if len(delays) != 3 {
+			t.Fatalf("with batch cleanup %v got %d waits, want one per module", batch, len(delays))
+		}
+		for _, d := range delays {
+			if d < 0 || d >= time.Second {
+				t.Errorf("wait %v outside the jitter window", d)
+			}
+		}
+	}
+}
+
+func TestSyncAllModules_StartJitterCancelled(t *testing.T) {
+	processor := &recordingProcessor{}
+	config := &ModuleConfig{Modules: map[string]string{"Brands": "Brands Module API"}}
+	orchestrator := NewSyncOrchestrator(processor, config)
+	orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+	orchestrator.SetStartJitter(time.Hour)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	results, err := orchestrator.SyncAllModules(ctx, "workspace")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SyncAllModules() error = %v, want the deadline", err)
+	}
+	if len(processor.events) != 0 {
+		t.Errorf("processed %v while waiting to start", processor.events)
+	}
+	if results[0].Status != StatusFailed {
+		t.Errorf("result = %+v, want Brands failed while waiting", results[0])
+	}
+}
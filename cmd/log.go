@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+var validLogLevels = []string{"debug", "info", "warn", "error"}
+
+// ParseLogLevel returns the slog level named by one of validLogLevels.
+func ParseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log-level %q, must be one of: %s", name, strings.Join(validLogLevels, ", "))
+	}
+	return level, nil
+}
+
+// NewLogger returns a logger writing text records at or above level to w.
+// Records logged with a context carrying a module get a module attribute, so
+// the output of concurrently synced modules can be told apart.
+func NewLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(moduleHandler{slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// NewJSONLogger is NewLogger writing every record as one JSON object per
+// line, for log aggregation.
+func NewJSONLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(moduleHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// WithLogger sets the logger the client reports its progress to.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *APIClient) {
+		c.log = logger
+	}
+}
+
+// moduleHandler adds the module of the record's context as an attribute.
+type moduleHandler struct {
+	slog.Handler
+}
+
+func (h moduleHandler) Handle(ctx context.Context, record slog.Record) error {
+	if module := moduleFrom(ctx); module != "" {
+		record.AddAttrs(slog.String("module", module))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return moduleHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h moduleHandler) WithGroup(name string) slog.Handler {
+	return moduleHandler{h.Handler.WithGroup(name)}
+}
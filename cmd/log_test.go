@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_ModuleAttribute(t *testing.T) {
+	var out strings.Builder
+	logger := NewLogger(&out, slog.LevelInfo)
+
+	logger.InfoContext(withModule(t.Context(), "Customers"), "processed module")
+	logger.With("workspace", "ws").InfoContext(t.Context(), "cleaning up stale collections")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if !strings.HasSuffix(lines[0], `msg="processed module" module=Customers`) {
+		t.Errorf("line = %q, want the module attribute", lines[0])
+	}
+	if strings.Contains(lines[1], "module=") || !strings.Contains(lines[1], "workspace=ws") {
+		t.Errorf("line = %q, want no module attribute", lines[1])
+	}
+}
+
+func TestNewJSONLogger_OneObjectPerRecord(t *testing.T) {
+	var out strings.Builder
+	logger := NewJSONLogger(&out, slog.LevelWarn)
+
+	logger.InfoContext(withModule(t.Context(), "Customers"), "processed module")
+	logger.ErrorContext(withModule(t.Context(), "Orders"), "postman import failed", "error", "status 500")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want only the error:\n%s", len(lines), out.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("line %q is not JSON: %v", lines[0], err)
+	}
+	if record["level"] != "ERROR" || record["msg"] != "postman import failed" || record["module"] != "Orders" || record["time"] == nil {
+		t.Errorf("record = %v, want level, time, msg and module", record)
+	}
+}
+
+func TestAPIClient_DebugLogsRequests(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1"}]}`))
+			return
+		}
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	for _, tt := range []struct {
+		level slog.Level
+		want  bool
+	}{
+		{level: slog.LevelDebug, want: true},
+		{level: slog.LevelInfo, want: false},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var out strings.Builder
+			client := NewAPIClient("doc-key", "pm-key", WithLogger(NewLogger(&out, tt.level)))
+			client.docURL = func(string) string { return docServer.URL }
+			client.postmanBaseURL = postman.URL
+
+			if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+				t.Fatalf("ProcessModule() error = %v", err)
+			}
+
+			for _, want := range []string{
+				`msg="fetched doc" url=` + docServer.URL + " bytes=53 module=Customers",
+				`msg="postman response" method=GET url="` + postman.URL + `/collections?workspace=workspace&limit=100&offset=0" status=200 bytes=18 module=Customers`,
+			} {
+				if got := strings.Contains(out.String(), want); got != tt.want {
+					t.Errorf("output contains %q = %v, want %v:\n%s", want, got, tt.want, out.String())
+				}
+			}
+			if !strings.Contains(out.String(), `level=INFO msg="processed module" module=Customers`) {
+				t.Errorf("output missing progress:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"debug": slog.LevelDebug, "info": slog.LevelInfo, "WARN": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLogLevel(name); err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+
+	if _, err := ParseLogLevel("verbose"); err == nil || !strings.Contains(err.Error(), "debug, info, warn, error") {
+		t.Errorf("ParseLogLevel() error = %v, want the valid levels", err)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// maskReplacement replaces every substring matched by a mask pattern.
+const maskReplacement = "****"
+
+// WithMaskPatterns makes the client redact the substrings matching any of the
+// patterns in every spec's descriptions and examples before it is imported.
+func WithMaskPatterns(patterns ...*regexp.Regexp) ClientOption {
+	return func(c *APIClient) {
+		c.maskPatterns = patterns
+	}
+}
+
+// maskSpec redacts the matches of patterns in the description of every
+// object of the spec and in every string within an example, leaving the rest
+// of the spec untouched.
+func maskSpec(doc string, patterns []*regexp.Regexp) (string, error) {
+	var spec any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	if !maskValue(spec, patterns, false) {
+		return doc, nil
+	}
+
+	result, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling spec: %w", err)
+	}
+	return string(result), nil
+}
+
+// maskValue masks the strings within value, in place, and reports whether it
+// changed any. Within an example every string is masked; elsewhere only
+// descriptions are.
+func maskValue(value any, patterns []*regexp.Regexp, inExample bool) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if s, ok := child.(string); ok && (inExample || key == "description" || key == "example") {
+				if masked := maskString(s, patterns); masked != s {
+					v[key] = masked
+					changed = true
+				}
+				continue
+			}
+			if maskValue(child, patterns, inExample || key == "example" || key == "examples") {
+				changed = true
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if s, ok := child.(string); ok && inExample {
+				if masked := maskString(s, patterns); masked != s {
+					v[i] = masked
+					changed = true
+				}
+				continue
+			}
+			if maskValue(child, patterns, inExample) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// maskString replaces every match of the patterns in s.
+func maskString(s string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		s = pattern.ReplaceAllLiteralString(s, maskReplacement)
+	}
+	return s
+}
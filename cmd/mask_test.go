@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestMaskSpec(t *testing.T) {
+	doc := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Login for ops@example.com", "description": "Contact ops@example.com"},
+		"paths": {
+			"/login": {
+				"post": {
+					"summary": "Log in as ops@example.com",
+					"description": "Returns a token like tok_abc123",
+					"requestBody": {"content": {"application/json": {
+						"schema": {"type": "object", "properties": {"email": {"type": "string", "example": "jane@example.com", "pattern": "^tok_[a-z0-9]+$"}}},
+						"examples": {"admin": {"summary": "Admin", "value": {"email": "admin@example.com", "tokens": ["tok_zz9", "none"]}}}
+					}}}
+				}
+			}
+		}
+	}`
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`[a-z]+@example\.com`),
+		regexp.MustCompile(`tok_[a-z0-9]+`),
+	}
+
+	got, err := maskSpec(doc, patterns)
+	if err != nil {
+		t.Fatalf("maskSpec() error = %v", err)
+	}
+
+	want := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Login for ops@example.com", "description": "Contact ****"},
+		"paths": {
+			"/login": {
+				"post": {
+					"summary": "Log in as ops@example.com",
+					"description": "Returns a token like ****",
+					"requestBody": {"content": {"application/json": {
+						"schema": {"type": "object", "properties": {"email": {"type": "string", "example": "****", "pattern": "^tok_[a-z0-9]+$"}}},
+						"examples": {"admin": {"summary": "Admin", "value": {"email": "****", "tokens": ["****", "none"]}}}
+					}}}
+				}
+			}
+		}
+	}`
+	var gotSpec, wantSpec any
+	if err := json.Unmarshal([]byte(got), &gotSpec); err != nil {
+		t.Fatalf("masked spec is not JSON: %v", err)
+	}
+	json.Unmarshal([]byte(want), &wantSpec)
+	if !reflect.DeepEqual(gotSpec, wantSpec) {
+		t.Errorf("maskSpec() = %s, want %s", got, want)
+	}
+}
+
+func TestMaskSpec_NoMatchKeepsDoc(t *testing.T) {
+	doc := `{"openapi":"3.0.0","info":{"description":"Public API"},"paths":{}}`
+	got, err := maskSpec(doc, []*regexp.Regexp{regexp.MustCompile(`secret`)})
+	if err != nil {
+		t.Fatalf("maskSpec() error = %v", err)
+	}
+	if got != doc {
+		t.Errorf("maskSpec() = %s, want the doc unchanged", got)
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"text/tabwriter"
+)
+
+// SpecMetrics measures the size of a module's spec, so the growth of an API
+// surface can be tracked across runs.
+type SpecMetrics struct {
+	Paths      int `json:"paths"`
+	Operations int `json:"operations"`
+	// Schemas counts the schemas of components.schemas, or of definitions
+	// in a Swagger 2 spec.
+	Schemas int `json:"schemas"`
+	// AvgParameters is the mean number of parameters per operation,
+	// counting those declared on the operation's path item.
+	AvgParameters float64 `json:"avgParameters"`
+}
+
+// computeSpecMetrics measures a JSON spec.
+func computeSpecMetrics(doc string) (SpecMetrics, error) {
+	var spec struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return SpecMetrics{}, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	metrics := SpecMetrics{
+		Paths:   len(spec.Paths),
+		Schemas: len(spec.Components.Schemas) + len(spec.Definitions),
+	}
+
+	var parameters int
+	for _, item := range spec.Paths {
+		shared := countParameters(item["parameters"])
+		for _, method := range httpMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var operation struct {
+				Parameters json.RawMessage `json:"parameters"`
+			}
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				return SpecMetrics{}, fmt.Errorf("parsing operation: %w", err)
+			}
+			metrics.Operations++
+			parameters += shared + countParameters(operation.Parameters)
+		}
+	}
+
+	if metrics.Operations > 0 {
+		metrics.AvgParameters = float64(parameters) / float64(metrics.Operations)
+	}
+	return metrics, nil
+}
+
+// countParameters returns the length of a parameters array, or zero when it
+// is missing or not an array.
+func countParameters(raw json.RawMessage) int {
+	var parameters []json.RawMessage
+	if json.Unmarshal(raw, &parameters) != nil {
+		return 0
+	}
+	return len(parameters)
+}
+
+// specMetricsLog collects the metrics of every module's spec. It is shared
+// by all copies of a client.
+type specMetricsLog struct {
+	mu      sync.Mutex
+	modules map[string]SpecMetrics
+}
+
+func (l *specMetricsLog) record(module string, metrics SpecMetrics) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.modules == nil {
+		l.modules = map[string]SpecMetrics{}
+	}
+	l.modules[module] = metrics
+}
+
+// SpecMetrics returns the metrics of the specs fetched so far, per module.
+func (c *APIClient) SpecMetrics() map[string]SpecMetrics {
+	c.metrics.mu.Lock()
+	defer c.metrics.mu.Unlock()
+
+	return maps.Clone(c.metrics.modules)
+}
+
+// WriteSpecMetrics writes the spec metrics of every module as a table.
+func WriteSpecMetrics(w io.Writer, metrics map[string]SpecMetrics) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "MODULE\tPATHS\tOPERATIONS\tSCHEMAS\tAVG PARAMS\t")
+
+	for _, module := range slices.Sorted(maps.Keys(metrics)) {
+		m := metrics[module]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f\t\n", module, m.Paths, m.Operations, m.Schemas, m.AvgParameters)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const metricsSpec = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/customers": {
+      "get": {"parameters": [{"name": "limit", "in": "query"}, {"name": "offset", "in": "query"}]},
+      "post": {}
+    },
+    "/customers/{id}": {
+      "parameters": [{"name": "id", "in": "path"}],
+      "get": {},
+      "delete": {"parameters": [{"name": "force", "in": "query"}]},
+      "summary": "not an operation"
+    }
+  },
+  "components": {"schemas": {"Customer": {}, "Address": {}, "Error": {}}}
+}`
+
+func TestComputeSpecMetrics(t *testing.T) {
+	got, err := computeSpecMetrics(metricsSpec)
+	if err != nil {
+		t.Fatalf("computeSpecMetrics() error = %v", err)
+	}
+
+	// 2 + 0 + 1 + 2 parameters over 4 operations.
+	want := SpecMetrics{Paths: 2, Operations: 4, Schemas: 3, AvgParameters: 1.25}
+	if got != want {
+		t.Errorf("computeSpecMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestComputeSpecMetrics_Swagger(t *testing.T) {
+	got, err := computeSpecMetrics(`{"swagger":"2.0","paths":{"/a":{"get":{}}},"definitions":{"A":{}}}`)
+	if err != nil {
+		t.Fatalf("computeSpecMetrics() error = %v", err)
+	}
+
+	want := SpecMetrics{Paths: 1, Operations: 1, Schemas: 1}
+	if got != want {
+		t.Errorf("computeSpecMetrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAPIClient_SpecMetrics(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(metricsSpec))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if _, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("PrepareModule() error = %v", err)
+	}
+
+	metrics := client.SpecMetrics()
+	if got := metrics["Customers"]; got.Operations != 4 {
+		t.Errorf("SpecMetrics()[Customers] = %+v, want 4 operations", got)
+	}
+}
+
+func TestWriteSpecMetrics(t *testing.T) {
+	var b strings.Builder
+	err := WriteSpecMetrics(&b, map[string]SpecMetrics{
+		"Customers": {Paths: 2, Operations: 4, Schemas: 3, AvgParameters: 1.25},
+		"Brands":    {Paths: 1, Operations: 1},
+	})
+	if err != nil {
+		t.Fatalf("WriteSpecMetrics() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and two modules:\n%s", len(lines), b.String())
+	}
+	if fields := strings.Join(strings.Fields(lines[2]), " "); fields != "Customers 2 4 3 1.2" {
+		t.Errorf("Customers line = %q, want Customers 2 4 3 1.2", fields)
+	}
+}
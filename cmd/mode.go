@@ -0,0 +1,48 @@
+package cmd
+
+import "context"
+
+// Modes select what a run does with the fetched docs.
+const (
+	// ModeSync replaces every module's collection in Postman.
+	ModeSync = "sync"
+	// ModeFetchOnly fetches and prepares every doc as a sync would, writing
+	// it to the output directory when one is set, without contacting Postman.
+	ModeFetchOnly = "fetch-only"
+	// ModeValidate fetches every doc and checks that it can be imported,
+	// without contacting Postman.
+	ModeValidate = "validate"
+	// ModeDeleteOnly deletes every module's collection from Postman without
+	// fetching its doc or importing anything.
+	ModeDeleteOnly = "delete-only"
+)
+
+var validModes = []string{ModeSync, ModeFetchOnly, ModeValidate, ModeDeleteOnly}
+
+// WithMode sets what the client does with the fetched docs, one of
+// validModes. The default is ModeSync.
+func WithMode(mode string) ClientOption {
+	return func(c *APIClient) {
+		c.mode = mode
+	}
+}
+
+// offline reports whether the client leaves Postman alone, because it runs
+// in a mode other than ModeSync and ModeDeleteOnly or writes the specs to
+// disk.
+func (c *APIClient) offline() bool {
+	return (c.mode != "" && c.mode != ModeSync && c.mode != ModeDeleteOnly) || c.outputDir != ""
+}
+
+// finishOffline completes a prepared module without contacting Postman.
+func (c *APIClient) finishOffline(ctx context.Context, prepared *PreparedModule) error {
+	switch {
+	case c.outputDir != "":
+		return c.writeSpec(ctx, prepared)
+	case c.mode == ModeValidate:
+		c.log.InfoContext(ctx, "spec is valid")
+	default:
+		c.log.InfoContext(ctx, "fetched spec", "bytes", len(prepared.Doc))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_OfflineModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		doc     string
+		wantErr bool
+	}{
+		{name: "fetch-only", mode: ModeFetchOnly, doc: upsertSpec},
+		{name: "validate", mode: ModeValidate, doc: upsertSpec},
+		{name: "validate invalid spec", mode: ModeValidate, doc: `{"openapi":"3.0.0","paths":{}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.doc))
+			}))
+			defer docServer.Close()
+			postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected Postman request %s %s", r.Method, r.URL.Path)
+			}))
+			defer postman.Close()
+
+			client := NewAPIClient("doc-key", "", WithMode(tt.mode), WithOutput(io.Discard))
+			client.postmanBaseURL = postman.URL
+			client.docURL = func(string) string { return docServer.URL }
+
+			err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProcessModule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
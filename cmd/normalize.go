@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// defaultInfoVersion is the info.version given to specs without one.
+const defaultInfoVersion = "1.0.0"
+
+// WithNormalize makes the client fix the parts of a spec that Postman's
+// importer rejects before importing it: a missing info.version, and missing
+// or duplicate operationIds.
+func WithNormalize(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.normalize = enabled
+	}
+}
+
+// normalizeSpec fills in a missing info.version and gives every operation a
+// unique operationId: missing ones are derived from the method and path, as
+// by generateOperationIDs, and every use of an operationId after the first
+// gets a numeric suffix. It returns the spec and what it changed, and the
+// doc itself when nothing changed. Paths are visited in sorted order, so the
+// result is deterministic.
+func normalizeSpec(doc string) (string, []string, error) {
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", nil, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	var changes []string
+
+	info, ok := spec["info"].(map[string]any)
+	if !ok {
+		info = map[string]any{}
+		spec["info"] = info
+	}
+	if version, _ := info["version"].(string); version == "" {
+		info["version"] = defaultInfoVersion
+		changes = append(changes, "set info.version to "+defaultInfoVersion)
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+
+	type operation struct {
+		fields map[string]any
+		method string
+		path   string
+	}
+	var operations []operation
+	taken := map[string]bool{}
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range httpMethods {
+			fields, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			operations = append(operations, operation{fields, method, path})
+			if id, _ := fields["operationId"].(string); id != "" {
+				taken[id] = true
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, op := range operations {
+		id, _ := op.fields["operationId"].(string)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			continue
+		}
+
+		base := id
+		if base == "" {
+			base = operationIDFor(op.method, op.path)
+		}
+		unique := base
+		for n := 2; taken[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", base, n)
+		}
+		taken[unique] = true
+		seen[unique] = true
+		op.fields["operationId"] = unique
+
+		operation := strings.ToUpper(op.method) + " " + op.path
+		if id == "" {
+			changes = append(changes, fmt.Sprintf("set operationId of %s to %s", operation, unique))
+		} else {
+			changes = append(changes, fmt.Sprintf("renamed duplicate operationId %s of %s to %s", id, operation, unique))
+		}
+	}
+
+	if len(changes) == 0 {
+		return doc, nil, nil
+	}
+
+	result, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return string(result), changes, nil
+}
+
+// normalizeDoc normalizes the module's spec and logs what it changed. A doc
+// that does not parse is returned as is, for the spec validation to report.
+func (c *APIClient) normalizeDoc(ctx context.Context, doc string) string {
+	normalized, changes, err := normalizeSpec(doc)
+	if err != nil {
+		return doc
+	}
+	if len(changes) > 0 {
+		c.log.InfoContext(ctx, "normalized spec for import", "count", len(changes), "changes", strings.Join(changes, "; "))
+	}
+	return normalized
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSpec(t *testing.T) {
+	doc := `{
+  "openapi": "3.0.0",
+  "info": {"title": "Customers"},
+  "paths": {
+    "/customers": {
+      "get": {"operationId": "listCustomers"},
+      "post": {}
+    },
+    "/customers/{id}": {
+      "get": {"operationId": "listCustomers"},
+      "delete": {"operationId": "listCustomers2"}
+    }
+  }
+}`
+
+	normalized, changes, err := normalizeSpec(doc)
+	if err != nil {
+		t.Fatalf("normalizeSpec() error = %v", err)
+	}
+
+	wantChanges := []string{
+		"set info.version to 1.0.0",
+		"set operationId of POST /customers to postCustomers",
+		"renamed duplicate operationId listCustomers of GET /customers/{id} to listCustomers3",
+	}
+	if !slices.Equal(changes, wantChanges) {
+		t.Errorf("changes = %q, want %q", changes, wantChanges)
+	}
+
+	var spec struct {
+		Info  map[string]string                    `json:"info"`
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(normalized), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info["version"] != "1.0.0" || spec.Info["title"] != "Customers" {
+		t.Errorf("info = %v, want the title kept and the version filled in", spec.Info)
+	}
+	tests := []struct{ path, method, want string }{
+		{"/customers", "get", "listCustomers"},
+		{"/customers", "post", "postCustomers"},
+		{"/customers/{id}", "get", "listCustomers3"},
+		{"/customers/{id}", "delete", "listCustomers2"},
+	}
+	for _, tt := range tests {
+		if got := spec.Paths[tt.path][tt.method]["operationId"]; got != tt.want {
+			t.Errorf("operationId of %s %s = %v, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSpec_Unchanged(t *testing.T) {
+	doc := `{"openapi":"3.0.0","info":{"title":"Customers","version":"2"},"paths":{"/customers":{"get":{"operationId":"listCustomers"}}}}`
+
+	normalized, changes, err := normalizeSpec(doc)
+	if err != nil {
+		t.Fatalf("normalizeSpec() error = %v", err)
+	}
+	if normalized != doc || len(changes) != 0 {
+		t.Errorf("normalizeSpec() = %q, %q, want the doc unchanged", normalized, changes)
+	}
+}
+
+func TestAPIClient_ProcessModuleNormalizesSpec(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{"operationId":"customers"},"post":{"operationId":"customers"}}}}`))
+	}))
+	defer docServer.Close()
+
+	for _, normalize := range []bool{true, false} {
+		postman := &mockPostman{collections: `{"collections":[]}`}
+		server := httptest.NewServer(postman)
+		defer server.Close()
+
+		var out strings.Builder
+		client := NewAPIClient("doc-key", "pm-key", WithNormalize(normalize), WithOutput(&out))
+		client.postmanBaseURL = server.URL
+		client.docURL = func(string) string { return docServer.URL }
+
+		if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+			t.Fatalf("ProcessModule() error = %v", err)
+		}
+
+		if len(postman.imported) != 1 {
+			t.Fatalf("got %d imports, want 1", len(postman.imported))
+		}
+		input, _ := postman.imported[0]["input"].(string)
+		duplicates, err := duplicateOperationIDs(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if normalize && (len(duplicates) != 0 || !strings.Contains(input, `"version": "1.0.0"`)) {
+			t.Errorf("imported spec = %s, want it normalized", input)
+		}
+		if !normalize && len(duplicates) != 1 {
+			t.Errorf("imported spec = %s, want it as published", input)
+		}
+		if got := strings.Contains(out.String(), "normalized spec for import"); got != normalize {
+			t.Errorf("normalization logged = %v, want %v:\n%s", got, normalize, out.String())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Notification is the document posted to the -notify-url webhook.
+type Notification struct {
+	WorkspaceID string         `json:"workspaceId"`
+	Modules     []ModuleChange `json:"modules"`
+}
+
+// ModuleChange is the outcome of a module together with its outcome in the
+// previous run, which is empty when the module has no recorded outcome.
+type ModuleChange struct {
+	Module   string       `json:"module"`
+	Status   ModuleStatus `json:"status"`
+	Previous ModuleStatus `json:"previous,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// Changed reports whether the module's outcome differs from the previous run.
+func (m ModuleChange) Changed() bool {
+	return m.Status != m.Previous
+}
+
+// ModuleChanges pairs every result with the module's previous outcome. When
+// onlyChanged is set, modules whose outcome is the same as before are left out.
+func ModuleChanges(results []ModuleResult, previous map[string]ModuleStatus, onlyChanged bool) []ModuleChange {
+	var changes []ModuleChange
+	for _, r := range results {
+		change := ModuleChange{Module: r.Module, Status: r.Status, Previous: previous[r.Module]}
+		if r.Err != nil {
+			change.Error = r.Err.Error()
+		}
+		if onlyChanged && !change.Changed() {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Notify posts the notification as JSON to the webhook url. It does nothing
+// when the notification lists no modules.
+func (c *APIClient) Notify(ctx context.Context, url string, notification Notification) error {
+	if len(notification.Modules) == 0 {
+		return nil
+	}
+
+	return c.postWebhook(ctx, url, notification)
+}
+
+// postWebhook posts payload as JSON to a webhook url.
+func (c *APIClient) postWebhook(ctx context.Context, url string, payload any) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notification failed: %d %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNotify_OnlyOnChange(t *testing.T) {
+	var received []Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("decoding notification: %v", err)
+		}
+		received = append(received, n)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	state := &State{}
+
+	runs := [][]ModuleResult{
+		{{Module: "Brands", Status: StatusSucceeded}, {Module: "Home", Status: StatusSkipped}},
+		{{Module: "Brands", Status: StatusFailed, Err: errors.New("import failed")}, {Module: "Home", Status: StatusSkipped}},
+		{{Module: "Brands", Status: StatusFailed, Err: errors.New("import failed")}, {Module: "Home", Status: StatusSkipped}},
+	}
+	for _, results := range runs {
+		previous := state.RecordOutcomes(results)
+		notification := Notification{WorkspaceID: "workspace", Modules: ModuleChanges(results, previous, true)}
+		if err := client.Notify(t.Context(), server.URL, notification); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("got %d notifications, want one for the first run and one for the pass to fail transition: %+v", len(received), received)
+	}
+
+	want := []ModuleChange{{Module: "Brands", Status: StatusFailed, Previous: StatusSucceeded, Error: "import failed"}}
+	if !reflect.DeepEqual(received[1].Modules, want) {
+		t.Errorf("modules = %+v, want %+v", received[1].Modules, want)
+	}
+}
+
+func TestModuleChanges_All(t *testing.T) {
+	results := []ModuleResult{{Module: "Brands", Status: StatusSucceeded}, {Module: "Home", Status: StatusFailed}}
+	previous := map[string]ModuleStatus{"Brands": StatusSucceeded, "Home": StatusSucceeded}
+
+	if got := ModuleChanges(results, previous, false); len(got) != 2 {
+		t.Errorf("ModuleChanges() = %+v, want every module", got)
+	}
+	if got := ModuleChanges(results, previous, true); len(got) != 1 || got[0].Module != "Home" {
+		t.Errorf("ModuleChanges() = %+v, want only Home", got)
+	}
+}
+
+func TestNotify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	err := client.Notify(t.Context(), server.URL, Notification{Modules: []ModuleChange{{Module: "Brands"}}})
+	if err == nil || !strings.Contains(err.Error(), "notification failed: 410") {
+		t.Errorf("Notify() error = %v, want status error", err)
+	}
+}
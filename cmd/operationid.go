@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Modes of the -require-operation-id lint.
+const (
+	// OperationIDWarn reports operations without an operationId and imports
+	// the spec anyway.
+	OperationIDWarn = "warn"
+	// OperationIDFail fails the module when an operation has no operationId.
+	OperationIDFail = "fail"
+)
+
+var validOperationIDModes = []string{OperationIDWarn, OperationIDFail}
+
+// WithOperationIDs makes the client check that every operation has an
+// operationId, in one of validOperationIDModes, and, when generate is set,
+// fill in missing ones from the method and path before the check.
+func WithOperationIDs(require string, generate bool) ClientOption {
+	return func(c *APIClient) {
+		c.requireOperationID = require
+		c.generateOperationID = generate
+	}
+}
+
+// missingOperationIDs returns the operations of the spec that have no
+// operationId, as "METHOD /path" sorted by path.
+func missingOperationIDs(doc string) ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	var missing []string
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		for _, method := range httpMethods {
+			operation, ok := spec.Paths[path][method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, _ := operation["operationId"].(string); id == "" {
+				missing = append(missing, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+// WithStrict makes the client fail modules whose spec has duplicate
+// operationIds instead of only warning about them.
+func WithStrict(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.strict = enabled
+	}
+}
+
+// duplicateOperationIDs returns every operationId used by more than one
+// operation, as "id (METHOD /path, METHOD /path)" sorted by id.
+func duplicateOperationIDs(doc string) ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	operations := map[string][]string{}
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		for _, method := range httpMethods {
+			operation, _ := spec.Paths[path][method].(map[string]any)
+			if id, _ := operation["operationId"].(string); id != "" {
+				operations[id] = append(operations[id], strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	var duplicates []string
+	for _, id := range slices.Sorted(maps.Keys(operations)) {
+		if len(operations[id]) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%s (%s)", id, strings.Join(operations[id], ", ")))
+		}
+	}
+	return duplicates, nil
+}
+
+// checkDuplicateOperationIDs reports the operationIds of the module's spec
+// that are used more than once, and fails in strict mode.
+func (c *APIClient) checkDuplicateOperationIDs(ctx context.Context, doc string) error {
+	duplicates, err := duplicateOperationIDs(doc)
+	if err != nil {
+		c.log.ErrorContext(ctx, "checking operation IDs failed", "error", err)
+		return err
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	if !c.strict {
+		c.log.WarnContext(ctx, "duplicate operationIds", "count", len(duplicates), "operationIds", strings.Join(duplicates, "; "))
+		return nil
+	}
+	return fmt.Errorf("%d duplicate operationIds: %s", len(duplicates), strings.Join(duplicates, "; "))
+}
+
+// checkOperationIDs reports the operations of the module's spec that have no
+// operationId, and fails in OperationIDFail mode.
+func (c *APIClient) checkOperationIDs(ctx context.Context, doc string) error {
+	missing, err := missingOperationIDs(doc)
+	if err != nil {
+		c.log.ErrorContext(ctx, "checking operation IDs failed", "error", err)
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if c.requireOperationID == OperationIDWarn {
+		c.log.WarnContext(ctx, "operations without an operationId", "count", len(missing), "operations", strings.Join(missing, ", "))
+		return nil
+	}
+	return fmt.Errorf("%d operations without an operationId: %s", len(missing), strings.Join(missing, ", "))
+}
+
+// generateOperationIDs sets the operationId of every operation that has none
+// to one derived from its method and path, e.g. getCustomersById for
+// GET /customers/{id}. IDs that are already taken get a numeric suffix.
+// Paths are visited in sorted order, so the result is deterministic.
+func generateOperationIDs(doc string) (string, error) {
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+
+	taken := map[string]bool{}
+	var unnamed []map[string]any
+	var names []string
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range httpMethods {
+			operation, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, _ := operation["operationId"].(string); id != "" {
+				taken[id] = true
+				continue
+			}
+			unnamed = append(unnamed, operation)
+			names = append(names, operationIDFor(method, path))
+		}
+	}
+
+	if len(unnamed) == 0 {
+		return doc, nil
+	}
+
+	for i, operation := range unnamed {
+		id := names[i]
+		for n := 2; taken[id]; n++ {
+			id = fmt.Sprintf("%s%d", names[i], n)
+		}
+		taken[id] = true
+		operation["operationId"] = id
+	}
+
+	result, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return string(result), nil
+}
+
+// operationIDFor builds a camel-case operation ID from the method and the
+// path segments, turning a {param} segment into By followed by its name.
+func operationIDFor(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			b.WriteString("By")
+			segment = strings.TrimSuffix(name, "}")
+		}
+		words := strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			runes := []rune(word)
+			b.WriteRune(unicode.ToUpper(runes[0]))
+			b.WriteString(string(runes[1:]))
+		}
+	}
+
+	return b.String()
+}
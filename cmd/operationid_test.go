@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const operationIDSpec = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/customers": {
+      "get": {"operationId": "listCustomers"},
+      "post": {}
+    },
+    "/customers/{id}": {
+      "parameters": [{"name": "id", "in": "path"}],
+      "get": {},
+      "delete": {"operationId": "getCustomersById"}
+    },
+    "/customer-groups/{group_id}/members": {
+      "put": {"operationId": ""}
+    }
+  }
+}`
+
+func TestMissingOperationIDs(t *testing.T) {
+	missing, err := missingOperationIDs(operationIDSpec)
+	if err != nil {
+		t.Fatalf("missingOperationIDs() error = %v", err)
+	}
+
+	want := []string{"PUT /customer-groups/{group_id}/members", "POST /customers", "GET /customers/{id}"}
+	if !slices.Equal(missing, want) {
+		t.Errorf("missingOperationIDs() = %v, want %v", missing, want)
+	}
+}
+
+func TestGenerateOperationIDs(t *testing.T) {
+	doc, err := generateOperationIDs(operationIDSpec)
+	if err != nil {
+		t.Fatalf("generateOperationIDs() error = %v", err)
+	}
+
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ path, method, want string }{
+		{"/customers", "get", "listCustomers"},
+		{"/customers", "post", "postCustomers"},
+		{"/customers/{id}", "get", "getCustomersById2"},
+		{"/customers/{id}", "delete", "getCustomersById"},
+		{"/customer-groups/{group_id}/members", "put", "putCustomerGroupsByGroupIdMembers"},
+	}
+	for _, tt := range tests {
+		operation, _ := spec.Paths[tt.path][tt.method].(map[string]any)
+		if got := operation["operationId"]; got != tt.want {
+			t.Errorf("%s %s operationId = %v, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+
+	again, err := generateOperationIDs(operationIDSpec)
+	if err != nil || again != doc {
+		t.Errorf("generateOperationIDs() is not deterministic")
+	}
+	if missing, _ := missingOperationIDs(doc); len(missing) != 0 {
+		t.Errorf("operations still missing an operationId: %v", missing)
+	}
+}
+
+func TestGenerateOperationIDs_Unchanged(t *testing.T) {
+	doc := `{"paths":{"/customers":{"get":{"operationId":"listCustomers"}}}}`
+	if got, err := generateOperationIDs(doc); err != nil || got != doc {
+		t.Errorf("generateOperationIDs() = %q, %v, want the doc unchanged", got, err)
+	}
+}
+
+func TestAPIClient_RequireOperationID(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(operationIDSpec))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	tests := []struct {
+		name     string
+		require  string
+		generate bool
+		wantErr  bool
+		wantOut  string
+	}{
+		{name: "fail", require: OperationIDFail, wantErr: true},
+		{name: "warn", require: OperationIDWarn, wantOut: `level=WARN msg="operations without an operationId" count=3`},
+		{name: "fail after generating", require: OperationIDFail, generate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			client := NewAPIClient("doc-key", "pm-key", WithOutput(&out), WithOperationIDs(tt.require, tt.generate))
+			client.docURL = func(string) string { return docServer.URL }
+			client.postmanBaseURL = postman.URL
+
+			_, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PrepareModule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "POST /customers") {
+				t.Errorf("PrepareModule() error = %v, want it to list the operations", err)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+const duplicateOperationIDSpec = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/customers": {
+      "get": {"operationId": "getCustomer"},
+      "post": {"operationId": "saveCustomer"}
+    },
+    "/customers/{id}": {
+      "get": {"operationId": "getCustomer"},
+      "put": {"operationId": "saveCustomer"},
+      "delete": {"operationId": "deleteCustomer"}
+    }
+  }
+}`
+
+func TestDuplicateOperationIDs(t *testing.T) {
+	duplicates, err := duplicateOperationIDs(duplicateOperationIDSpec)
+	if err != nil {
+		t.Fatalf("duplicateOperationIDs() error = %v", err)
+	}
+
+	want := []string{
+		"getCustomer (GET /customers, GET /customers/{id})",
+		"saveCustomer (POST /customers, PUT /customers/{id})",
+	}
+	if !slices.Equal(duplicates, want) {
+		t.Errorf("duplicateOperationIDs() = %v, want %v", duplicates, want)
+	}
+
+	if duplicates, _ := duplicateOperationIDs(operationIDSpec); len(duplicates) != 0 {
+		t.Errorf("duplicateOperationIDs() = %v, want none", duplicates)
+	}
+}
+
+func TestAPIClient_DuplicateOperationIDs(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(duplicateOperationIDSpec))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	for _, strict := range []bool{false, true} {
+		var out strings.Builder
+		client := NewAPIClient("doc-key", "pm-key", WithOutput(&out), WithStrict(strict))
+		client.docURL = func(string) string { return docServer.URL }
+		client.postmanBaseURL = postman.URL
+
+		_, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace")
+		if strict {
+			if err == nil || !strings.Contains(err.Error(), "2 duplicate operationIds: getCustomer") {
+				t.Errorf("strict PrepareModule() error = %v, want duplicate error", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("PrepareModule() error = %v", err)
+		}
+		if want := `level=WARN msg="duplicate operationIds" count=2`; !strings.Contains(out.String(), want) {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+		if !strings.Contains(out.String(), "module=Customers") {
+			t.Errorf("output = %q, want the module reported", out.String())
+		}
+	}
+}
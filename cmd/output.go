@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+var validStatusOutputs = []string{"stdout", "stderr", "none"}
+
+// RunStatus is the machine-readable outcome of a run.
+type RunStatus struct {
+	WorkspaceID string `json:"workspaceId"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	// Modules holds the outcome of every module.
+	Modules []ModuleResult `json:"modules,omitempty"`
+	// Transfers holds the bytes each module transferred, and Total their sum.
+	Transfers map[string]ModuleTransfer `json:"transfers,omitempty"`
+	Total     *ModuleTransfer           `json:"total,omitempty"`
+	// SpecMetrics measures the spec of every module that was fetched.
+	SpecMetrics map[string]SpecMetrics `json:"specMetrics,omitempty"`
+}
+
+// NewRunStatus returns the status of a run that ended with syncErr.
+func NewRunStatus(workspaceID string, syncErr error) RunStatus {
+	status := RunStatus{WorkspaceID: workspaceID, Status: "success"}
+	if syncErr != nil {
+		status.Status = "failed"
+		status.Error = syncErr.Error()
+	}
+	return status
+}
+
+// WithTransfers returns a copy of the status reporting the given transfers.
+func (s RunStatus) WithTransfers(transfers map[string]ModuleTransfer) RunStatus {
+	total := TotalTransfer(transfers)
+	s.Transfers = transfers
+	s.Total = &total
+	return s
+}
+
+// RunOutput separates human-readable status messages from machine-readable
+// output, so stdout can carry only JSON when both are wanted.
+type RunOutput struct {
+	// Status receives progress and the final human-readable message.
+	Status io.Writer
+	// JSON receives the RunStatus document, or nil when it is not wanted.
+	JSON io.Writer
+}
+
+// NewRunOutput routes status messages to stdout, stderr or nowhere, and the
+// JSON status to stdout when jsonOutput is set.
+func NewRunOutput(statusDest string, jsonOutput bool) (*RunOutput, error) {
+	out := &RunOutput{}
+
+	switch statusDest {
+	case "stdout":
+		out.Status = os.Stdout
+	case "stderr":
+		out.Status = os.Stderr
+	case "none":
+		out.Status = io.Discard
+	default:
+		return nil, fmt.Errorf("invalid status output %q", statusDest)
+	}
+
+	if jsonOutput {
+		out.JSON = os.Stdout
+	}
+
+	return out, nil
+}
+
+// WriteJSON writes the machine-readable run status when JSON output is enabled.
+func (o *RunOutput) WriteJSON(status RunStatus) error {
+	if o.JSON == nil {
+		return nil
+	}
+
+	return json.NewEncoder(o.JSON).Encode(status)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewRunOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusDest string
+		jsonOutput bool
+		wantStatus io.Writer
+		wantJSON   io.Writer
+		wantErr    bool
+	}{
+		{name: "default", statusDest: "stdout", wantStatus: os.Stdout},
+		{name: "status to stderr with JSON on stdout", statusDest: "stderr", jsonOutput: true, wantStatus: os.Stderr, wantJSON: os.Stdout},
+		{name: "status suppressed", statusDest: "none", jsonOutput: true, wantStatus: io.Discard, wantJSON: os.Stdout},
+		{name: "invalid destination", statusDest: "file", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NewRunOutput(tt.statusDest, tt.jsonOutput)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("NewRunOutput() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewRunOutput() error = %v", err)
+			}
+
+			if out.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", out.Status, tt.wantStatus)
+			}
+			if out.JSON != tt.wantJSON {
+				t.Errorf("JSON = %v, want %v", out.JSON, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestRunOutput_SeparatesStatusFromJSON(t *testing.T) {
+	var stdout, stderr strings.Builder
+	out := &RunOutput{Status: &stderr, JSON: &stdout}
+
+	config := &ModuleConfig{
+		Modules:      map[string]string{"Customers": "Customers Module API"},
+		BatchCleanup: true,
+	}
+	orchestrator := NewSyncOrchestrator(&phasedProcessor{}, config)
+	orchestrator.SetLogger(NewLogger(out.Status, slog.LevelInfo))
+	if _, err := orchestrator.SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	if err := out.WriteJSON(NewRunStatus("workspace", errors.New("import failed"))); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	if !strings.Contains(stderr.String(), `msg="processed module" module=Customers`) {
+		t.Errorf("stderr = %q, want status messages", stderr.String())
+	}
+
+	var status RunStatus
+	if err := json.Unmarshal([]byte(stdout.String()), &status); err != nil {
+		t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+	}
+	want := RunStatus{WorkspaceID: "workspace", Status: "failed", Error: "import failed"}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+}
+
+func TestRunOutput_WriteJSONDisabled(t *testing.T) {
+	out := &RunOutput{Status: io.Discard}
+	if err := out.WriteJSON(NewRunStatus("workspace", nil)); err != nil {
+		t.Errorf("WriteJSON() error = %v, want nil when JSON output is disabled", err)
+	}
+}
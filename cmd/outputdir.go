@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithOutputDir makes the client write every module's spec to dir as
+// <module>.json instead of syncing it to Postman. No Postman request is sent.
+func WithOutputDir(dir string) ClientOption {
+	return func(c *APIClient) {
+		c.outputDir = dir
+	}
+}
+
+// writeSpec writes a prepared module's doc to the output directory.
+func (c *APIClient) writeSpec(ctx context.Context, prepared *PreparedModule) error {
+	path := filepath.Join(c.outputDir, prepared.ModuleName+".json")
+	if c.dryRun {
+		c.log.InfoContext(ctx, "dry run: would write spec", "path", path, "bytes", len(prepared.Doc))
+		return nil
+	}
+
+	if err := os.MkdirAll(c.outputDir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(prepared.Doc), 0o644); err != nil {
+		return fmt.Errorf("writing spec: %w", err)
+	}
+
+	c.log.InfoContext(ctx, "wrote spec", "path", path)
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAPIClient_OutputDir(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected Postman request %s %s", r.Method, r.URL.Path)
+	}))
+	defer postman.Close()
+
+	dir := filepath.Join(t.TempDir(), "specs")
+	client := NewAPIClient("doc-key", "", WithOutputDir(dir), WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", ""); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Customers.json"))
+	if err != nil {
+		t.Fatalf("reading written spec: %v", err)
+	}
+	var written, want any
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("written spec is not JSON: %v", err)
+	}
+	json.Unmarshal([]byte(upsertSpec), &want)
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("written spec = %s, want the fetched doc", data)
+	}
+}
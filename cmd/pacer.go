@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// paceThreshold is the remaining request budget below which the pacer starts
+// spreading requests over what is left of the rate-limit window.
+const paceThreshold = 20
+
+// rateLimitPacer spaces out Postman requests based on the X-RateLimit-Remaining
+// and X-RateLimit-Reset headers of earlier responses. It is shared by all
+// goroutines using the same client.
+type rateLimitPacer struct {
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+	// next is the send time reserved by the last delayed request.
+	next time.Time
+}
+
+func newRateLimitPacer() *rateLimitPacer {
+	return &rateLimitPacer{
+		remaining: -1,
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+// delay returns how long to wait before the next request. Nothing is delayed
+// while the budget is unknown, comfortably large, or the window has reset.
+// Otherwise every call reserves its own send time, an even share of what is
+// left of the window after the one reserved before it, so concurrent
+// requests are spread out instead of all waiting the same time.
+func (p *rateLimitPacer) delay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.remaining < 0 || p.remaining >= paceThreshold || !now.Before(p.resetAt) {
+		return 0
+	}
+
+	untilReset := p.resetAt.Sub(now)
+	if p.remaining == 0 {
+		return untilReset
+	}
+
+	if p.next.Before(now) {
+		p.next = now
+	}
+	p.next = p.next.Add(untilReset / time.Duration(p.remaining+1))
+	return p.next.Sub(now)
+}
+
+// wait blocks until the next request may be sent or ctx is done.
+func (p *rateLimitPacer) wait(ctx context.Context) error {
+	if d := p.delay(); d > 0 {
+		return p.sleep(ctx, d)
+	}
+	return nil
+}
+
+// observe records the rate-limit headers of a response. The reset header is
+// accepted both as seconds until reset and as a Unix timestamp.
+func (p *rateLimitPacer) observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.remaining = remaining
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if reset > 1_000_000_000 {
+			p.resetAt = time.Unix(reset, 0)
+		} else {
+			p.resetAt = p.now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestPacer(now time.Time) *rateLimitPacer {
+	p := newRateLimitPacer()
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func rateLimitHeader(remaining, reset string) http.Header {
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", remaining)
+	h.Set("X-RateLimit-Reset", reset)
+	return h
+}
+
+func TestRateLimitPacer_Delay(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "no headers", header: http.Header{}, want: 0},
+		{name: "plenty of budget", header: rateLimitHeader("100", "60"), want: 0},
+		{name: "budget shrinking", header: rateLimitHeader("9", "60"), want: 6 * time.Second},
+		{name: "budget nearly gone", header: rateLimitHeader("1", "60"), want: 30 * time.Second},
+		{name: "budget exhausted", header: rateLimitHeader("0", "60"), want: 60 * time.Second},
+		{name: "reset as unix timestamp", header: rateLimitHeader("0", "1700000030"), want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPacer(now)
+			p.observe(tt.header)
+
+			if got := p.delay(); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitPacer_SpreadsConcurrentRequests(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	p := newTestPacer(now)
+	p.observe(rateLimitHeader("9", "60"))
+
+	delays := make([]time.Duration, 5)
+	var wg sync.WaitGroup
+	for i := range delays {
+		wg.Go(func() { delays[i] = p.delay() })
+	}
+	wg.Wait()
+
+	got := slices.Sorted(slices.Values(delays))
+	want := []time.Duration{6 * time.Second, 12 * time.Second, 18 * time.Second, 24 * time.Second, 30 * time.Second}
+	if !slices.Equal(got, want) {
+		t.Errorf("delays = %v, want %v", got, want)
+	}
+}
+
+func TestRateLimitPacer_SpeedsUpAfterReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	p := newTestPacer(now)
+	p.observe(rateLimitHeader("0", "10"))
+
+	if p.delay() == 0 {
+		t.Fatal("delay() = 0, want a delay while the budget is exhausted")
+	}
+
+	now = now.Add(11 * time.Second)
+	p.now = func() time.Time { return now }
+
+	if got := p.delay(); got != 0 {
+		t.Errorf("delay() after reset = %v, want 0", got)
+	}
+}
+
+func TestAPIClient_PacesPostmanRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "3")
+		w.Header().Set("X-RateLimit-Reset", "8")
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	var slept []time.Duration
+	client.pacer.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	for range 2 {
+		if _, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace"); err != nil {
+			t.Fatalf("getCollectionsByName() error = %v", err)
+		}
+	}
+
+	if len(slept) != 1 {
+		t.Fatalf("slept %d times, want 1 (only after the first response)", len(slept))
+	}
+	if slept[0] <= 0 || slept[0] > 2*time.Second {
+		t.Errorf("slept %v, want roughly 8s/4", slept[0])
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// postmanRC is the part of the Postman CLI config file, written by
+// `postman login`, that the tool reads credentials from.
+type postmanRC struct {
+	Login struct {
+		Profiles []struct {
+			Alias         string `json:"alias"`
+			PostmanAPIKey string `json:"postmanApiKey"`
+			WorkspaceID   string `json:"workspaceId"`
+		} `json:"_profiles"`
+	} `json:"login"`
+}
+
+// postmanRCPath returns the location of the Postman CLI config file.
+func postmanRCPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".postman", "postmanrc"), nil
+}
+
+// loadPostmanCredentials returns the API key and, when set, the default
+// workspace of the first profile in the Postman CLI config file that has a
+// key. A missing file yields empty values.
+func loadPostmanCredentials() (apiKey, workspaceID string, err error) {
+	path, err := postmanRCPath()
+	if err != nil {
+		return "", "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("reading Postman config file: %w", err)
+	}
+
+	var rc postmanRC
+	if err := json.Unmarshal(data, &rc); err != nil {
+		return "", "", fmt.Errorf("parsing Postman config file %s: %w", path, err)
+	}
+
+	for _, profile := range rc.Login.Profiles {
+		if profile.PostmanAPIKey != "" {
+			return profile.PostmanAPIKey, profile.WorkspaceID, nil
+		}
+	}
+	return "", "", nil
+}
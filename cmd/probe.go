@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"text/tabwriter"
+)
+
+// ProbeResult records whether an endpoint answered a preflight request.
+type ProbeResult struct {
+	Endpoint string
+	URL      string
+	Up       bool
+	Status   int
+	Error    string
+}
+
+// Probe sends a cheap request to every module's doc URL and to every Postman
+// workspace the modules are synced to, workspaceID unless a module has its
+// own, and reports which of them are reachable. Nothing is synced.
+// An endpoint counts as up when it answers with a status below 500.
+func (c *APIClient) Probe(ctx context.Context, config *ModuleConfig, workspaceID string) []ProbeResult {
+	var results []ProbeResult
+
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		url, err := c.moduleDocURL(module)
+		result := ProbeResult{Endpoint: module + " doc API", URL: url}
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+		if err == nil {
+			c.setDocHeaders(req)
+			result.fill(c.httpClient.Do(req))
+		} else {
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	for _, id := range config.workspaceIDs(workspaceID) {
+		url := fmt.Sprintf("%s/workspaces/%s", c.postmanBaseURL, id)
+		result := ProbeResult{Endpoint: "Postman workspace", URL: url}
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err == nil {
+			result.fill(c.doPostman(req))
+		} else {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (r *ProbeResult) fill(resp *http.Response, err error) {
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+	resp.Body.Close()
+
+	r.Status = resp.StatusCode
+	r.Up = resp.StatusCode < http.StatusInternalServerError
+}
+
+// WriteProbeMatrix writes the probe results as a table.
+func WriteProbeMatrix(w io.Writer, results []ProbeResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tSTATE\tSTATUS\tURL\tERROR")
+
+	for _, r := range results {
+		state := "down"
+		if r.Up {
+			state = "up"
+		}
+
+		status := "-"
+		if r.Status != 0 {
+			status = fmt.Sprint(r.Status)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Endpoint, state, status, r.URL, r.Error)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_Probe(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("doc probe method = %s, want HEAD", r.Method)
+		}
+		if r.Header.Get("X-API-Key") != "doc-key" {
+			t.Errorf("doc probe X-API-Key = %q, want doc-key", r.Header.Get("X-API-Key"))
+		}
+	}))
+	defer up.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"workspace":{"id":"ws-1"}}`))
+	}))
+	defer postman.Close()
+
+	urls := map[string]string{
+		"Brands":    up.URL,
+		"Classes":   failing.URL,
+		"Customers": unreachable.URL,
+	}
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(module string) string { return urls[module] }
+
+	config := &ModuleConfig{Modules: map[string]string{
+		"Brands":    "Brands Module API",
+		"Classes":   "Classes Module API",
+		"Customers": "Customers Module API",
+	}}
+	results := client.Probe(t.Context(), config, "ws-1")
+
+	want := []struct {
+		endpoint string
+		up       bool
+		status   int
+	}{
+		{"Brands doc API", true, http.StatusOK},
+		{"Classes doc API", false, http.StatusBadGateway},
+		{"Customers doc API", false, 0},
+		{"Postman workspace", true, http.StatusOK},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Probe() returned %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Endpoint != w.endpoint || r.Up != w.up || r.Status != w.status {
+			t.Errorf("result[%d] = %+v, want endpoint %s up %v status %d", i, r, w.endpoint, w.up, w.status)
+		}
+	}
+	if results[2].Error == "" {
+		t.Error("unreachable endpoint should report its connection error")
+	}
+
+	var out strings.Builder
+	if err := WriteProbeMatrix(&out, results); err != nil {
+		t.Fatalf("WriteProbeMatrix() error = %v", err)
+	}
+	for _, line := range []string{"Brands doc API     up     200", "Classes doc API    down   502", "Postman workspace  up     200"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("matrix missing %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profile is one environment of a profiles config file: its modules and,
+// optionally, its doc URL template and Postman workspace.
+type profile struct {
+	DocURLTemplate string                 `yaml:"docURLTemplate"`
+	Workspace      string                 `yaml:"workspace"`
+	Modules        map[string]moduleEntry `yaml:"modules"`
+}
+
+// loadProfile reads the named profile from a YAML or JSON file of profiles,
+// e.g.
+//
+//	profiles:
+//	  staging:
+//	    docURLTemplate: https://api.%s.vivalabs-staging.link/v1/internal-docs
+//	    workspace: 2f8a6c1e-staging
+//	    modules:
+//	      Customers: Customers Module API
+func loadProfile(path, name string) (profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile{}, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var file struct {
+		Profiles map[string]profile `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return profile{}, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	p, ok := file.Profiles[name]
+	if !ok {
+		if len(file.Profiles) == 0 {
+			return profile{}, fmt.Errorf("profile %q not found: config file %s defines no profiles", name, path)
+		}
+		return profile{}, fmt.Errorf("profile %q not found in %s, want one of: %s", name, path, strings.Join(slices.Sorted(maps.Keys(file.Profiles)), ", "))
+	}
+	return p, nil
+}
+
+// NewModuleConfigFromProfile loads the modules of the named profile of a
+// profiles file. They are written like the modules of NewModuleConfigFromFile.
+func NewModuleConfigFromProfile(path, name string) (*ModuleConfig, error) {
+	p, err := loadProfile(path, name)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := newModuleConfig(path, p.Modules)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return config, nil
+}
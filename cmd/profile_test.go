@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewModuleConfigFromProfile(t *testing.T) {
+	path := writeConfigFile(t, "profiles.yaml", `profiles:
+  dev:
+    modules:
+      Customers: Customers Module API
+      Orders:
+        collection: Orders Module API
+        dependsOn: [Customers]
+  prod:
+    modules:
+      Customers: Customers Module API
+`)
+
+	config, err := NewModuleConfigFromProfile(path, "dev")
+	if err != nil {
+		t.Fatalf("NewModuleConfigFromProfile() error = %v", err)
+	}
+	if want := map[string]string{"Customers": "Customers Module API", "Orders": "Orders Module API"}; !reflect.DeepEqual(config.Modules, want) {
+		t.Errorf("Modules = %v, want %v", config.Modules, want)
+	}
+	if want := map[string][]string{"Orders": {"Customers"}}; !reflect.DeepEqual(config.DependsOn, want) {
+		t.Errorf("DependsOn = %v, want %v", config.DependsOn, want)
+	}
+
+	config, err = NewModuleConfigFromProfile(path, "prod")
+	if err != nil {
+		t.Fatalf("NewModuleConfigFromProfile() error = %v", err)
+	}
+	if len(config.Modules) != 1 {
+		t.Errorf("Modules = %v, want only those of prod", config.Modules)
+	}
+}
+
+func TestNewModuleConfigFromProfile_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		profile     string
+		errContains string
+	}{
+		{
+			name:        "unknown profile",
+			content:     "profiles:\n  dev:\n    modules:\n      Customers: Customers Module API\n",
+			profile:     "prod",
+			errContains: `profile "prod" not found`,
+		},
+		{
+			name:        "plain module file",
+			content:     "Customers: Customers Module API\n",
+			profile:     "dev",
+			errContains: "defines no profiles",
+		},
+		{
+			name:        "profile without modules",
+			content:     "profiles:\n  dev:\n    workspace: workspace-dev\n",
+			profile:     "dev",
+			errContains: "profile dev: config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewModuleConfigFromProfile(writeConfigFile(t, "profiles.yaml", tt.content), tt.profile)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("NewModuleConfigFromProfile() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps the Postman requests of the client, and of every copy
+// made with WithClientSettings, at perMinute requests a minute, spaced
+// evenly. Zero leaves requests unlimited apart from the rate-limit pacer.
+func WithRateLimit(perMinute int) ClientOption {
+	return func(c *APIClient) {
+		c.limiter = nil
+		if perMinute > 0 {
+			c.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), 1)
+		}
+	}
+}
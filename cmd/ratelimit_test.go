@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer server.Close()
+
+	// 1200 requests a minute allow one every 50ms.
+	const interval = 50 * time.Millisecond
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithRateLimit(1200))
+	client.postmanBaseURL = server.URL
+
+	// A burst from several modules, each with its own client copy, shares
+	// the one limiter.
+	var wg sync.WaitGroup
+	for range 5 {
+		processor, err := client.WithClientSettings(ClientSettings{})
+		if err != nil {
+			t.Fatalf("WithClientSettings() error = %v", err)
+		}
+		module := processor.(*APIClient)
+		wg.Go(func() {
+			if _, err := module.listCollections(t.Context(), "workspace"); err != nil {
+				t.Errorf("listCollections() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(times) != 5 {
+		t.Fatalf("got %d requests, want 5", len(times))
+	}
+	// The first request passes right away, the others wait their turn.
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < interval-10*time.Millisecond {
+			t.Errorf("request %d followed the previous one after %v, want about %v", i+1, gap, interval)
+		}
+	}
+}
+
+func TestWithRateLimit_Unlimited(t *testing.T) {
+	if client := NewAPIClient("doc-key", "pm-key", WithRateLimit(0)); client.limiter != nil {
+		t.Error("WithRateLimit(0) should not limit requests")
+	}
+}
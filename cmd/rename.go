@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// renameImported gives every imported collection the configured collection
+// name and, with branding, the branded description, in one update each.
+// Postman names imported collections after the spec's info.title, so
+// without the rename the next run would not find them by name to replace
+// them.
+func (c *APIClient) renameImported(ctx context.Context, imported []CollectionRef, prepared *PreparedModule) error {
+	var description string
+	if c.branding != nil {
+		specDesc, err := specDescription(prepared.Doc)
+		if err != nil {
+			return err
+		}
+		description = c.branding.describe(specDesc)
+	}
+
+	for _, ref := range imported {
+		if ref.Name == prepared.CollectionName && description == "" {
+			continue
+		}
+		if err := c.patchCollectionInfo(ctx, ref, prepared.CollectionName, description); err != nil {
+			return fmt.Errorf("updating collection %s: %w", ref.UpdateKey(), err)
+		}
+	}
+	return nil
+}
+
+// patchCollectionInfo sets the name and the description of a collection,
+// leaving the description as is when empty and the rest of the collection
+// untouched.
+func (c *APIClient) patchCollectionInfo(ctx context.Context, ref CollectionRef, name, description string) error {
+	info := map[string]any{"name": name}
+	if description != "" {
+		info["description"] = description
+	}
+	payloadJSON, err := json.Marshal(map[string]any{
+		"collection": map[string]any{"info": info},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s", c.postmanBaseURL, ref.UpdateKey())
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return postmanStatusError(resp.StatusCode, fmt.Errorf("failed to patch collection: %d %s", resp.StatusCode, string(body)))
+	}
+
+	if ref.Name != name {
+		c.log.InfoContext(ctx, "renamed collection", "collection", ref.UpdateKey(), "from", ref.Name, "to", name)
+	}
+	if description != "" {
+		c.log.InfoContext(ctx, "branded collection", "collection", ref.UpdateKey())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAPIClient_ImportRenamesCollection(t *testing.T) {
+	const spec = `{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`
+
+	tests := []struct {
+		name         string
+		importedName string
+		renameStatus int
+		branding     *Branding
+		wantRenamed  string
+		wantPatches  int
+		wantErr      string
+	}{
+		{
+			name:         "named after the spec title",
+			importedName: "Customers",
+			wantRenamed:  "Customers Module API",
+			wantPatches:  1,
+		},
+		{
+			name:         "already named as configured",
+			importedName: "Customers Module API",
+		},
+		{
+			name:         "renamed and branded in one update",
+			importedName: "Customers",
+			branding:     &Branding{Header: "Generated"},
+			wantRenamed:  "Customers Module API",
+			wantPatches:  1,
+		},
+		{
+			name:         "rename fails",
+			importedName: "Customers",
+			renameStatus: http.StatusForbidden,
+			wantErr:      "failed to patch collection: 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(spec))
+			}))
+			defer docServer.Close()
+
+			var (
+				mu      sync.Mutex
+				renamed string
+				patches int
+			)
+			postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "GET":
+					w.Write([]byte(`{"collections":[]}`))
+				case r.Method == "POST":
+					json.NewEncoder(w).Encode(map[string]any{"collections": []map[string]string{{"id": "c1", "uid": "1-c1", "name": tt.importedName}}})
+				case r.Method == "PATCH" && r.URL.Path == "/collections/1-c1":
+					if tt.renameStatus != 0 {
+						w.WriteHeader(tt.renameStatus)
+						return
+					}
+					var payload struct {
+						Collection struct {
+							Info struct {
+								Name string `json:"name"`
+							} `json:"info"`
+						} `json:"collection"`
+					}
+					json.NewDecoder(r.Body).Decode(&payload)
+					mu.Lock()
+					renamed = payload.Collection.Info.Name
+					patches++
+					mu.Unlock()
+					w.Write([]byte(`{}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer postman.Close()
+
+			options := []ClientOption{
+				WithPostmanBaseURL(postman.URL),
+				WithDocURLTemplate(docServer.URL + "/%s"),
+				WithOutput(io.Discard),
+			}
+			if tt.branding != nil {
+				options = append(options, WithBranding(tt.branding))
+			}
+			client := NewAPIClient("doc-key", "pm-key", options...)
+
+			err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ProcessModule() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessModule() error = %v", err)
+			}
+			if renamed != tt.wantRenamed {
+				t.Errorf("renamed to %q, want %q", renamed, tt.wantRenamed)
+			}
+			if patches != tt.wantPatches {
+				t.Errorf("got %d collection updates, want %d", patches, tt.wantPatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CollectionChanges lists the collections a module deleted and created.
+type CollectionChanges struct {
+	Deleted []string
+	Created string
+}
+
+// ChangeTracker is implemented by processors that track the collections of
+// every module, so the orchestrator can add the created one to the module's
+// result.
+type ChangeTracker interface {
+	CollectionChanges() map[string]CollectionChanges
+}
+
+// createdCollection returns the uid of the collection the module created,
+// when the processor tracks it.
+func (s *SyncOrchestrator) createdCollection(module string) string {
+	tracker, ok := s.processor.(ChangeTracker)
+	if !ok {
+		return ""
+	}
+	return tracker.CollectionChanges()[module].Created
+}
+
+// collectionChangeLog collects the collection changes per module. It is
+// shared by all copies of a client.
+type collectionChangeLog struct {
+	mu      sync.Mutex
+	modules map[string]CollectionChanges
+}
+
+func (l *collectionChangeLog) update(module string, fn func(*CollectionChanges)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.modules == nil {
+		l.modules = map[string]CollectionChanges{}
+	}
+	changes := l.modules[module]
+	fn(&changes)
+	l.modules[module] = changes
+}
+
+func (l *collectionChangeLog) deleted(module, id string) {
+	l.update(module, func(c *CollectionChanges) { c.Deleted = append(c.Deleted, id) })
+}
+
+func (l *collectionChangeLog) created(module, id string) {
+	l.update(module, func(c *CollectionChanges) { c.Created = id })
+}
+
+// CollectionChanges returns the collections deleted and created so far, per
+// module.
+func (c *APIClient) CollectionChanges() map[string]CollectionChanges {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+
+	changes := make(map[string]CollectionChanges, len(c.changes.modules))
+	for module, change := range c.changes.modules {
+		change.Deleted = slices.Clone(change.Deleted)
+		changes[module] = change
+	}
+	return changes
+}
+
+// Report is the JSON document written to the -report file.
+type Report struct {
+	WorkspaceID string         `json:"workspaceId"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Modules     []ReportModule `json:"modules"`
+}
+
+// ReportModule is the outcome of one module in a Report.
+type ReportModule struct {
+	Module     string       `json:"module"`
+	Collection string       `json:"collection"`
+	Status     ModuleStatus `json:"status"`
+	Deleted    []string     `json:"deletedCollectionIds"`
+	Created    string       `json:"createdCollectionId,omitempty"`
+	DurationMS int64        `json:"durationMs"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// NewReport combines the module results with the collections each module
+// deleted and created.
+func NewReport(workspaceID string, timestamp time.Time, results []ModuleResult, changes map[string]CollectionChanges) Report {
+	report := Report{WorkspaceID: workspaceID, Timestamp: timestamp, Modules: []ReportModule{}}
+	for _, r := range results {
+		module := ReportModule{
+			Module:     r.Module,
+			Collection: r.Collection,
+			Status:     r.Status,
+			Deleted:    changes[r.Module].Deleted,
+			Created:    changes[r.Module].Created,
+			DurationMS: r.Duration.Milliseconds(),
+		}
+		if module.Deleted == nil {
+			module.Deleted = []string{}
+		}
+		if r.Err != nil {
+			module.Error = r.Err.Error()
+		}
+		report.Modules = append(report.Modules, module)
+	}
+	return report
+}
+
+// WriteReport writes the report as indented JSON to path.
+func WriteReport(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling report: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
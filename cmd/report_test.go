@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport_CollectionChanges(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Brands" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"collections":[
+				{"id":"c1","uid":"1-c1","name":"Customers Module API"},
+				{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+			]}`))
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"c3","uid":"1-c3","name":"Customers Module API"}]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(module string) string { return docServer.URL + "/" + module }
+
+	config := &ModuleConfig{Modules: map[string]string{
+		"Brands":    "Brands Module API",
+		"Customers": "Customers Module API",
+	}}
+	orchestrator := NewSyncOrchestrator(client, config)
+	orchestrator.SetLogger(NewLogger(io.Discard, 0))
+
+	results, err := orchestrator.SyncAllModules(t.Context(), "workspace")
+	if err == nil {
+		t.Fatal("SyncAllModules() error = nil, want the Brands failure")
+	}
+	if results[0].CollectionUID != "" || results[1].CollectionUID != "1-c3" {
+		t.Errorf("collection uids = %q, %q, want none for Brands and 1-c3 for Customers", results[0].CollectionUID, results[1].CollectionUID)
+	}
+
+	timestamp := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteReport(path, NewReport("workspace", timestamp, results, client.CollectionChanges())); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, data)
+	}
+
+	if report.WorkspaceID != "workspace" || !report.Timestamp.Equal(timestamp) {
+		t.Errorf("report = %+v, want the workspace and timestamp", report)
+	}
+	if len(report.Modules) != 2 {
+		t.Fatalf("got %d modules, want 2", len(report.Modules))
+	}
+
+	brands, customers := report.Modules[0], report.Modules[1]
+	if brands.Status != StatusFailed || !strings.Contains(brands.Error, "unexpected status: 503") || len(brands.Deleted) != 0 || brands.Created != "" {
+		t.Errorf("Brands = %+v, want a failure without changes", brands)
+	}
+	if customers.Status != StatusSucceeded || !reflect.DeepEqual(customers.Deleted, []string{"1-c1", "1-c2"}) || customers.Created != "1-c3" {
+		t.Errorf("Customers = %+v, want c1 and c2 deleted and c3 created", customers)
+	}
+	if !strings.Contains(string(data), `"deletedCollectionIds": []`) {
+		t.Errorf("report should list no deleted collections as an empty array:\n%s", data)
+	}
+}
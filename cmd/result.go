@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// ModuleStatus is the outcome of syncing a module.
+type ModuleStatus string
+
+const (
+	StatusSucceeded ModuleStatus = "succeeded"
+	StatusFailed    ModuleStatus = "failed"
+	// StatusUnchanged marks modules skipped because their spec is the same
+	// as when they were last synced.
+	StatusUnchanged ModuleStatus = "unchanged"
+	// StatusSkipped marks modules that never ran, because a dependency
+	// failed or the run was cancelled first.
+	StatusSkipped ModuleStatus = "skipped"
+)
+
+// ModuleResult is the outcome of syncing one module.
+type ModuleResult struct {
+	Module     string
+	Collection string
+	Status     ModuleStatus
+	Duration   time.Duration
+	// Phases splits Duration by phase, when the processor times them.
+	Phases *PhaseTimings
+	// CollectionUID is the uid of the collection a succeeded module created,
+	// when the processor tracks it.
+	CollectionUID string
+	Err           error
+}
+
+// phasesJSON is the JSON form of PhaseTimings, in milliseconds.
+type phasesJSON struct {
+	FetchMS  int64 `json:"fetchMs"`
+	DeleteMS int64 `json:"deleteMs"`
+	ImportMS int64 `json:"importMs"`
+}
+
+// MarshalJSON encodes the result with the durations in milliseconds and the
+// error as its message.
+func (r ModuleResult) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+
+	var phases *phasesJSON
+	if r.Phases != nil {
+		phases = &phasesJSON{r.Phases.Fetch.Milliseconds(), r.Phases.Delete.Milliseconds(), r.Phases.Import.Milliseconds()}
+	}
+
+	return json.Marshal(struct {
+		Module        string       `json:"module"`
+		Collection    string       `json:"collection"`
+		CollectionUID string       `json:"collectionUid,omitempty"`
+		Status        ModuleStatus `json:"status"`
+		DurationMS    int64        `json:"durationMs"`
+		Phases        *phasesJSON  `json:"phases,omitempty"`
+		Error         string       `json:"error,omitempty"`
+	}{r.Module, r.Collection, r.CollectionUID, r.Status, r.Duration.Milliseconds(), phases, errMsg})
+}
+
+// CountResults returns how many modules ended with each status.
+func CountResults(results []ModuleResult) map[ModuleStatus]int {
+	counts := map[ModuleStatus]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
+
+// WriteResultTable writes the module results as a table followed by a line
+// counting the outcomes. Created collections are followed by their uid.
+func WriteResultTable(w io.Writer, results []ModuleResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tCOLLECTION\tSTATUS\tDURATION\tERROR")
+
+	for _, r := range results {
+		var errMsg string
+		if r.Err != nil {
+			errMsg = r.Err.Error()
+		}
+		collection := r.Collection
+		if r.CollectionUID != "" {
+			collection += " (" + r.CollectionUID + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Module, collection, r.Status, r.Duration.Round(time.Millisecond), errMsg)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, summarizeResults(results))
+	return err
+}
+
+// summarizeResults counts the outcomes of the results in a sentence.
+func summarizeResults(results []ModuleResult) string {
+	counts := CountResults(results)
+	summary := fmt.Sprintf("%d of %d modules succeeded, %d failed, %d skipped",
+		counts[StatusSucceeded], len(results), counts[StatusFailed], counts[StatusSkipped])
+	if counts[StatusUnchanged] > 0 {
+		summary += fmt.Sprintf(", %d unchanged", counts[StatusUnchanged])
+	}
+	return summary
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncAllModules_Results(t *testing.T) {
+	processor := &recordingProcessor{fail: map[string]bool{"Brands": true}}
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Brands":    "Brands Module API",
+			"Classes":   "Classes Module API",
+		},
+		DependsOn: map[string][]string{"Customers": {"Brands"}},
+	}
+
+	results, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace")
+	if err == nil {
+		t.Fatal("SyncAllModules() error = nil, want the Brands failure")
+	}
+
+	want := []struct {
+		module string
+		status ModuleStatus
+		hasErr bool
+	}{
+		{"Brands", StatusFailed, true},
+		{"Classes", StatusSucceeded, false},
+		{"Customers", StatusSkipped, true},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Module != w.module || r.Status != w.status || (r.Err != nil) != w.hasErr {
+			t.Errorf("results[%d] = %+v, want module %s with status %s", i, r, w.module, w.status)
+		}
+		if r.Collection != config.Modules[w.module] {
+			t.Errorf("results[%d].Collection = %q, want %q", i, r.Collection, config.Modules[w.module])
+		}
+	}
+}
+
+func TestSyncAllModules_BatchCleanupResults(t *testing.T) {
+	processor := &phasedProcessor{failImport: "Brands"}
+	config := &ModuleConfig{
+		Modules:      map[string]string{"Customers": "Customers Module API", "Brands": "Brands Module API"},
+		BatchCleanup: true,
+	}
+
+	results, _ := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace")
+
+	counts := CountResults(results)
+	if counts[StatusSucceeded] != 1 || counts[StatusFailed] != 1 {
+		t.Errorf("CountResults() = %v, want one success and one failure: %+v", counts, results)
+	}
+	if results[0].Module != "Brands" || results[0].Status != StatusFailed {
+		t.Errorf("results[0] = %+v, want the failed Brands import", results[0])
+	}
+}
+
+func TestWriteResultTable(t *testing.T) {
+	var b strings.Builder
+	err := WriteResultTable(&b, []ModuleResult{
+		{Module: "Brands", Collection: "Brands Module API", Status: StatusFailed, Duration: 1500 * time.Millisecond, Err: errors.New("import failed")},
+		{Module: "Classes", Collection: "Classes Module API", Status: StatusSucceeded, Duration: 800 * time.Millisecond},
+		{Module: "Customers", Collection: "Customers Module API", Status: StatusSucceeded, Duration: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("WriteResultTable() error = %v", err)
+	}
+
+	output := b.String()
+	for _, want := range []string{
+		"Brands     Brands Module API     failed     1.5s      import failed",
+		"2 of 3 modules succeeded, 1 failed, 0 skipped",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestModuleResult_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(ModuleResult{
+		Module:     "Brands",
+		Collection: "Brands Module API",
+		Status:     StatusFailed,
+		Duration:   1500 * time.Millisecond,
+		Err:        errors.New("import failed"),
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"module":"Brands","collection":"Brands Module API","status":"failed","durationMs":1500,"error":"import failed"}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestModuleResult_MarshalJSONPhases(t *testing.T) {
+	data, err := json.Marshal(ModuleResult{
+		Module:     "Brands",
+		Collection: "Brands Module API",
+		Status:     StatusSucceeded,
+		Duration:   1500 * time.Millisecond,
+		Phases:     &PhaseTimings{Fetch: 200 * time.Millisecond, Delete: 300 * time.Millisecond, Import: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"module":"Brands","collection":"Brands Module API","status":"succeeded","durationMs":1500,"phases":{"fetchMs":200,"deleteMs":300,"importMs":1000}}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestModuleResult_CollectionUID(t *testing.T) {
+	result := ModuleResult{Module: "Customers", Collection: "Customers Module API", Status: StatusSucceeded, Duration: time.Second, CollectionUID: "1-c3"}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"module":"Customers","collection":"Customers Module API","collectionUid":"1-c3","status":"succeeded","durationMs":1000}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var b strings.Builder
+	if err := WriteResultTable(&b, []ModuleResult{result}); err != nil {
+		t.Fatalf("WriteResultTable() error = %v", err)
+	}
+	if want := "Customers  Customers Module API (1-c3)  succeeded  1s"; !strings.Contains(b.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, b.String())
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultRetryDelay = time.Second
+	// DefaultPostmanAPIVersion is the Postman API version requests are pinned to.
+	DefaultPostmanAPIVersion = "10"
+)
+
+// WithPostmanAPIVersion pins Postman requests to the given API version through
+// the Accept header. An empty version leaves the choice to Postman.
+func WithPostmanAPIVersion(version string) ClientOption {
+	return func(c *APIClient) {
+		c.pmAPIVersion = version
+	}
+}
+
+// WithPostmanBaseURL sends Postman requests to baseURL instead of
+// https://api.getpostman.com, e.g. an EU data residency tenant or a test
+// server. Trailing slashes are ignored; an empty baseURL keeps the default.
+func WithPostmanBaseURL(baseURL string) ClientOption {
+	return func(c *APIClient) {
+		if baseURL = strings.TrimRight(baseURL, "/"); baseURL != "" {
+			c.postmanBaseURL = baseURL
+		}
+	}
+}
+
+// doPostman sends an authenticated request to the Postman API, pacing it
+// according to the rate limit reported by previous responses and retrying
+// transient failures.
+func (c *APIClient) doPostman(req *http.Request) (*http.Response, error) {
+	return c.doPostmanWithRetries(req, c.maxRetries)
+}
+
+// doPostmanWithRetries is doPostman retrying transient failures at most
+// maxRetries times.
+func (c *APIClient) doPostmanWithRetries(req *http.Request, maxRetries int) (*http.Response, error) {
+	req.Header.Set("X-API-Key", c.pmAPIKey)
+	if c.pmAPIVersion != "" {
+		req.Header.Set("Accept", fmt.Sprintf("application/vnd.api.v%s+json", c.pmAPIVersion))
+	}
+
+	return c.doWithRetry(req, maxRetries)
+}
+
+// retryableStatuses are the HTTP statuses Postman returns for transient
+// failures. Any other status is returned to the caller without retrying.
+var retryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// WithRetry sets how many times transient failures are retried and the base
+// delay that is doubled after every attempt.
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *APIClient) {
+		c.maxRetries = maxRetries
+		c.retryDelay = baseDelay
+	}
+}
+
+// doWithRetry sends req, retrying with exponential backoff while the response
+// is classified as transient. A Retry-After header takes precedence over the
+// backoff delay. The returned response body is always readable.
+func (c *APIClient) doWithRetry(req *http.Request, maxRetries int) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// A retry resends the same body, which counts once.
+		resp, err := c.send(attemptReq, attempt == 0)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		c.log.DebugContext(req.Context(), "postman response", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "bytes", len(body))
+
+		transient := slices.Contains(retryableStatuses, resp.StatusCode) || c.isRetryable(body)
+		if attempt >= maxRetries || !transient {
+			return resp, nil
+		}
+
+		delay, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			delay = c.retryDelay << attempt
+		}
+		c.log.WarnContext(req.Context(), "retrying request", "method", req.Method, "url", req.URL.String(), "delay", delay, "attempt", attempt+1, "maxRetries", maxRetries)
+		if err := c.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs a single request, pacing it against the Postman rate limit.
+// With countBody, the request body is added to the module's sent bytes.
+func (c *APIClient) send(req *http.Request, countBody bool) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.pacer.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	switch module := moduleFrom(req.Context()); {
+	case !countBody:
+	case req.ContentLength > 0:
+		c.transfers.add(module, 0, req.ContentLength)
+	case req.ContentLength < 0 && req.Body != nil:
+		// A streamed body is counted as it is sent.
+		req.Body = &countingBody{ReadCloser: req.Body, count: func(n int) { c.transfers.add(module, 0, int64(n)) }}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.pacer.observe(resp.Header)
+	return resp, nil
+}
+
+// isRetryable reports whether a response body carries one of the configured
+// transient error codes, either as a top-level "code" or inside "error".
+func (c *APIClient) isRetryable(body []byte) bool {
+	if len(c.retryBodyCodes) == 0 {
+		return false
+	}
+
+	var parsed struct {
+		Code  string `json:"code"`
+		Error struct {
+			Code string `json:"code"`
+			Name string `json:"name"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return false
+	}
+
+	for _, code := range []string{parsed.Code, parsed.Error.Code, parsed.Error.Name} {
+		if code != "" && slices.Contains(c.retryBodyCodes, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sleepContext waits for d, returning early with the context's error when ctx
+// is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date. A date in the past yields a zero delay.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// rewindRequest returns a copy of req with a fresh body so it can be resent.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("rewinding request body: %w", err)
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIClient_RetriesOnBodyCode(t *testing.T) {
+	var attempts int
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"TRY_AGAIN","message":"busy"}`))
+			return
+		}
+		w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithRetryBodyCodes("TRY_AGAIN"), WithVerifyImport(VerifyWarn), WithImportRetries(3))
+	client.postmanBaseURL = server.URL
+	var delays []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	if _, _, err := client.importToPostman(t.Context(), `{"openapi":"3.0.0"}`, "Customers Module API", "workspace", ImportOptions{}); err != nil {
+		t.Fatalf("importToPostman() error = %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	for i, body := range bodies {
+		if !strings.Contains(body, "openapi") {
+			t.Errorf("attempt %d sent body %q, want the import payload", i+1, body)
+		}
+	}
+	if len(delays) != 2 || delays[1] != 2*delays[0] {
+		t.Errorf("delays = %v, want exponential backoff", delays)
+	}
+}
+
+func TestAPIClient_RetryGivesUp(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"name":"TRY_AGAIN"}}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithRetryBodyCodes("TRY_AGAIN"))
+	client.postmanBaseURL = server.URL
+	client.sleep = func(context.Context, time.Duration) error { return nil }
+
+	if _, err := client.listCollections(t.Context(), "workspace"); err == nil {
+		t.Fatal("listCollections() error = nil, want error after retries")
+	}
+	if attempts != defaultMaxRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, defaultMaxRetries+1)
+	}
+}
+
+func TestAPIClient_IsRetryable(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key", WithRetryBodyCodes("TRY_AGAIN"))
+
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "top-level code", body: `{"code":"TRY_AGAIN"}`, want: true},
+		{name: "nested error code", body: `{"error":{"code":"TRY_AGAIN"}}`, want: true},
+		{name: "nested error name", body: `{"error":{"name":"TRY_AGAIN"}}`, want: true},
+		{name: "other code", body: `{"code":"INVALID"}`, want: false},
+		{name: "not JSON", body: `busy`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := client.isRetryable([]byte(tt.body)); got != tt.want {
+				t.Errorf("isRetryable(%s) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+
+	if NewAPIClient("doc-key", "pm-key").isRetryable([]byte(`{"code":"TRY_AGAIN"}`)) {
+		t.Error("isRetryable() should be false without configured codes")
+	}
+}
+
+func TestAPIClient_RetriesTransientStatuses(t *testing.T) {
+	for _, status := range retryableStatuses {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts == 1 {
+					w.WriteHeader(status)
+					return
+				}
+				w.Write([]byte(`{"collections":[]}`))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key")
+			client.postmanBaseURL = server.URL
+			client.sleep = func(context.Context, time.Duration) error { return nil }
+
+			if _, err := client.listCollections(t.Context(), "workspace"); err != nil {
+				t.Fatalf("listCollections() error = %v", err)
+			}
+			if attempts != 2 {
+				t.Errorf("attempts = %d, want 2", attempts)
+			}
+		})
+	}
+}
+
+func TestAPIClient_FailsFastOnClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key")
+			client.postmanBaseURL = server.URL
+			client.sleep = func(context.Context, time.Duration) error {
+				t.Error("slept before a non-retriable status")
+				return nil
+			}
+
+			if err := client.deleteCollection(t.Context(), CollectionRef{ID: "c1"}); err == nil {
+				t.Fatal("deleteCollection() error = nil, want error")
+			}
+			if attempts != 1 {
+				t.Errorf("attempts = %d, want 1", attempts)
+			}
+		})
+	}
+}
+
+func TestAPIClient_HonorsRetryAfter(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithRetry(2, 10*time.Millisecond))
+	client.postmanBaseURL = server.URL
+	var delays []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	if _, err := client.listCollections(t.Context(), "workspace"); err != nil {
+		t.Fatalf("listCollections() error = %v", err)
+	}
+	if len(delays) != 1 || delays[0] != 7*time.Second {
+		t.Errorf("delays = %v, want [7s] from Retry-After", delays)
+	}
+}
+
+func TestAPIClient_WithRetry(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithRetry(1, 50*time.Millisecond))
+	client.postmanBaseURL = server.URL
+	var delays []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	if _, err := client.listCollections(t.Context(), "workspace"); err == nil {
+		t.Fatal("listCollections() error = nil, want error after retries")
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+	if len(delays) != 1 || delays[0] != 50*time.Millisecond {
+		t.Errorf("delays = %v, want [50ms]", delays)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "absent", value: "", wantOK: false},
+		{name: "seconds", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "past date", value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOK: true},
+		{name: "invalid", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got, ok := retryAfter(future); !ok || got < 59*time.Minute || got > time.Hour {
+		t.Errorf("retryAfter(%q) = %v, %v, want about an hour", future, got, ok)
+	}
+}
+
+func TestAPIClient_PinsPostmanAPIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		wantAcc string
+	}{
+		{name: "default version", wantAcc: "application/vnd.api.v10+json"},
+		{name: "configured version", opts: []ClientOption{WithPostmanAPIVersion("11")}, wantAcc: "application/vnd.api.v11+json"},
+		{name: "unpinned", opts: []ClientOption{WithPostmanAPIVersion("")}, wantAcc: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accepts []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accepts = append(accepts, r.Header.Get("Accept"))
+				w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", append(tt.opts, WithOutput(io.Discard))...)
+			client.postmanBaseURL = server.URL
+
+			refs, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+			if err != nil {
+				t.Fatalf("getCollectionsByName() error = %v", err)
+			}
+			if err := client.deleteCollection(t.Context(), refs[0]); err != nil {
+				t.Fatalf("deleteCollection() error = %v", err)
+			}
+			if _, _, err := client.importToPostman(t.Context(), `{"openapi":"3.0.0"}`, "Customers Module API", "workspace", ImportOptions{}); err != nil {
+				t.Fatalf("importToPostman() error = %v", err)
+			}
+
+			for i, accept := range accepts {
+				if accept != tt.wantAcc {
+					t.Errorf("request %d Accept = %q, want %q", i+1, accept, tt.wantAcc)
+				}
+			}
+		})
+	}
+}
+
+func TestSleepContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("sleepContext() error = %v, want context canceled", err)
+	}
+}
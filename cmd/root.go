@@ -2,38 +2,235 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
-	"sync"
+	"os"
+	"regexp"
+	"slices"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type APIClient struct {
 	httpClient *http.Client
-	docAPIKey  string
-	pmAPIKey   string
+	// transport replaces the network transport when set; see WithTransport.
+	transport      http.RoundTripper
+	docAPIKey      string
+	pmAPIKey       string
+	pmAPIVersion   string
+	postmanBaseURL string
+	pacer          *rateLimitPacer
+	// limiter gates every Postman request when a rate limit is set.
+	limiter *rate.Limiter
+	// proxyURL is the explicit proxy, or empty to use the environment's.
+	proxyURL string
+	// connsPerHost caps the connections per host; zero means no cap.
+	connsPerHost int
+	maxRetries   int
+	retryDelay   time.Duration
+	// importMaxRetries is how many times imports are retried; see
+	// WithImportRetries.
+	importMaxRetries int
+	retryBodyCodes   []string
+	sleep            func(ctx context.Context, d time.Duration) error
+	log              *slog.Logger
+	docURL           func(moduleName string) string
+	// docURLTemplates overrides docURL for individual modules.
+	docURLTemplates map[string]string
+	// docAuthType is one of validDocAuthTypes; empty means DocAuthAPIKey.
+	docAuthType string
+	// docHeaders are sent with every doc request besides the credential.
+	docHeaders map[string]string
+	// circuit skips doc hosts that keep failing; nil disables it.
+	circuit *circuitBreaker
+	// minDocSize is the smallest doc body, in bytes, that is imported.
+	minDocSize    int
+	importOptions map[string]ImportOptions
+	// collectionUIDs are the collections modules are synced into by uid.
+	collectionUIDs map[string]string
+	// collectionKeyField is the dotted spec path matched instead of the name.
+	collectionKeyField string
+	gzipImport         bool
+	cassette           *Cassette
+	share              string
+	branding           *Branding
+	dryRun             bool
+	injectSecurity     string
+	forceSecurity      bool
+	maskPatterns       []*regexp.Regexp
+	canonical          bool
+	state              *State
+	idMap              *IDMap
+	upsert             bool
+	force              bool
+	blockBreaking      bool
+	allowBreaking      bool
+	// outputDir, when set, receives the specs instead of Postman.
+	outputDir string
+	// mode is one of validModes; empty means ModeSync.
+	mode         string
+	flagCheckURL string
+	transfers    *transferStats
+	httpClients  *httpClientSet
+	changes      *collectionChangeLog
+	metrics      *specMetricsLog
+	timings      *phaseTimingLog
+	keyIndex     *collectionKeyIndex
+	// deleteSlots holds one token per delete in flight; nil means no cap.
+	deleteSlots chan struct{}
+	strategy    string
+	// requireOperationID is one of validOperationIDModes, or empty to skip
+	// the check.
+	requireOperationID  string
+	generateOperationID bool
+	strict              bool
+	failOnDuplicates    bool
+	// verifyImport is one of validVerifyModes, or empty to skip the check.
+	verifyImport string
+	// normalize fixes what Postman's importer rejects before importing.
+	normalize bool
+}
+
+// ClientOption customizes an APIClient created by NewAPIClient.
+type ClientOption func(*APIClient)
+
+// WithRetryBodyCodes makes the client retry responses whose JSON body carries
+// one of the given error codes, whatever their HTTP status.
+func WithRetryBodyCodes(codes ...string) ClientOption {
+	return func(c *APIClient) {
+		c.retryBodyCodes = codes
+	}
+}
+
+// WithOutput makes the client log its progress to w at info level.
+func WithOutput(w io.Writer) ClientOption {
+	return func(c *APIClient) {
+		c.log = NewLogger(w, slog.LevelInfo)
+	}
+}
+
+// WithGzipImport makes the client send import payloads gzip-compressed,
+// falling back to an uncompressed request when the server rejects them.
+func WithGzipImport(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.gzipImport = enabled
+	}
+}
+
+// WithDryRun makes the client fetch docs and list collections but only report
+// the deletes and imports it would perform.
+func WithDryRun(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.dryRun = enabled
+	}
 }
 
-func NewAPIClient(docAPIKey, pmAPIKey string) *APIClient {
-	return &APIClient{
+func NewAPIClient(docAPIKey, pmAPIKey string, opts ...ClientOption) *APIClient {
+	client := &APIClient{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   defaultTimeout,
+			Transport: newTransport(),
 		},
-		docAPIKey: docAPIKey,
-		pmAPIKey:  pmAPIKey,
+		docAPIKey:      docAPIKey,
+		pmAPIKey:       pmAPIKey,
+		pmAPIVersion:   DefaultPostmanAPIVersion,
+		postmanBaseURL: defaultPostmanBaseURL,
+		pacer:          newRateLimitPacer(),
+		maxRetries:     defaultMaxRetries,
+		retryDelay:     defaultRetryDelay,
+		sleep:          sleepContext,
+		log:            NewLogger(os.Stdout, slog.LevelInfo),
+		docURL:         docURL,
+		transfers:      &transferStats{},
+		httpClients:    &httpClientSet{},
+		changes:        &collectionChangeLog{},
+		metrics:        &specMetricsLog{},
+		timings:        &phaseTimingLog{},
+		keyIndex:       &collectionKeyIndex{},
 	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
 
 type ModuleProcessor interface {
-	ProcessModule(moduleName, collectionName, workspaceID string) error
+	ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error
 }
 
 type ModuleConfig struct {
 	Modules map[string]string
+	// ClientSettings optionally overrides the HTTP client settings per module.
+	ClientSettings map[string]ClientSettings
+	// DependsOn lists, per module, the modules that must be synced before it.
+	DependsOn map[string][]string
+	// DocURLs optionally overrides the doc URL template per module.
+	DocURLs map[string]string
+	// ImportOptions optionally sets the import options per module.
+	ImportOptions map[string]ImportOptions
+	// Workspaces optionally routes modules to a Postman workspace other than
+	// the one given to SyncAllModules.
+	Workspaces map[string]string
+	// FeatureFlags optionally names, per module, the feature flag that must
+	// be on for the module to be synced.
+	FeatureFlags map[string]string
+	// CollectionUIDs optionally names, per module, the collection it is
+	// updated into instead of the collections found by name.
+	CollectionUIDs map[string]string
+	// DocURLTemplate is the doc URL of the modules without an entry in
+	// DocURLs, with %s standing for the module name. Empty means
+	// DefaultDocURLTemplate.
+	DocURLTemplate string
+	// Concurrency caps how many modules are synced at the same time. Zero
+	// means defaultConcurrency.
+	Concurrency int
+	// BatchCleanup deletes the stale collections of every module in one
+	// phase before any module is imported.
+	BatchCleanup bool
+}
+
+// workspaceFor returns the workspace the module is synced to: its own when
+// configured, otherwise fallback.
+func (c *ModuleConfig) workspaceFor(module, fallback string) string {
+	if id, ok := c.Workspaces[module]; ok {
+		return id
+	}
+	return fallback
+}
+
+// workspaceIDs returns fallback and every workspace of its own a module is
+// synced to, sorted.
+func (c *ModuleConfig) workspaceIDs(fallback string) []string {
+	ids := map[string]bool{fallback: true}
+	for module := range c.Modules {
+		ids[c.workspaceFor(module, fallback)] = true
+	}
+	return slices.Sorted(maps.Keys(ids))
 }
 
+// targetWorkspaceIDs returns every workspace a module is synced to, sorted.
+// Unlike workspaceIDs it leaves out fallback when every module has a
+// workspace of its own.
+func (c *ModuleConfig) targetWorkspaceIDs(fallback string) []string {
+	ids := map[string]bool{}
+	for module := range c.Modules {
+		ids[c.workspaceFor(module, fallback)] = true
+	}
+	return slices.Sorted(maps.Keys(ids))
+}
+
+// defaultConcurrency is the number of modules synced in parallel by default.
+const defaultConcurrency = 4
+
 func NewModuleConfig() *ModuleConfig {
 	return &ModuleConfig{
 		Modules: map[string]string{
@@ -49,220 +246,370 @@ func NewModuleConfig() *ModuleConfig {
 type SyncOrchestrator struct {
 	processor ModuleProcessor
 	config    *ModuleConfig
+	log       *slog.Logger
+	// shutdownGrace is how long in-flight modules may continue once the
+	// sync's context is done.
+	shutdownGrace time.Duration
+	// stream, when set, receives each module's result once it completes.
+	stream *ResultStream
+	// scheduler dispatches the modules; nil means a concurrent scheduler.
+	scheduler Scheduler
+	// failFast cancels the sync once a module fails.
+	failFast bool
+	// startJitter is the window of the random wait before each module.
+	startJitter time.Duration
+	sleep       func(ctx context.Context, d time.Duration) error
 }
 
 func NewSyncOrchestrator(processoor ModuleProcessor, config *ModuleConfig) *SyncOrchestrator {
 	return &SyncOrchestrator{
 		processor: processoor,
 		config:    config,
+		log:       NewLogger(os.Stdout, slog.LevelInfo),
+		sleep:     sleepContext,
 	}
 }
 
-func (s *SyncOrchestrator) SyncAllModules(workspaceID string) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(s.config.Modules))
+// SetLogger sets the logger the orchestrator reports its progress to.
+func (s *SyncOrchestrator) SetLogger(logger *slog.Logger) {
+	s.log = logger
+}
 
-	for mod, col := range s.config.Modules {
-		wg.Go(func() {
-			if err := s.processor.ProcessModule(mod, col, workspaceID); err != nil {
-				errChan <- err
-			}
-		})
+// SyncAllModules syncs every configured module and returns a result per
+// module, sorted by module name, together with all module errors joined.
+// Once ctx is done no further module is started, and the modules in flight
+// are cancelled after the shutdown grace period. With fail-fast, the first
+// failure does the same without a grace period, and so does the first error
+// wrapping ErrPostmanUnauthorized in any case.
+func (s *SyncOrchestrator) SyncAllModules(ctx context.Context, workspaceID string) ([]ModuleResult, error) {
+	work, cancel := drainContext(ctx, s.shutdownGrace)
+	defer cancel()
+	ctx, work, abort := s.failFastContexts(ctx, work)
+	defer abort(nil)
+
+	if s.config.BatchCleanup {
+		return s.syncInPhases(ctx, work, workspaceID, abort)
 	}
 
-	wg.Wait()
-	close(errChan)
-
-	for err := range errChan {
+	return s.runModules(ctx, s.config, func(mod string) error {
+		processor, err := s.processorFor(mod)
 		if err != nil {
+			return fmt.Errorf("configuring client: %w", err)
+		}
+		if err := s.checkFeatureFlag(work, mod, processor); err != nil {
 			return err
 		}
-	}
-
-	return nil
+		if err := s.waitStartJitter(work); err != nil {
+			return err
+		}
+		return processor.ProcessModule(work, mod, s.config.Modules[mod], s.config.workspaceFor(mod, workspaceID))
+	}, s.abortOnFailure(abort, s.completed))
 }
 
-func (c *APIClient) fetchDoc(url string) (string, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+// SyncModule syncs a single configured module, ignoring its dependencies,
+// and returns its error. It is meant for programs embedding the package that
+// sync modules on demand; the error names the module like SyncAllModules.
+func (s *SyncOrchestrator) SyncModule(ctx context.Context, moduleName, workspaceID string) error {
+	collectionName, ok := s.config.Modules[moduleName]
+	if !ok {
+		return fmt.Errorf("unknown module %q", moduleName)
 	}
 
-	req.Header.Set("X-API-Key", c.docAPIKey)
-
-	resp, err := c.httpClient.Do(req)
+	processor, err := s.processorFor(moduleName)
 	if err != nil {
-		return "", fmt.Errorf("making request: %w", err)
+		return fmt.Errorf("module %s: configuring client: %w", moduleName, err)
 	}
 
-	defer resp.Body.Close()
+	err = processor.ProcessModule(ctx, moduleName, collectionName, s.config.workspaceFor(moduleName, workspaceID))
+	if err != nil && !errors.Is(err, ErrUnchanged) {
+		return fmt.Errorf("module %s (collection %q): %w", moduleName, collectionName, err)
+	}
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+// processorFor returns the processor to use for a module, applying the module's
+// client settings when both the settings and a configurable processor exist.
+func (s *SyncOrchestrator) processorFor(moduleName string) (ModuleProcessor, error) {
+	settings, ok := s.config.ClientSettings[moduleName]
+	if !ok {
+		return s.processor, nil
 	}
 
-	var data any
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&data); err != nil {
-		return "", fmt.Errorf("decoding JSON: %w", err)
+	configurer, ok := s.processor.(ClientConfigurer)
+	if !ok {
+		return s.processor, nil
 	}
 
-	prettyJSON, _ := json.MarshalIndent(data, "", "  ")
+	return configurer.WithClientSettings(settings)
+}
 
-	return string(prettyJSON), nil
+// docStatusError is returned for a doc request answered with another status
+// than 200 OK.
+type docStatusError struct {
+	Status int
+	Body   string
 }
 
-func (c *APIClient) getCollectionsByName(name, workspaceID string) ([]string, error) {
-	url := fmt.Sprintf("https://api.getpostman.com/collections?workspace=%s", workspaceID)
-	req, err := http.NewRequest("GET", url, nil)
+func (e *docStatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d, body: %s", e.Status, e.Body)
+}
+
+func (c *APIClient) fetchDoc(ctx context.Context, url string) (string, error) {
+	doc, _, err := c.fetchDocIfModified(ctx, url, DocValidator{})
+	return doc, err
+}
+
+// fetchDocIfModified fetches a doc, conditionally when a validator of the
+// same URL is given, and returns it with the validator of the response. A doc
+// that has not been modified yields errNotModified.
+func (c *APIClient) fetchDocIfModified(ctx context.Context, url string, validator DocValidator) (string, DocValidator, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return "", DocValidator{}, fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("X-API-Key", c.pmAPIKey)
+	c.setDocHeaders(req)
+	validator.setConditions(req)
 
+	c.log.DebugContext(ctx, "fetching doc", "url", url)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+		return "", DocValidator{}, fmt.Errorf("making request: %w", err)
 	}
+
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotModified {
+		c.log.DebugContext(ctx, "doc not modified", "url", url)
+		return "", validator, errNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list collections: %d %s", resp.StatusCode, string(body))
+		body, _ := io.ReadAll(resp.Body)
+		return "", DocValidator{}, &docStatusError{Status: resp.StatusCode, Body: string(body)}
 	}
 
-	fmt.Printf("Collections response: %s\n", string(body))
+	body, err := io.ReadAll(resp.Body)
+	c.transfers.add(moduleFrom(ctx), int64(len(body)), 0)
+	if err != nil {
+		return "", DocValidator{}, fmt.Errorf("reading response: %w", err)
+	}
+	c.log.DebugContext(ctx, "fetched doc", "url", url, "bytes", len(body))
 
-	var result map[string]any
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", DocValidator{}, fmt.Errorf("%w: doc API returned an empty body", errEmptyDoc)
+	}
+	data, err := decodeDoc(body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return "", DocValidator{}, err
+	}
+	if err := c.checkDoc(body, data); err != nil {
+		return "", DocValidator{}, err
 	}
 
-	collections, ok := result["collections"].([]any)
-	if !ok {
-		return nil, nil
+	prettyJSON, _ := json.MarshalIndent(data, "", "  ")
+
+	return string(prettyJSON), validatorOf(url, resp.Header), nil
+}
+
+func (c *APIClient) getCollectionsByName(ctx context.Context, name, workspaceID string) ([]CollectionRef, error) {
+	collections, err := c.listCollections(ctx, workspaceID)
+	if err != nil {
+		return nil, err
 	}
 
-	var ids []string
-	for _, col := range collections {
-		collection, ok := col.(map[string]any)
-		if !ok {
+	var refs []CollectionRef
+	for _, ref := range collections {
+		if ref.Name != name {
 			continue
 		}
-
-		cname, ok := collection["name"].(string)
-		if !ok {
+		if ref.DeleteKey() == "" {
+			c.log.WarnContext(ctx, "skipping listed collection without an id", "name", name)
 			continue
 		}
+		refs = append(refs, ref)
+	}
 
-		if cname == name {
-			ids = append(ids, collection["id"].(string))
-		}
+	return refs, nil
+}
+
+func (c *APIClient) deleteCollection(ctx context.Context, ref CollectionRef) error {
+	if err := c.removeCollection(ctx, ref); err != nil {
+		return err
 	}
 
-	return ids, nil
+	c.changes.deleted(moduleFrom(ctx), ref.UpdateKey())
+	c.log.InfoContext(ctx, "deleted collection", "collection", ref.DeleteKey())
+	return nil
 }
 
-func (c *APIClient) deleteCollection(collectionID string) error {
-	url := fmt.Sprintf("https://api.getpostman.com/collections/%s", collectionID)
-	req, err := http.NewRequest("DELETE", url, nil)
+// removeCollection deletes a collection without recording it as a change,
+// for collections that only existed during the run.
+func (c *APIClient) removeCollection(ctx context.Context, ref CollectionRef) error {
+	url := fmt.Sprintf("%s/collections/%s", c.postmanBaseURL, ref.DeleteKey())
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("X-API-Key", c.pmAPIKey)
+	release, err := c.acquireDelete(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting to delete: %w", err)
+	}
+	defer release()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doPostman(req)
 	if err != nil {
 		return fmt.Errorf("making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
-	fmt.Printf("Delete response (status %d): %s\n", resp.StatusCode, string(body))
+	c.log.DebugContext(ctx, "delete response", "status", resp.StatusCode, "body", string(body))
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("failed to delete collection: %d %s: %w", resp.StatusCode, string(body), errCollectionGone)
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete collection: %d %s", resp.StatusCode, string(body))
+		return postmanStatusError(resp.StatusCode, fmt.Errorf("failed to delete collection: %d %s", resp.StatusCode, string(body)))
 	}
 
-	fmt.Printf("Successfully deleted collection: %s\n", collectionID)
+	c.keyIndex.removed(ref)
 	return nil
 }
 
-func (c *APIClient) importToPostman(openAPIData, collectionName, workspaceID string) error {
-	fmt.Println("Start to import collection: ", collectionName)
-	payload := map[string]any{
-		"type":  "string",
-		"input": openAPIData,
+// importToPostman imports the spec and returns the collections Postman
+// created, and whether the import was sent more than once, in which case an
+// earlier attempt may have created collections too. It fails when the
+// response names none.
+func (c *APIClient) importToPostman(ctx context.Context, openAPIData, collectionName, workspaceID string, options ImportOptions) ([]CollectionRef, bool, error) {
+	c.log.InfoContext(ctx, "importing collection", "collection", collectionName)
+	url := fmt.Sprintf("%s/import/openapi?workspace=%s", c.postmanBaseURL, workspaceID)
+
+	status, body, retried, err := c.postImport(ctx, url, openAPIData, options, c.gzipImport)
+	if err == nil && c.gzipImport && isEncodingRejected(status) {
+		c.log.WarnContext(ctx, "gzip import rejected, retrying uncompressed", "status", status)
+		var retriedPlain bool
+		status, body, retriedPlain, err = c.postImport(ctx, url, openAPIData, options, false)
+		retried = retried || retriedPlain
+	}
+	if err != nil {
+		return nil, retried, err
+	}
+
+	if status != http.StatusOK {
+		return nil, retried, postmanStatusError(status, fmt.Errorf("import failed with status %d: %s", status, string(body)))
 	}
 
-	payloadJSON, err := json.Marshal(payload)
+	c.log.DebugContext(ctx, "import response", "body", string(body))
+	imported, err := parseCollections(body)
 	if err != nil {
-		return fmt.Errorf("marshaling payload: %w", err)
+		return nil, retried, fmt.Errorf("import response %s: %w", string(body), err)
 	}
+	if len(imported) == 0 || imported[0].UpdateKey() == "" {
+		return nil, retried, fmt.Errorf("import response names no created collection: %s", string(body))
+	}
+
+	c.log.InfoContext(ctx, "imported collection", "collection", collectionName, "uid", imported[0].UpdateKey())
+	return imported, retried, nil
+}
 
-	url := fmt.Sprintf("https://api.getpostman.com/import/openapi?workspace=%s", workspaceID)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadJSON))
+// postImport streams the import payload to Postman and reports whether it was
+// sent more than once. An uncompressed payload is measured first, so it is
+// sent with its Content-Length; a compressed one is sent chunked.
+func (c *APIClient) postImport(ctx context.Context, url, openAPIData string, options ImportOptions, compress bool) (int, []byte, bool, error) {
+	size := int64(-1)
+	if !compress {
+		var err error
+		if size, err = importPayloadSize(openAPIData, options); err != nil {
+			return 0, nil, false, err
+		}
+	}
+
+	newBody := importBody(openAPIData, options, compress)
+	payload, _ := newBody()
+	req, err := http.NewRequestWithContext(ctx, "POST", url, payload)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return 0, nil, false, fmt.Errorf("creating request: %w", err)
 	}
+	// Every attempt rewinds the body, which counts the attempts.
+	attempts := 0
+	req.GetBody = func() (io.ReadCloser, error) {
+		attempts++
+		return newBody()
+	}
+	req.ContentLength = size
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", c.pmAPIKey)
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doPostmanWithRetries(req, c.importRetries())
 	if err != nil {
-		return fmt.Errorf("making request: %w", err)
+		return 0, nil, attempts > 1, fmt.Errorf("making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("import failed with status %d: %s", resp.StatusCode, string(body))
-	}
+	return resp.StatusCode, body, attempts > 1, nil
+}
 
-	fmt.Printf("Import successful: %s\n", string(body))
-	return nil
+// isEncodingRejected reports whether a status suggests the server did not
+// accept a gzip-encoded request body.
+func isEncodingRejected(status int) bool {
+	return status == http.StatusUnsupportedMediaType || status == http.StatusBadRequest
 }
 
-func (c *APIClient) ProcessModule(moduleName, collectionName, workspaceID string) error {
-	fmt.Println("processing module", moduleName)
+// DefaultDocURLTemplate is the doc URL of a module in the dev environment,
+// with %s standing for the module name.
+const DefaultDocURLTemplate = "https://api.%s.vivalabs-dev.link/v1/internal-docs"
 
-	apiURL := "https://api.vivalabs-dev.link/v1/internal-docs"
-	if moduleName != "Home" {
-		apiURL = fmt.Sprintf("https://api.%s.vivalabs-dev.link/v1/internal-docs", moduleName)
+// docURL returns the internal docs URL for a module.
+func docURL(moduleName string) string {
+	if moduleName == "Home" {
+		return "https://api.vivalabs-dev.link/v1/internal-docs"
 	}
+	return fmt.Sprintf(DefaultDocURLTemplate, moduleName)
+}
 
-	data, err := c.fetchDoc(apiURL)
-	if err != nil {
-		fmt.Println("fetch doc error", err)
-		return err
+func (c *APIClient) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	ctx = withModule(ctx, moduleName)
+	c.log.InfoContext(ctx, "processing module")
+	defer func() {
+		t := c.PhaseTimings()[moduleName]
+		c.log.DebugContext(ctx, "module timings", "fetch", t.Fetch, "delete", t.Delete, "import", t.Import)
+	}()
+
+	if c.mode == ModeDeleteOnly {
+		return c.deleteModule(ctx, moduleName, collectionName, workspaceID)
 	}
 
-	// Check if collection already exists and delete all instances
-	existingIds, err := c.getCollectionsByName(collectionName, workspaceID)
+	prepared, err := c.PrepareModule(ctx, moduleName, collectionName, workspaceID)
 	if err != nil {
-		fmt.Printf("Error checking existing collections: %v\n", err)
 		return err
 	}
+	if prepared.Unchanged {
+		return ErrUnchanged
+	}
 
-	for _, id := range existingIds {
-		fmt.Printf("Found existing collection %s, deleting...\n", id)
-		err = c.deleteCollection(id)
-		if err != nil {
-			fmt.Printf("Error deleting collection %s: %v\n", id, err)
+	if c.strategy == StrategyImportFirst {
+		if err := c.ImportModule(ctx, prepared); err != nil {
+			return err
 		}
 	}
 
-	// Import to Postman
-	err = c.importToPostman(data, collectionName, workspaceID)
-	if err != nil {
-		fmt.Printf("Postman import error: %v\n", err)
-		return err
+	// Delete all existing instances of the collection
+	if err := c.DeleteCollections(ctx, prepared.Stale); err != nil {
+		c.log.ErrorContext(ctx, "deleting collections failed", "error", err)
+	}
+
+	if c.strategy != StrategyImportFirst {
+		if err := c.ImportModule(ctx, prepared); err != nil {
+			return err
+		}
 	}
 
-	fmt.Println("processed module", moduleName)
+	c.log.InfoContext(ctx, "processed module")
 	return nil
 }
@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -66,6 +74,30 @@ func TestAPIClient_fetchDoc(t *testing.T) {
 			wantErr:        true,
 			errContains:    "decoding JSON",
 		},
+		{
+			name:           "empty object",
+			mockResponse:   `{}`,
+			mockStatusCode: http.StatusOK,
+			apiKey:         "test-api-key",
+			wantErr:        true,
+			errContains:    "doc API returned an empty object",
+		},
+		{
+			name:           "null",
+			mockResponse:   `null`,
+			mockStatusCode: http.StatusOK,
+			apiKey:         "test-api-key",
+			wantErr:        true,
+			errContains:    "doc API returned null",
+		},
+		{
+			name:           "empty body",
+			mockResponse:   ``,
+			mockStatusCode: http.StatusOK,
+			apiKey:         "test-api-key",
+			wantErr:        true,
+			errContains:    "doc API returned an empty body",
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,7 +113,7 @@ func TestAPIClient_fetchDoc(t *testing.T) {
 			defer server.Close()
 
 			client := NewAPIClient(tt.apiKey, "pm-key")
-			result, err := client.fetchDoc(server.URL)
+			result, err := client.fetchDoc(t.Context(), server.URL)
 
 			if tt.wantErr {
 				if err == nil {
@@ -153,3 +185,423 @@ func TestAPIClient_JSONParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIClient_importToPostmanGzip(t *testing.T) {
+	const spec = `{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`
+
+	tests := []struct {
+		name         string
+		rejectGzip   bool
+		wantRequests int
+	}{
+		{name: "gzip accepted", rejectGzip: false, wantRequests: 1},
+		{name: "gzip rejected falls back", rejectGzip: true, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			var received map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				body := io.Reader(r.Body)
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					if tt.rejectGzip {
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Fatalf("request body is not gzip-encoded: %v", err)
+					}
+					body = zr
+				} else if !tt.rejectGzip {
+					t.Error("expected a gzip-encoded request")
+				}
+
+				if err := json.NewDecoder(body).Decode(&received); err != nil {
+					t.Fatalf("decoding payload: %v", err)
+				}
+				w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1"}]}`))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", WithGzipImport(true))
+			client.postmanBaseURL = server.URL
+
+			if _, _, err := client.importToPostman(t.Context(), spec, "Customers Module API", "workspace", ImportOptions{}); err != nil {
+				t.Fatalf("importToPostman() error = %v", err)
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if received["type"] != "string" || received["input"] != spec {
+				t.Errorf("payload = %v, want the original import payload", received)
+			}
+		})
+	}
+}
+
+func TestAPIClient_importToPostmanWithoutCreatedCollection(t *testing.T) {
+	for _, body := range []string{`{}`, `{"collections":[]}`, `{"collections":[{"name":"Customers Module API"}]}`, `not json`} {
+		t.Run(body, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+			client.postmanBaseURL = server.URL
+
+			_, _, err := client.importToPostman(t.Context(), `{"openapi":"3.0.0"}`, "Customers Module API", "workspace", ImportOptions{})
+			if err == nil || !strings.Contains(err.Error(), body) {
+				t.Errorf("importToPostman() error = %v, want it to include the response", err)
+			}
+		})
+	}
+}
+
+func TestSyncOrchestrator_SyncAllModulesAggregatesErrors(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Brands":    "Brands Module API",
+			"Home":      "Home Module API",
+		},
+	}
+
+	processor := &recordingProcessor{fail: map[string]bool{"Customers": true, "Brands": true}}
+	_, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace")
+	if err == nil {
+		t.Fatal("SyncAllModules() error = nil, want errors for two modules")
+	}
+
+	for _, want := range []string{
+		`module Customers (collection "Customers Module API"): Customers failed`,
+		`module Brands (collection "Brands Module API"): Brands failed`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("SyncAllModules() error = %q, want it to contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "Home") {
+		t.Errorf("SyncAllModules() error = %q, should not mention the successful module", err)
+	}
+
+	processor = &recordingProcessor{}
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Errorf("SyncAllModules() error = %v, want nil when all modules succeed", err)
+	}
+}
+
+func TestSyncOrchestrator_SyncModule(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Brands":    "Brands Module API",
+		},
+		DependsOn: map[string][]string{"Customers": {"Brands"}},
+	}
+
+	processor := &recordingProcessor{fail: map[string]bool{"Brands": true}}
+	orchestrator := NewSyncOrchestrator(processor, config)
+
+	if err := orchestrator.SyncModule(t.Context(), "Customers", "workspace"); err != nil {
+		t.Fatalf("SyncModule(Customers) error = %v", err)
+	}
+	if want := []string{"start Customers", "end Customers"}; !slices.Equal(processor.events, want) {
+		t.Errorf("events = %v, want only %v", processor.events, want)
+	}
+
+	err := orchestrator.SyncModule(t.Context(), "Brands", "workspace")
+	if err == nil || !strings.Contains(err.Error(), `module Brands (collection "Brands Module API"): Brands failed`) {
+		t.Errorf("SyncModule(Brands) error = %v, want the module's error", err)
+	}
+
+	if err := orchestrator.SyncModule(t.Context(), "Orders", "workspace"); err == nil {
+		t.Error("SyncModule(Orders) error = nil, want unknown module")
+	}
+}
+
+// workspaceProcessor records the workspace every module is synced to.
+type workspaceProcessor struct {
+	mu         sync.Mutex
+	workspaces map[string]string
+}
+
+func (p *workspaceProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workspaces[moduleName] = workspaceID
+	return nil
+}
+
+func TestSyncOrchestrator_ModuleWorkspaces(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Orders":    "Orders Module API",
+		},
+		Workspaces: map[string]string{"Orders": "ws-partner"},
+	}
+
+	processor := &workspaceProcessor{workspaces: map[string]string{}}
+	orchestrator := NewSyncOrchestrator(processor, config)
+	if _, err := orchestrator.SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	want := map[string]string{"Customers": "workspace", "Orders": "ws-partner"}
+	if !reflect.DeepEqual(processor.workspaces, want) {
+		t.Errorf("workspaces = %v, want %v", processor.workspaces, want)
+	}
+
+	processor.workspaces = map[string]string{}
+	if err := orchestrator.SyncModule(t.Context(), "Orders", "workspace"); err != nil {
+		t.Fatalf("SyncModule() error = %v", err)
+	}
+	if got := processor.workspaces["Orders"]; got != "ws-partner" {
+		t.Errorf("SyncModule(Orders) workspace = %q, want ws-partner", got)
+	}
+
+	if got := config.workspaceIDs("workspace"); !slices.Equal(got, []string{"workspace", "ws-partner"}) {
+		t.Errorf("workspaceIDs() = %v", got)
+	}
+}
+
+func TestAPIClient_ProcessModuleDryRun(t *testing.T) {
+	const spec = `{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(spec))
+	}))
+	defer docServer.Close()
+
+	var methods []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Write([]byte(`{"collections":[
+			{"id":"c1","uid":"1-c1","name":"Customers Module API"},
+			{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+		]}`))
+	}))
+	defer postman.Close()
+
+	var out strings.Builder
+	client := NewAPIClient("doc-key", "pm-key", WithDryRun(true), WithOutput(&out))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	if len(methods) != 1 || methods[0] != "GET" {
+		t.Errorf("Postman requests = %v, want only the collection listing", methods)
+	}
+
+	payload := marshalImportPayload(t, "{\n  \"openapi\": \"3.0.0\",\n  \"paths\": {\n    \"/customers\": {\n      \"get\": {}\n    }\n  }\n}", ImportOptions{})
+	for _, want := range []string{
+		`msg="dry run: would delete collection" collection=c1 name="Customers Module API" module=Customers`,
+		`msg="dry run: would delete collection" collection=c2 name="Customers Module API" module=Customers`,
+		fmt.Sprintf(`msg="dry run: would import collection" collection="Customers Module API" bytes=%d module=Customers`, len(payload)),
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAPIClient_ProcessModuleDryRunReportsListErrors(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"name":"AuthenticationError"}}`))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithDryRun(true), WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err == nil {
+		t.Error("ProcessModule() error = nil, want the list error in dry-run mode")
+	}
+}
+
+func TestAPIClient_ProcessModuleCancelled(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.ProcessModule(ctx, "Customers", "Customers Module API", "workspace")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ProcessModule() error = %v, want context deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ProcessModule() returned after %v, want it to abort promptly", elapsed)
+	}
+}
+
+func TestSyncOrchestrator_SyncAllModulesCancelled(t *testing.T) {
+	processor := &recordingProcessor{}
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API", "Brands": "Brands Module API"}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := NewSyncOrchestrator(processor, config).SyncAllModules(ctx, "workspace")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SyncAllModules() error = %v, want context canceled", err)
+	}
+	if len(processor.events) != 0 {
+		t.Errorf("events = %v, want no module started after cancellation", processor.events)
+	}
+}
+
+// mockPostman emulates the Postman endpoints a module sync uses: listing,
+// deleting and importing collections. It records what was deleted and
+// imported.
+type mockPostman struct {
+	collections string
+
+	mu       sync.Mutex
+	deleted  []string
+	imported []map[string]any
+}
+
+func (m *mockPostman) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != "pm-key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Query().Get("workspace") != "" && r.URL.Query().Get("workspace") != "workspace" {
+		http.Error(w, "unknown workspace", http.StatusNotFound)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/collections":
+		w.Write([]byte(m.collections))
+	case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/collections/"):
+		m.deleted = append(m.deleted, strings.TrimPrefix(r.URL.Path, "/collections/"))
+		w.Write([]byte(`{}`))
+	case r.Method == "POST" && r.URL.Path == "/import/openapi":
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.imported = append(m.imported, payload)
+		w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Customers Module API"}]}`))
+	case r.Method == "PATCH" && strings.HasPrefix(r.URL.Path, "/collections/"):
+		// Renames of collections imported for other modules.
+		w.Write([]byte(`{}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestAPIClient_ProcessModuleAgainstMockPostman(t *testing.T) {
+	const spec = `{"openapi":"3.0.0","info":{"title":"Customers","version":"1.0.0"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`
+
+	tests := []struct {
+		name        string
+		collections string
+		wantDeleted []string
+	}{
+		{
+			name:        "no existing collection",
+			collections: `{"collections":[{"id":"b1","uid":"1-b1","name":"Brands Module API"}]}`,
+		},
+		{
+			name: "one existing collection",
+			collections: `{"collections":[
+				{"id":"b1","uid":"1-b1","name":"Brands Module API"},
+				{"id":"c1","uid":"1-c1","name":"Customers Module API"}
+			]}`,
+			wantDeleted: []string{"c1"},
+		},
+		{
+			name: "duplicates",
+			collections: `{"collections":[
+				{"id":"c1","uid":"1-c1","name":"Customers Module API"},
+				{"id":"b1","uid":"1-b1","name":"Brands Module API"},
+				{"id":"c2","uid":"1-c2","name":"Customers Module API"}
+			]}`,
+			wantDeleted: []string{"c1", "c2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/customers/docs" || r.Header.Get("X-API-Key") != "doc-key" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(spec))
+			}))
+			defer docServer.Close()
+
+			postman := &mockPostman{collections: tt.collections}
+			postmanServer := httptest.NewServer(postman)
+			defer postmanServer.Close()
+
+			client := NewAPIClient("doc-key", "pm-key",
+				WithPostmanBaseURL(postmanServer.URL),
+				WithDocURLTemplate(docServer.URL+"/%s/docs"),
+				WithOutput(io.Discard),
+			)
+
+			if err := client.ProcessModule(t.Context(), "customers", "Customers Module API", "workspace"); err != nil {
+				t.Fatalf("ProcessModule() error = %v", err)
+			}
+
+			slices.Sort(postman.deleted)
+			if !slices.Equal(postman.deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", postman.deleted, tt.wantDeleted)
+			}
+
+			if len(postman.imported) != 1 {
+				t.Fatalf("got %d imports, want 1", len(postman.imported))
+			}
+			payload := postman.imported[0]
+			if payload["type"] != "string" {
+				t.Errorf("import type = %v, want string", payload["type"])
+			}
+			input, _ := payload["input"].(string)
+			var imported struct {
+				Paths map[string]any `json:"paths"`
+			}
+			if err := json.Unmarshal([]byte(input), &imported); err != nil {
+				t.Fatalf("import input is no JSON spec: %v\n%s", err, input)
+			}
+			if _, ok := imported.Paths["/customers"]; !ok {
+				t.Errorf("import input = %s, want the fetched spec", input)
+			}
+		})
+	}
+}
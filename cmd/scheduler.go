@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+)
+
+// Scheduler runs the tasks the orchestrator dispatches, one per module, in
+// dependency order with ties broken alphabetically.
+type Scheduler interface {
+	// Start runs task, possibly concurrently with the tasks already started.
+	// It returns ctx's error, without running task, when ctx is done before
+	// task could start.
+	Start(ctx context.Context, task func()) error
+	// Wait blocks until every started task has finished.
+	Wait()
+}
+
+// SetScheduler replaces the scheduler dispatching the modules of a sync. By
+// default a concurrent scheduler runs up to the configured concurrency of
+// modules at a time, and a sequential one runs them one at a time when the
+// concurrency is 1.
+func (s *SyncOrchestrator) SetScheduler(scheduler Scheduler) {
+	s.scheduler = scheduler
+}
+
+// schedulerFor returns the scheduler to dispatch the modules of config. With
+// a concurrency of 1 each module ends before the next is chosen, so one that
+// becomes ready meanwhile is not overtaken by a module sorting after it.
+func (s *SyncOrchestrator) schedulerFor(config *ModuleConfig) Scheduler {
+	if s.scheduler != nil {
+		return s.scheduler
+	}
+	if config.concurrency() == 1 {
+		return NewSequentialScheduler()
+	}
+	return NewConcurrentScheduler(config.concurrency())
+}
+
+type concurrentScheduler struct {
+	wg    sync.WaitGroup
+	slots chan struct{}
+}
+
+// NewConcurrentScheduler returns a scheduler running up to limit tasks at a
+// time. Start blocks while limit tasks are running.
+func NewConcurrentScheduler(limit int) Scheduler {
+	return &concurrentScheduler{slots: make(chan struct{}, limit)}
+}
+
+func (s *concurrentScheduler) Start(ctx context.Context, task func()) error {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		<-s.slots
+		return err
+	}
+
+	s.wg.Go(func() {
+		defer func() { <-s.slots }()
+		task()
+	})
+	return nil
+}
+
+func (s *concurrentScheduler) Wait() {
+	s.wg.Wait()
+}
+
+type sequentialScheduler struct{}
+
+// NewSequentialScheduler returns a scheduler running every task to completion
+// before Start returns, so modules are processed one at a time in a fixed
+// order. It makes runs reproducible, for tests and debugging.
+func NewSequentialScheduler() Scheduler {
+	return sequentialScheduler{}
+}
+
+func (sequentialScheduler) Start(ctx context.Context, task func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	task()
+	return nil
+}
+
+func (sequentialScheduler) Wait() {}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSequentialScheduler_FixedOrder(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:     map[string]string{"Home": "", "Customers": "", "Brands": "", "Classes": ""},
+		DependsOn:   map[string][]string{"Home": {"Customers", "Classes"}},
+		Concurrency: 4,
+	}
+	want := []string{
+		"start Brands", "end Brands",
+		"start Classes", "end Classes",
+		"start Customers", "end Customers",
+		"start Home", "end Home",
+	}
+
+	for run := range 5 {
+		processor := &recordingProcessor{}
+		orchestrator := NewSyncOrchestrator(processor, config)
+		orchestrator.SetScheduler(NewSequentialScheduler())
+		if _, err := orchestrator.SyncAllModules(t.Context(), "workspace"); err != nil {
+			t.Fatalf("SyncAllModules() error = %v", err)
+		}
+		if !slices.Equal(processor.events, want) {
+			t.Fatalf("run %d: events = %v, want %v", run, processor.events, want)
+		}
+	}
+}
+
+func TestSequentialScheduler_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	ran := false
+	if err := NewSequentialScheduler().Start(ctx, func() { ran = true }); err == nil || ran {
+		t.Errorf("Start() error = %v, ran = %v, want the cancellation without running the task", err, ran)
+	}
+}
+
+func TestConcurrentScheduler_Limit(t *testing.T) {
+	scheduler := NewConcurrentScheduler(2)
+	var running, peak atomic.Int32
+	for range 6 {
+		err := scheduler.Start(t.Context(), func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		})
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	}
+	scheduler.Wait()
+
+	if peak.Load() != 2 {
+		t.Errorf("peak of running tasks = %d, want 2", peak.Load())
+	}
+}
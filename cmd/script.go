@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WriteScript writes a shell script with the curl commands a sync of the
+// configured modules would run with the client's settings: its Postman base
+// URL and API version, doc auth type and doc headers, and the collections
+// configured by uid, which are updated in place. API keys are referenced
+// through the DOC_API_KEY and PM_API_KEY environment variables instead of
+// being inlined. Modules without a workspace of their own use workspaceID.
+func (c *APIClient) WriteScript(w io.Writer, config *ModuleConfig, workspaceID string) error {
+	var b strings.Builder
+
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Generated by the API sync tool. Requires curl and jq, with DOC_API_KEY and PM_API_KEY exported.\n")
+	b.WriteString("set -eu\n")
+
+	docAuth := c.scriptDocAuth()
+	pmAuth := c.scriptPostmanAuth()
+	for _, module := range slices.Sorted(maps.Keys(config.Modules)) {
+		collection := config.Modules[module]
+		workspace := config.workspaceFor(module, workspaceID)
+		listURL := fmt.Sprintf("%s/collections?workspace=%s", c.postmanBaseURL, workspace)
+		importURL := fmt.Sprintf("%s/import/openapi?workspace=%s", c.postmanBaseURL, workspace)
+		docFile := shellQuote(module + ".json")
+		url := shellQuote(config.docURL(module))
+		if template, ok := config.DocURLs[module]; ok {
+			url = shellExpand(template)
+		}
+
+		fmt.Fprintf(&b, "\n# Module %s -> collection %q\n", module, collection)
+		fmt.Fprintf(&b, "curl -sSf -X GET %s %s > %s\n", docAuth, url, docFile)
+		uid, configured := c.collectionUIDs[module]
+		if !configured {
+			fmt.Fprintf(&b, "curl -sSf -X GET %s %s \\\n", pmAuth, shellQuote(listURL))
+			fmt.Fprintf(&b, "  | jq -r --arg name %s '.collections[] | select(.name == $name) | .id' \\\n", shellQuote(collection))
+			b.WriteString("  | while read -r id; do\n")
+			fmt.Fprintf(&b, "      curl -sSf -X DELETE %s \"%s/collections/$id\"\n", pmAuth, c.postmanBaseURL)
+			b.WriteString("    done\n")
+		}
+		if options := config.ImportOptions[module]; !options.isZero() {
+			optionsJSON, err := json.Marshal(options)
+			if err != nil {
+				return fmt.Errorf("marshaling import options: %w", err)
+			}
+			fmt.Fprintf(&b, "imported=$(jq -n --rawfile input %s --argjson options %s '{type: \"string\", input: $input, options: $options}' \\\n", docFile, shellQuote(string(optionsJSON)))
+		} else {
+			fmt.Fprintf(&b, "imported=$(jq -n --rawfile input %s '{type: \"string\", input: $input}' \\\n", docFile)
+		}
+		fmt.Fprintf(&b, "  | curl -sSf -X POST -H 'Content-Type: application/json' %s --data @- %s)\n", pmAuth, shellQuote(importURL))
+		b.WriteString("uid=$(printf '%s' \"$imported\" | jq -r '.collections[0].uid')\n")
+
+		if !configured {
+			// Postman names the import after the spec's title.
+			fmt.Fprintf(&b, "jq -n --arg name %s '{collection: {info: {name: $name}}}' \\\n", shellQuote(collection))
+			fmt.Fprintf(&b, "  | curl -sSf -X PATCH -H 'Content-Type: application/json' %s --data @- \"%s/collections/$uid\"\n", pmAuth, c.postmanBaseURL)
+			continue
+		}
+
+		// The import is only a conversion, copied over the configured
+		// collection without Postman's ids and deleted.
+		b.WriteString("id=$(printf '%s' \"$imported\" | jq -r '.collections[0].id')\n")
+		fmt.Fprintf(&b, "curl -sSf -X GET %s \"%s/collections/$uid\" \\\n", pmAuth, c.postmanBaseURL)
+		fmt.Fprintf(&b, "  | jq --arg name %s '.collection |= (walk(if type == \"object\" then del(%s) else . end) | .info.name = $name)' \\\n", shellQuote(collection), scriptIDKeys())
+		fmt.Fprintf(&b, "  | curl -sSf -X PUT -H 'Content-Type: application/json' %s --data @- %s\n", pmAuth, shellQuote(c.postmanBaseURL+"/collections/"+uid))
+		fmt.Fprintf(&b, "curl -sSf -X DELETE %s \"%s/collections/$id\"\n", pmAuth, c.postmanBaseURL)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// scriptDocAuth returns the curl options sending the doc API credential the
+// way the doc auth type demands, followed by the doc headers.
+func (c *APIClient) scriptDocAuth() string {
+	var options []string
+	switch c.docAuthType {
+	case DocAuthBearer:
+		options = append(options, `-H "Authorization: Bearer $DOC_API_KEY"`)
+	case DocAuthBasic:
+		options = append(options, `-u "$DOC_API_KEY"`)
+	default:
+		options = append(options, `-H "X-API-Key: $DOC_API_KEY"`)
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.docHeaders)) {
+		options = append(options, "-H "+shellQuote(key+": "+c.docHeaders[key]))
+	}
+	return strings.Join(options, " ")
+}
+
+// scriptPostmanAuth returns the curl options every Postman request carries.
+func (c *APIClient) scriptPostmanAuth() string {
+	options := `-H "X-API-Key: $PM_API_KEY"`
+	if c.pmAPIVersion != "" {
+		options += " -H " + shellQuote(fmt.Sprintf("Accept: application/vnd.api.v%s+json", c.pmAPIVersion))
+	}
+	return options
+}
+
+// scriptIDKeys returns postmanIDKeys as jq paths.
+func scriptIDKeys() string {
+	paths := make([]string, len(postmanIDKeys))
+	for i, key := range postmanIDKeys {
+		paths[i] = "." + key
+	}
+	return strings.Join(paths, ", ")
+}
+
+// shellExpand wraps s in double quotes so the shell expands its variable
+// references, and nothing else, when the script runs.
+func shellExpand(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`").Replace(s) + `"`
+}
+
+// shellQuote wraps s in single quotes so it is passed to the shell verbatim.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
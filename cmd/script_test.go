@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteScript(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Home":      "Home Module API",
+		},
+	}
+
+	var b strings.Builder
+	if err := NewAPIClient("", "", WithOutput(io.Discard)).WriteScript(&b, config, "ws-123"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+	script := b.String()
+
+	pm := `-H "X-API-Key: $PM_API_KEY" -H 'Accept: application/vnd.api.v` + DefaultPostmanAPIVersion + `+json'`
+	wantLines := []string{
+		`curl -sSf -X GET -H "X-API-Key: $DOC_API_KEY" 'https://api.Customers.vivalabs-dev.link/v1/internal-docs'`,
+		`curl -sSf -X GET -H "X-API-Key: $DOC_API_KEY" 'https://api.vivalabs-dev.link/v1/internal-docs'`,
+		`curl -sSf -X GET ` + pm + ` 'https://api.getpostman.com/collections?workspace=ws-123'`,
+		`jq -r --arg name 'Customers Module API'`,
+		`curl -sSf -X DELETE ` + pm + ` "https://api.getpostman.com/collections/$id"`,
+		`curl -sSf -X POST -H 'Content-Type: application/json' ` + pm + ` --data @- 'https://api.getpostman.com/import/openapi?workspace=ws-123'`,
+		`jq -n --arg name 'Customers Module API' '{collection: {info: {name: $name}}}'`,
+		`curl -sSf -X PATCH -H 'Content-Type: application/json' ` + pm + ` --data @- "https://api.getpostman.com/collections/$uid"`,
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q\n%s", want, script)
+		}
+	}
+
+	if strings.Index(script, "# Module Customers") > strings.Index(script, "# Module Home") {
+		t.Error("modules should be emitted in sorted order")
+	}
+}
+
+func TestWriteScript_ModuleWorkspace(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:    map[string]string{"Orders": "Orders Module API"},
+		Workspaces: map[string]string{"Orders": "ws-partner"},
+	}
+
+	var b strings.Builder
+	if err := NewAPIClient("", "", WithOutput(io.Discard)).WriteScript(&b, config, "ws-123"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+
+	script := b.String()
+	for _, want := range []string{"collections?workspace=ws-partner", "import/openapi?workspace=ws-partner"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "ws-123") {
+		t.Errorf("script should not use the default workspace\n%s", script)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %s", got)
+	}
+}
+
+func TestWriteScript_DocURLTemplate(t *testing.T) {
+	config := &ModuleConfig{
+		Modules: map[string]string{"Orders": "Orders Module API"},
+		DocURLs: map[string]string{"Orders": "https://${ORDERS_HOST}/v1/internal-docs"},
+	}
+
+	var b strings.Builder
+	if err := NewAPIClient("", "", WithOutput(io.Discard)).WriteScript(&b, config, "ws-123"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+
+	want := `curl -sSf -X GET -H "X-API-Key: $DOC_API_KEY" "https://${ORDERS_HOST}/v1/internal-docs"`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("script missing %q\n%s", want, b.String())
+	}
+}
+
+func TestWriteScript_GlobalDocURLTemplate(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:        map[string]string{"Home": "Home Module API"},
+		DocURLTemplate: "https://api.%s.vivalabs.link/v1/internal-docs",
+	}
+
+	var b strings.Builder
+	if err := NewAPIClient("", "", WithOutput(io.Discard)).WriteScript(&b, config, "ws-123"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+
+	want := `'https://api.Home.vivalabs.link/v1/internal-docs'`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("script missing %q\n%s", want, b.String())
+	}
+}
+
+func TestShellExpand(t *testing.T) {
+	if got := shellExpand("a\"b`c\\d$HOME"); got != "\"a\\\"b\\`c\\\\d$HOME\"" {
+		t.Errorf("shellExpand() = %s", got)
+	}
+}
+
+func TestWriteScript_ClientSettings(t *testing.T) {
+	config := &ModuleConfig{Modules: map[string]string{"Orders": "Orders Module API"}}
+
+	tests := []struct {
+		name      string
+		opts      []ClientOption
+		wantLines []string
+	}{
+		{
+			name: "postman base url and api version",
+			opts: []ClientOption{WithPostmanBaseURL("https://api.eu.getpostman.com/"), WithPostmanAPIVersion("11")},
+			wantLines: []string{
+				`curl -sSf -X GET -H "X-API-Key: $PM_API_KEY" -H 'Accept: application/vnd.api.v11+json' 'https://api.eu.getpostman.com/collections?workspace=ws-123'`,
+				`--data @- 'https://api.eu.getpostman.com/import/openapi?workspace=ws-123'`,
+			},
+		},
+		{
+			name:      "bearer doc auth",
+			opts:      []ClientOption{WithDocAuthType(DocAuthBearer)},
+			wantLines: []string{`curl -sSf -X GET -H "Authorization: Bearer $DOC_API_KEY" 'https://api.Orders.vivalabs-dev.link/v1/internal-docs'`},
+		},
+		{
+			name:      "basic doc auth",
+			opts:      []ClientOption{WithDocAuthType(DocAuthBasic)},
+			wantLines: []string{`curl -sSf -X GET -u "$DOC_API_KEY" 'https://api.Orders.vivalabs-dev.link/v1/internal-docs'`},
+		},
+		{
+			name:      "doc headers",
+			opts:      []ClientOption{WithDocHeaders(map[string]string{"X-Tenant-ID": "acme", "X-Region": "eu-west"})},
+			wantLines: []string{`curl -sSf -X GET -H "X-API-Key: $DOC_API_KEY" -H 'X-Region: eu-west' -H 'X-Tenant-ID: acme' 'https://api.Orders.vivalabs-dev.link/v1/internal-docs'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			client := NewAPIClient("", "", append(tt.opts, WithOutput(io.Discard))...)
+			if err := client.WriteScript(&b, config, "ws-123"); err != nil {
+				t.Fatalf("WriteScript() error = %v", err)
+			}
+			for _, want := range tt.wantLines {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("script missing %q\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteScript_CollectionUID(t *testing.T) {
+	config := &ModuleConfig{Modules: map[string]string{"Orders": "Orders Module API"}}
+	client := NewAPIClient("", "", WithCollectionUIDs(map[string]string{"Orders": "12345-orders"}), WithOutput(io.Discard))
+
+	var b strings.Builder
+	if err := client.WriteScript(&b, config, "ws-123"); err != nil {
+		t.Fatalf("WriteScript() error = %v", err)
+	}
+	script := b.String()
+
+	wantLines := []string{
+		`--data @- 'https://api.getpostman.com/import/openapi?workspace=ws-123')`,
+		`jq --arg name 'Orders Module API' '.collection |= (walk(if type == "object" then del(.id, .uid, ._postman_id, ._exporter_id) else . end) | .info.name = $name)'`,
+		`--data @- 'https://api.getpostman.com/collections/12345-orders'`,
+		`"https://api.getpostman.com/collections/$id"`,
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "collections?workspace=") || strings.Contains(script, "PATCH") {
+		t.Errorf("script should neither list, delete by name nor rename for a configured collection\n%s", script)
+	}
+}
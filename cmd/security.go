@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// securitySchemes maps the -inject-security values to the name and OpenAPI
+// definition of the scheme added to every spec.
+var securitySchemes = map[string]struct {
+	name       string
+	definition map[string]any
+}{
+	"bearer":  {name: "bearerAuth", definition: map[string]any{"type": "http", "scheme": "bearer"}},
+	"basic":   {name: "basicAuth", definition: map[string]any{"type": "http", "scheme": "basic"}},
+	"api-key": {name: "apiKeyAuth", definition: map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"}},
+}
+
+// securitySchemeNames returns the accepted -inject-security values, sorted.
+func securitySchemeNames() []string {
+	return slices.Sorted(maps.Keys(securitySchemes))
+}
+
+// WithInjectSecurity makes the client add the given security scheme, one of
+// the keys of securitySchemes, and a global requirement for it to every spec.
+// An existing scheme or requirement is only replaced when force is set.
+func WithInjectSecurity(scheme string, force bool) ClientOption {
+	return func(c *APIClient) {
+		c.injectSecurity = scheme
+		c.forceSecurity = force
+	}
+}
+
+// injectSecurity adds the security scheme to components.securitySchemes and
+// requires it globally, keeping what the spec already defines unless force is set.
+func injectSecurity(doc, scheme string, force bool) (string, error) {
+	injected, ok := securitySchemes[scheme]
+	if !ok {
+		return "", fmt.Errorf("invalid security scheme %q", scheme)
+	}
+
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+
+	components, _ := spec["components"].(map[string]any)
+	if components == nil {
+		components = map[string]any{}
+		spec["components"] = components
+	}
+
+	schemes, _ := components["securitySchemes"].(map[string]any)
+	if schemes == nil {
+		schemes = map[string]any{}
+		components["securitySchemes"] = schemes
+	}
+
+	if _, exists := schemes[injected.name]; !exists || force {
+		schemes[injected.name] = injected.definition
+	}
+
+	if _, exists := spec["security"]; !exists || force {
+		spec["security"] = []any{map[string]any{injected.name: []any{}}}
+	}
+
+	result, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling spec: %w", err)
+	}
+
+	return string(result), nil
+}
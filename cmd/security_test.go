@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInjectSecurity(t *testing.T) {
+	existingScheme := map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+	existingSecurity := []any{map[string]any{"oauth": []any{"read"}}}
+
+	tests := []struct {
+		name         string
+		doc          string
+		force        bool
+		wantScheme   any
+		wantSecurity any
+	}{
+		{
+			name:         "injects when absent",
+			doc:          `{"openapi":"3.0.0","paths":{}}`,
+			wantScheme:   map[string]any{"type": "http", "scheme": "bearer"},
+			wantSecurity: []any{map[string]any{"bearerAuth": []any{}}},
+		},
+		{
+			name: "preserves existing scheme and requirement",
+			doc: `{"openapi":"3.0.0",
+				"components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer","bearerFormat":"JWT"}}},
+				"security":[{"oauth":["read"]}]}`,
+			wantScheme:   existingScheme,
+			wantSecurity: existingSecurity,
+		},
+		{
+			name: "force replaces existing scheme and requirement",
+			doc: `{"openapi":"3.0.0",
+				"components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer","bearerFormat":"JWT"}}},
+				"security":[{"oauth":["read"]}]}`,
+			force:        true,
+			wantScheme:   map[string]any{"type": "http", "scheme": "bearer"},
+			wantSecurity: []any{map[string]any{"bearerAuth": []any{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := injectSecurity(tt.doc, "bearer", tt.force)
+			if err != nil {
+				t.Fatalf("injectSecurity() error = %v", err)
+			}
+
+			var spec map[string]any
+			if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+				t.Fatalf("parsing result: %v", err)
+			}
+
+			schemes := spec["components"].(map[string]any)["securitySchemes"].(map[string]any)
+			if !reflect.DeepEqual(schemes["bearerAuth"], tt.wantScheme) {
+				t.Errorf("bearerAuth scheme = %v, want %v", schemes["bearerAuth"], tt.wantScheme)
+			}
+			if !reflect.DeepEqual(spec["security"], tt.wantSecurity) {
+				t.Errorf("security = %v, want %v", spec["security"], tt.wantSecurity)
+			}
+		})
+	}
+}
+
+func TestInjectSecurity_KeepsOtherSchemes(t *testing.T) {
+	doc, err := injectSecurity(`{"components":{"securitySchemes":{"oauth":{"type":"oauth2"}}}}`, "api-key", false)
+	if err != nil {
+		t.Fatalf("injectSecurity() error = %v", err)
+	}
+
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		t.Fatalf("parsing result: %v", err)
+	}
+
+	schemes := spec["components"].(map[string]any)["securitySchemes"].(map[string]any)
+	if _, ok := schemes["oauth"]; !ok {
+		t.Error("existing oauth scheme was removed")
+	}
+	if _, ok := schemes["apiKeyAuth"]; !ok {
+		t.Error("apiKeyAuth scheme was not added")
+	}
+}
+
+func TestInjectSecurity_InvalidScheme(t *testing.T) {
+	if _, err := injectSecurity(`{}`, "digest", false); err == nil {
+		t.Error("injectSecurity() error = nil, want invalid scheme error")
+	}
+}
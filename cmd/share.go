@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// shareRoles maps the -share values to the role granted to the whole team.
+var shareRoles = map[string]string{
+	"team-view": "VIEWER",
+	"team-edit": "EDITOR",
+}
+
+// WithShare makes the client share every imported collection with the team
+// using the given visibility, one of the keys of shareRoles.
+func WithShare(visibility string) ClientOption {
+	return func(c *APIClient) {
+		c.share = visibility
+	}
+}
+
+// shareCollection grants the team the role configured for the visibility.
+func (c *APIClient) shareCollection(ctx context.Context, ref CollectionRef, visibility string) error {
+	role, ok := shareRoles[visibility]
+	if !ok {
+		return fmt.Errorf("invalid share visibility %q", visibility)
+	}
+
+	payloadJSON, err := json.Marshal(map[string]any{
+		"roles": []map[string]any{
+			{"op": "update", "path": "/team", "value": role},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/roles", c.postmanBaseURL, ref.UpdateKey())
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return postmanStatusError(resp.StatusCode, fmt.Errorf("failed to share collection: %d %s", resp.StatusCode, string(body)))
+	}
+
+	c.log.InfoContext(ctx, "shared collection with the team", "collection", ref.UpdateKey(), "role", role)
+	return nil
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_ImportModuleSharesCollection(t *testing.T) {
+	var sharePath string
+	var sharePayload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2","name":"Customers"}]}`))
+		case "PATCH":
+			sharePath = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&sharePayload)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithShare("team-view"))
+	client.postmanBaseURL = server.URL
+
+	prepared := &PreparedModule{ModuleName: "Customers", CollectionName: "Customers Module API", WorkspaceID: "workspace", Doc: `{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`}
+	if err := client.ImportModule(t.Context(), prepared); err != nil {
+		t.Fatalf("ImportModule() error = %v", err)
+	}
+
+	if sharePath != "/collections/1-c2/roles" {
+		t.Errorf("share path = %q, want /collections/1-c2/roles", sharePath)
+	}
+	roles, _ := sharePayload["roles"].([]any)
+	if len(roles) != 1 || roles[0].(map[string]any)["value"] != "VIEWER" {
+		t.Errorf("share payload = %v, want the team VIEWER role", sharePayload)
+	}
+}
+
+func TestAPIClient_ImportModuleWithoutShare(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("unexpected %s request without -share", r.Method)
+		}
+		w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	if err := client.ImportModule(t.Context(), &PreparedModule{Doc: `{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`}); err != nil {
+		t.Fatalf("ImportModule() error = %v", err)
+	}
+}
+
+func TestAPIClient_ShareCollectionInvalidVisibility(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key")
+	if err := client.shareCollection(t.Context(), CollectionRef{UID: "1-c2"}, "public"); err == nil {
+		t.Error("shareCollection() error = nil, want invalid visibility error")
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// SetShutdownGrace sets how long modules already being synced may continue
+// once the context passed to SyncAllModules is done. No module starts after
+// that; the default of zero aborts in-flight modules right away.
+func (s *SyncOrchestrator) SetShutdownGrace(grace time.Duration) {
+	s.shutdownGrace = grace
+}
+
+// drainContext returns a context carrying the values of ctx that is cancelled
+// grace after ctx is done, or when the returned cancel function is called.
+func drainContext(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	work, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-work.Done():
+		}
+	})
+
+	return work, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drainProcessor blocks every module until released or cancelled.
+type drainProcessor struct {
+	started chan string
+	release chan struct{}
+
+	mu       sync.Mutex
+	finished []string
+}
+
+func (p *drainProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	p.started <- moduleName
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finished = append(p.finished, moduleName)
+	return nil
+}
+
+func TestSyncAllModules_ShutdownGrace(t *testing.T) {
+	tests := []struct {
+		name         string
+		grace        time.Duration
+		wantInFlight ModuleStatus
+	}{
+		{name: "in-flight module finishes within grace", grace: time.Minute, wantInFlight: StatusSucceeded},
+		{name: "in-flight module cancelled after grace", grace: 10 * time.Millisecond, wantInFlight: StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := &drainProcessor{started: make(chan string, 2), release: make(chan struct{})}
+			config := &ModuleConfig{
+				Modules:     map[string]string{"Brands": "Brands Module API", "Customers": "Customers Module API"},
+				Concurrency: 1,
+			}
+			orchestrator := NewSyncOrchestrator(processor, config)
+			orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+			orchestrator.SetShutdownGrace(tt.grace)
+
+			// The cancellation stands in for SIGTERM arriving mid-cycle.
+			ctx, cancel := context.WithCancel(t.Context())
+			go func() {
+				<-processor.started
+				cancel()
+				time.Sleep(50 * time.Millisecond)
+				close(processor.release)
+			}()
+
+			results, err := orchestrator.SyncAllModules(ctx, "workspace")
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("SyncAllModules() error = %v, want context.Canceled", err)
+			}
+
+			if len(results) != 2 {
+				t.Fatalf("got %d results, want 2: %+v", len(results), results)
+			}
+			if results[0].Module != "Brands" || results[0].Status != tt.wantInFlight {
+				t.Errorf("in-flight result = %+v, want Brands %s", results[0], tt.wantInFlight)
+			}
+			if results[1].Status != StatusSkipped {
+				t.Errorf("result = %+v, want Customers skipped after shutdown", results[1])
+			}
+		})
+	}
+}
+
+func TestDrainContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(withModule(t.Context(), "Customers"))
+	work, stop := drainContext(ctx, 20*time.Millisecond)
+	defer stop()
+
+	if moduleFrom(work) != "Customers" {
+		t.Error("drain context lost the values of its parent")
+	}
+
+	cancel()
+	select {
+	case <-work.Done():
+		t.Fatal("work context cancelled before the grace period")
+	case <-time.After(5 * time.Millisecond):
+	}
+
+	select {
+	case <-work.Done():
+	case <-time.After(time.Second):
+		t.Fatal("work context not cancelled after the grace period")
+	}
+}
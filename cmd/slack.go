@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// slackMessage is the payload of a Slack incoming webhook.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// SlackSummary formats the results of a sync as a Slack message: a line
+// counting the outcomes followed by one line per module that did not succeed.
+func SlackSummary(workspaceID string, results []ModuleResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API sync to workspace %s: %s", workspaceID, summarizeResults(results))
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\n• %s (%s) %s: %v", r.Module, r.Collection, r.Status, r.Err)
+	}
+	return b.String()
+}
+
+// NotifySlack posts the summary of the results to a Slack incoming webhook.
+func (c *APIClient) NotifySlack(ctx context.Context, webhookURL, workspaceID string, results []ModuleResult) error {
+	if err := c.postWebhook(ctx, webhookURL, slackMessage{Text: SlackSummary(workspaceID, results)}); err != nil {
+		return fmt.Errorf("notifying Slack: %w", err)
+	}
+	return nil
+}
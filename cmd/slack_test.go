@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIClient_NotifySlack(t *testing.T) {
+	var received slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decoding message: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	results := []ModuleResult{
+		{Module: "Brands", Collection: "Brands Module API", Status: StatusFailed, Err: errors.New("import failed")},
+		{Module: "Classes", Collection: "Classes Module API", Status: StatusSucceeded},
+		{Module: "Customers", Collection: "Customers Module API", Status: StatusSkipped, Err: errors.New("skipped: dependency Brands failed")},
+	}
+	if err := NewAPIClient("doc-key", "pm-key").NotifySlack(t.Context(), server.URL, "workspace", results); err != nil {
+		t.Fatalf("NotifySlack() error = %v", err)
+	}
+
+	want := "API sync to workspace workspace: 1 of 3 modules succeeded, 1 failed, 1 skipped\n" +
+		"• Brands (Brands Module API) failed: import failed\n" +
+		"• Customers (Customers Module API) skipped: skipped: dependency Brands failed"
+	if received.Text != want {
+		t.Errorf("text = %q, want %q", received.Text, want)
+	}
+}
+
+func TestAPIClient_NotifySlackFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewAPIClient("doc-key", "pm-key").NotifySlack(t.Context(), server.URL, "workspace", nil)
+	if err == nil {
+		t.Fatal("NotifySlack() error = nil, want the rejected webhook")
+	}
+}
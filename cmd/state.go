@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+// State is persisted between runs in the state file. It is safe for use by
+// the concurrently processed modules of a run.
+type State struct {
+	mu sync.Mutex
+
+	// NextBatch is the position in the sorted module list where the next
+	// -batch-size run starts.
+	NextBatch int `json:"nextBatch"`
+	// Collections maps each module to the uid of its Postman collection.
+	Collections map[string]string `json:"collections,omitempty"`
+	// Outcomes maps each module to its status at the end of the last run.
+	Outcomes map[string]ModuleStatus `json:"outcomes,omitempty"`
+	// DocHashes maps each module to the hash of the doc it was last synced
+	// from.
+	DocHashes map[string]string `json:"docHashes,omitempty"`
+	// Surfaces maps each module to the endpoints and fields of the doc it
+	// was last synced from.
+	Surfaces map[string]SpecSurface `json:"surfaces,omitempty"`
+	// DocValidators maps each module to the ETag and Last-Modified of the
+	// doc it was last synced from.
+	DocValidators map[string]DocValidator `json:"docValidators,omitempty"`
+	// DocHosts maps each doc host that failed on the last runs to its
+	// consecutive failures, for the circuit breaker.
+	DocHosts map[string]HostFailures `json:"docHosts,omitempty"`
+	// Conversions maps each module to the temporary collections an in-place
+	// update imported and has not deleted yet, by their delete key.
+	Conversions map[string][]string `json:"conversions,omitempty"`
+}
+
+// WithState makes the client remember the collection it imports for every
+// module in the given state.
+func WithState(state *State) ClientOption {
+	return func(c *APIClient) {
+		c.state = state
+	}
+}
+
+// CollectionID returns the uid recorded for the module's collection.
+func (s *State) CollectionID(module string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.Collections[module]
+	return id, ok
+}
+
+// SetCollectionID records the uid of the module's collection.
+func (s *State) SetCollectionID(module, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Collections == nil {
+		s.Collections = map[string]string{}
+	}
+	s.Collections[module] = id
+}
+
+// DocHash returns the hash recorded for the module's doc.
+func (s *State) DocHash(module string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.DocHashes[module]
+	return hash, ok
+}
+
+// SetDocHash records the hash of the doc the module was synced from.
+func (s *State) SetDocHash(module, hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.DocHashes == nil {
+		s.DocHashes = map[string]string{}
+	}
+	s.DocHashes[module] = hash
+}
+
+// DocValidator returns the validator recorded for the module's doc.
+func (s *State) DocValidator(module string) (DocValidator, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	validator, ok := s.DocValidators[module]
+	return validator, ok
+}
+
+// SetDocValidator records the validator of the doc the module was synced
+// from. An empty validator removes the recorded one.
+func (s *State) SetDocValidator(module string, validator DocValidator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if validator.isZero() {
+		delete(s.DocValidators, module)
+		return
+	}
+	if s.DocValidators == nil {
+		s.DocValidators = map[string]DocValidator{}
+	}
+	s.DocValidators[module] = validator
+}
+
+// DocHostFailures returns the consecutive failures recorded for a doc host.
+func (s *State) DocHostFailures(host string) (HostFailures, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.DocHosts[host]
+	return f, ok
+}
+
+// SetDocHostFailures records the consecutive failures of a doc host. No
+// failures remove the host.
+func (s *State) SetDocHostFailures(host string, f HostFailures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Count == 0 {
+		delete(s.DocHosts, host)
+		return
+	}
+	if s.DocHosts == nil {
+		s.DocHosts = map[string]HostFailures{}
+	}
+	s.DocHosts[host] = f
+}
+
+// Forget removes the collection, doc hash, surface and validator recorded
+// for the module, whose collection no longer exists.
+func (s *State) Forget(module string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.Collections, module)
+	delete(s.DocHashes, module)
+	delete(s.Surfaces, module)
+	delete(s.DocValidators, module)
+}
+
+// Surface returns the surface recorded for the module's doc.
+func (s *State) Surface(module string) (SpecSurface, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	surface, ok := s.Surfaces[module]
+	return surface, ok
+}
+
+// SetSurface records the surface of the doc the module was synced from.
+func (s *State) SetSurface(module string, surface SpecSurface) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Surfaces == nil {
+		s.Surfaces = map[string]SpecSurface{}
+	}
+	s.Surfaces[module] = surface
+}
+
+// RecordOutcomes stores the status of every result and returns the statuses
+// recorded by the previous run.
+func (s *State) RecordOutcomes(results []ModuleResult) map[string]ModuleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := maps.Clone(s.Outcomes)
+	if s.Outcomes == nil {
+		s.Outcomes = map[string]ModuleStatus{}
+	}
+	for _, r := range results {
+		s.Outcomes[r.Module] = r.Status
+	}
+	return previous
+}
+
+// LoadState reads the state file. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Save writes the state file.
+func (s *State) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ConversionsOf returns the temporary collections left by the module's
+// in-place updates.
+func (s *State) ConversionsOf(module string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.Conversions[module])
+}
+
+// AddConversion records a temporary collection imported for the module.
+func (s *State) AddConversion(module, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Conversions == nil {
+		s.Conversions = map[string][]string{}
+	}
+	s.Conversions[module] = append(s.Conversions[module], id)
+}
+
+// RemoveConversion forgets a temporary collection once it is deleted.
+func (s *State) RemoveConversion(module, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := slices.DeleteFunc(s.Conversions[module], func(c string) bool { return c == id })
+	if len(ids) == 0 {
+		delete(s.Conversions, module)
+		return
+	}
+	s.Conversions[module] = ids
+}
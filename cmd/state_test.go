@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadState_MissingFile(t *testing.T) {
+	state, err := LoadState(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.NextBatch != 0 {
+		t.Errorf("LoadState() = %+v, want empty state", state)
+	}
+}
+
+func TestState_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	saved := &State{NextBatch: 3}
+	saved.SetCollectionID("Customers", "1-c1")
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.NextBatch != 3 {
+		t.Errorf("NextBatch = %d, want 3", state.NextBatch)
+	}
+	if id, ok := state.CollectionID("Customers"); !ok || id != "1-c1" {
+		t.Errorf("CollectionID(Customers) = %q, %v, want 1-c1", id, ok)
+	}
+}
+
+func TestLoadState_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	os.WriteFile(path, []byte("not json"), 0o644)
+
+	if _, err := LoadState(path); err == nil {
+		t.Error("LoadState() error = nil, want parse error")
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Strategies order the delete and import steps of a module sync.
+const (
+	// StrategyDeleteFirst deletes the existing collections before importing.
+	// It never leaves duplicates behind, but the collection is missing until
+	// the import finishes, and for good when the import fails.
+	StrategyDeleteFirst = "delete-first"
+	// StrategyValidateFirst is kept for existing configurations. Every
+	// strategy now validates the doc before deleting anything, so it behaves
+	// like delete-first.
+	StrategyValidateFirst = "validate-first"
+	// StrategyImportFirst imports the new collection before deleting the old
+	// ones. The collection is never missing, but both versions exist for a
+	// moment, and for good when the delete fails.
+	StrategyImportFirst = "import-first"
+)
+
+var validStrategies = []string{StrategyDeleteFirst, StrategyValidateFirst, StrategyImportFirst}
+
+// WithStrategy sets the order of the delete and import steps, one of
+// validStrategies. The default is StrategyDeleteFirst.
+func WithStrategy(strategy string) ClientOption {
+	return func(c *APIClient) {
+		c.strategy = strategy
+	}
+}
+
+// ValidateSpec returns an error unless doc is a JSON object declaring an
+// OpenAPI or Swagger version and at least one path. Every fetched doc is
+// checked with it before anything is deleted or imported.
+func ValidateSpec(doc string) error {
+	var spec struct {
+		OpenAPI string         `json:"openapi"`
+		Swagger string         `json:"swagger"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return fmt.Errorf("invalid spec: %w", err)
+	}
+
+	if spec.OpenAPI == "" && spec.Swagger == "" {
+		return errors.New("invalid spec: no openapi or swagger version")
+	}
+	if len(spec.Paths) == 0 {
+		return errors.New("invalid spec: no paths")
+	}
+
+	return nil
+}
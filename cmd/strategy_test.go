@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// strategyServers starts a doc server returning doc and a Postman server
+// recording the method and path of every request.
+func strategyServers(t *testing.T, doc string) (*APIClient, *[]string) {
+	t.Helper()
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(doc))
+	}))
+	t.Cleanup(docServer.Close)
+
+	var calls []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"collections":[{"id":"old","uid":"1-old","name":"Customers Module API"}]}`))
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Customers Module API"}]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(postman.Close)
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+	return client, &calls
+}
+
+func TestAPIClient_ProcessModuleStrategies(t *testing.T) {
+	const validSpec = `{"openapi":"3.0.0","paths":{"/customers":{"get":{}}}}`
+	const invalidSpec = `{"title":"not a spec"}`
+
+	tests := []struct {
+		name      string
+		strategy  string
+		doc       string
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name:      "delete-first",
+			strategy:  StrategyDeleteFirst,
+			doc:       validSpec,
+			wantCalls: []string{"GET /collections", "DELETE /collections/old", "POST /import/openapi"},
+		},
+		{
+			name:      "validate-first",
+			strategy:  StrategyValidateFirst,
+			doc:       validSpec,
+			wantCalls: []string{"GET /collections", "DELETE /collections/old", "POST /import/openapi"},
+		},
+		{
+			name:      "import-first",
+			strategy:  StrategyImportFirst,
+			doc:       validSpec,
+			wantCalls: []string{"GET /collections", "POST /import/openapi", "DELETE /collections/old"},
+		},
+		{
+			name:      "validate-first stops on an invalid spec",
+			strategy:  StrategyValidateFirst,
+			doc:       invalidSpec,
+			wantCalls: nil,
+			wantErr:   true,
+		},
+		{
+			name:      "delete-first keeps the collection on an invalid spec",
+			strategy:  StrategyDeleteFirst,
+			doc:       invalidSpec,
+			wantCalls: nil,
+			wantErr:   true,
+		},
+		{
+			name:      "import-first leaves the collection alone on an invalid spec",
+			strategy:  StrategyImportFirst,
+			doc:       invalidSpec,
+			wantCalls: nil,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := strategyServers(t, tt.doc)
+			client.strategy = tt.strategy
+
+			err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessModule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{name: "openapi", doc: `{"openapi":"3.0.0","paths":{"/a":{}}}`},
+		{name: "swagger", doc: `{"swagger":"2.0","paths":{"/a":{}}}`},
+		{name: "no version", doc: `{"paths":{"/a":{}}}`, wantErr: true},
+		{name: "no paths", doc: `{"openapi":"3.0.0","paths":{}}`, wantErr: true},
+		{name: "missing paths", doc: `{"openapi":"3.0.0"}`, wantErr: true},
+		{name: "empty", doc: ``, wantErr: true},
+		{name: "not JSON", doc: `<html>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSpec(tt.doc); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ResultStream appends module results to a writer as JSON lines, one per
+// module, as they complete. It is safe for concurrent use.
+type ResultStream struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewResultStream returns a stream writing to w.
+func NewResultStream(w io.Writer) *ResultStream {
+	return &ResultStream{w: w}
+}
+
+// Write appends the result as a single JSON line.
+func (s *ResultStream) Write(result ModuleResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding result of module %s: %w", result.Module, err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(line); err != nil {
+		return fmt.Errorf("writing result of module %s: %w", result.Module, err)
+	}
+	return nil
+}
+
+// SetResultStream streams each module's result to stream as soon as the
+// module completes, so a crash mid-sync keeps the finished modules' results.
+func (s *SyncOrchestrator) SetResultStream(stream *ResultStream) {
+	s.stream = stream
+}
+
+// completed streams a finished module's result, if a stream is set.
+func (s *SyncOrchestrator) completed(result ModuleResult) {
+	if s.stream == nil {
+		return
+	}
+	if err := s.stream.Write(result); err != nil {
+		s.log.ErrorContext(withModule(context.Background(), result.Module), "streaming result failed", "error", err)
+	}
+}
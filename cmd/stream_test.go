@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSyncAllModules_StreamsResults(t *testing.T) {
+	for _, batchCleanup := range []bool{false, true} {
+		var processor ModuleProcessor = &recordingProcessor{fail: map[string]bool{"Brands": true}}
+		if batchCleanup {
+			processor = &phasedProcessor{failImport: "Brands"}
+		}
+		config := &ModuleConfig{
+			Modules: map[string]string{
+				"Customers": "Customers Module API",
+				"Brands":    "Brands Module API",
+				"Classes":   "Classes Module API",
+			},
+			BatchCleanup: batchCleanup,
+		}
+
+		var b bytes.Buffer
+		orchestrator := NewSyncOrchestrator(processor, config)
+		orchestrator.SetResultStream(NewResultStream(&b))
+		results, _ := orchestrator.SyncAllModules(t.Context(), "workspace")
+
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		if len(lines) != len(results) {
+			t.Fatalf("batchCleanup=%v: streamed %d lines, want one per module:\n%s", batchCleanup, len(lines), b.String())
+		}
+
+		streamed := map[string]string{}
+		for _, line := range lines {
+			var got struct {
+				Module string       `json:"module"`
+				Status ModuleStatus `json:"status"`
+				Error  string       `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(line), &got); err != nil {
+				t.Fatalf("batchCleanup=%v: invalid JSON line %q: %v", batchCleanup, line, err)
+			}
+			streamed[got.Module] = string(got.Status)
+		}
+		for _, r := range results {
+			if streamed[r.Module] != string(r.Status) {
+				t.Errorf("batchCleanup=%v: streamed %s as %q, want %q", batchCleanup, r.Module, streamed[r.Module], r.Status)
+			}
+		}
+	}
+}
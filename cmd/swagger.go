@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+)
+
+// isSwagger2 reports whether a JSON spec is a Swagger 2.0 document.
+func isSwagger2(doc string) bool {
+	var spec struct {
+		Swagger string `json:"swagger"`
+	}
+	return json.Unmarshal([]byte(doc), &spec) == nil && spec.Swagger == "2.0"
+}
+
+// convertSwagger2 converts a Swagger 2.0 spec to OpenAPI 3.0, which Postman
+// imports with request bodies intact.
+func convertSwagger2(doc string) (string, error) {
+	var v2 openapi2.T
+	if err := json.Unmarshal([]byte(doc), &v2); err != nil {
+		return "", fmt.Errorf("parsing swagger spec: %w", err)
+	}
+
+	v3, err := openapi2conv.ToV3(&v2)
+	if err != nil {
+		return "", fmt.Errorf("converting swagger spec: %w", err)
+	}
+
+	out, err := json.MarshalIndent(v3, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling converted spec: %w", err)
+	}
+	return string(out), nil
+}
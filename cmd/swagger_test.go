@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const swagger2Spec = `{
+  "swagger": "2.0",
+  "info": {"title": "Customers", "version": "1.0.0"},
+  "host": "api.example.com",
+  "basePath": "/v1",
+  "schemes": ["https"],
+  "paths": {
+    "/customers": {
+      "post": {
+        "consumes": ["application/json"],
+        "parameters": [{"in": "body", "name": "customer", "required": true, "schema": {"$ref": "#/definitions/Customer"}}],
+        "responses": {"201": {"description": "Created"}}
+      }
+    }
+  },
+  "definitions": {"Customer": {"type": "object", "properties": {"name": {"type": "string"}}}}
+}`
+
+func TestConvertSwagger2(t *testing.T) {
+	if !isSwagger2(swagger2Spec) {
+		t.Fatal("isSwagger2() = false for a swagger 2.0 spec")
+	}
+
+	converted, err := convertSwagger2(swagger2Spec)
+	if err != nil {
+		t.Fatalf("convertSwagger2() error = %v", err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			RequestBody *struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(converted), &spec); err != nil {
+		t.Fatalf("converted spec is not JSON: %v", err)
+	}
+
+	if spec.OpenAPI == "" {
+		t.Errorf("converted spec has no openapi field:\n%s", converted)
+	}
+	body := spec.Paths["/customers"]["post"].RequestBody
+	if body == nil || body.Content["application/json"] == nil {
+		t.Errorf("converted operation has no JSON request body:\n%s", converted)
+	}
+	if spec.Components.Schemas["Customer"] == nil {
+		t.Errorf("converted spec has no Customer schema:\n%s", converted)
+	}
+}
+
+func TestIsSwagger2_OpenAPI3(t *testing.T) {
+	if isSwagger2(`{"openapi": "3.0.0", "info": {"title": "Customers", "version": "1.0.0"}, "paths": {}}`) {
+		t.Error("isSwagger2() = true for an openapi 3.0 spec")
+	}
+}
+
+func TestAPIClient_PrepareModuleConvertsSwagger2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(swagger2Spec))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "", WithMode(ModeFetchOnly), WithOutput(io.Discard))
+	client.docURL = func(string) string { return server.URL }
+
+	prepared, err := client.PrepareModule(t.Context(), "Customers", "Customers Module API", "workspace")
+	if err != nil {
+		t.Fatalf("PrepareModule() error = %v", err)
+	}
+	if isSwagger2(prepared.Doc) {
+		t.Errorf("prepared doc is still swagger 2.0:\n%s", prepared.Doc)
+	}
+	if err := ValidateSpec(prepared.Doc); err != nil {
+		t.Errorf("ValidateSpec() of the converted doc error = %v", err)
+	}
+}
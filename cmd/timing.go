@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// PhaseTimings splits the time spent on a module by what it waited for.
+type PhaseTimings struct {
+	// Fetch is the time spent fetching the doc.
+	Fetch time.Duration
+	// Delete is the time spent listing the existing collections and
+	// deleting them.
+	Delete time.Duration
+	// Import is the time spent importing, or updating, the collection and
+	// the steps following it.
+	Import time.Duration
+}
+
+// PhaseTimer is implemented by processors that time the phases of every
+// module, so the orchestrator can add them to the module's result.
+type PhaseTimer interface {
+	PhaseTimings() map[string]PhaseTimings
+}
+
+// Module phases timed by phaseTimingLog.
+const (
+	phaseFetch  = "fetch"
+	phaseDelete = "delete"
+	phaseImport = "import"
+)
+
+// phaseTimingLog collects the phase timings per module. It is shared by all
+// copies of a client.
+type phaseTimingLog struct {
+	mu      sync.Mutex
+	modules map[string]PhaseTimings
+}
+
+func (l *phaseTimingLog) add(module, phase string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.modules == nil {
+		l.modules = map[string]PhaseTimings{}
+	}
+	t := l.modules[module]
+	switch phase {
+	case phaseFetch:
+		t.Fetch += d
+	case phaseDelete:
+		t.Delete += d
+	case phaseImport:
+		t.Import += d
+	}
+	l.modules[module] = t
+}
+
+// timePhase adds the time since start to the phase of the module ctx is
+// tagged with.
+func (c *APIClient) timePhase(ctx context.Context, phase string, start time.Time) {
+	c.timings.add(moduleFrom(ctx), phase, time.Since(start))
+}
+
+// PhaseTimings returns the phase timings so far, per module.
+func (c *APIClient) PhaseTimings() map[string]PhaseTimings {
+	c.timings.mu.Lock()
+	defer c.timings.mu.Unlock()
+
+	return maps.Clone(c.timings.modules)
+}
+
+// phaseTimings returns the phase timings of the module when the processor
+// records them.
+func (s *SyncOrchestrator) phaseTimings(module string) *PhaseTimings {
+	timer, ok := s.processor.(PhaseTimer)
+	if !ok {
+		return nil
+	}
+	timings, ok := timer.PhaseTimings()[module]
+	if !ok {
+		return nil
+	}
+	return &timings
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncAllModules_PhaseTimings(t *testing.T) {
+	const delay = 20 * time.Millisecond
+
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			time.Sleep(delay)
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+		case "POST":
+			time.Sleep(2 * delay)
+			w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2","name":"Customers Module API"}]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	var logs bytes.Buffer
+	client := NewAPIClient("doc-key", "pm-key", WithLogger(NewLogger(&logs, slog.LevelDebug)))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+	results, err := NewSyncOrchestrator(client, config).SyncAllModules(t.Context(), "workspace")
+	if err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	phases := results[0].Phases
+	if phases == nil {
+		t.Fatal("result has no phase timings")
+	}
+	if phases.Fetch < delay || phases.Delete < delay || phases.Import < 2*delay {
+		t.Errorf("Phases = %+v, want at least %v, %v and %v", *phases, delay, delay, 2*delay)
+	}
+	if phases.Fetch+phases.Delete+phases.Import > results[0].Duration {
+		t.Errorf("Phases = %+v add up to more than the duration %v", *phases, results[0].Duration)
+	}
+	if !strings.Contains(logs.String(), "module timings") {
+		t.Errorf("no timings logged at debug level:\n%s", logs.String())
+	}
+}
+
+func TestSyncAllModules_NoPhaseTimings(t *testing.T) {
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API"}}
+	results, err := NewSyncOrchestrator(&recordingProcessor{}, config).SyncAllModules(t.Context(), "workspace")
+	if err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+	if results[0].Phases != nil {
+		t.Errorf("Phases = %+v, want none from a processor without timings", results[0].Phases)
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"text/tabwriter"
+)
+
+// ModuleTransfer counts the bytes a module moved during a run.
+type ModuleTransfer struct {
+	// DocBytes is the size of the doc bodies fetched from the doc API.
+	DocBytes int64 `json:"docBytes"`
+	// PostmanBytes is the size of the request bodies sent to Postman,
+	// counting a retried request once and compressed bodies at their
+	// encoded size.
+	PostmanBytes int64 `json:"postmanBytes"`
+}
+
+// transferStats collects the bytes transferred per module. It is shared by
+// all copies of a client.
+type transferStats struct {
+	mu      sync.Mutex
+	modules map[string]ModuleTransfer
+}
+
+func (s *transferStats) add(module string, doc, postman int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.modules == nil {
+		s.modules = map[string]ModuleTransfer{}
+	}
+	t := s.modules[module]
+	t.DocBytes += doc
+	t.PostmanBytes += postman
+	s.modules[module] = t
+}
+
+// Transfers returns the bytes transferred so far, per module.
+func (c *APIClient) Transfers() map[string]ModuleTransfer {
+	c.transfers.mu.Lock()
+	defer c.transfers.mu.Unlock()
+
+	return maps.Clone(c.transfers.modules)
+}
+
+// TotalTransfer sums the transfers of all modules.
+func TotalTransfer(transfers map[string]ModuleTransfer) ModuleTransfer {
+	var total ModuleTransfer
+	for _, t := range transfers {
+		total.DocBytes += t.DocBytes
+		total.PostmanBytes += t.PostmanBytes
+	}
+	return total
+}
+
+// WriteTransferSummary writes the bytes transferred per module and in total
+// as a table.
+func WriteTransferSummary(w io.Writer, transfers map[string]ModuleTransfer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "MODULE\tDOC BYTES\tPOSTMAN BYTES\t")
+
+	for _, module := range slices.Sorted(maps.Keys(transfers)) {
+		t := transfers[module]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t\n", module, t.DocBytes, t.PostmanBytes)
+	}
+
+	total := TotalTransfer(transfers)
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\t\n", total.DocBytes, total.PostmanBytes)
+
+	return tw.Flush()
+}
+
+// countingBody reports the bytes read from a request body of unknown length.
+type countingBody struct {
+	io.ReadCloser
+	count func(n int)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.count(n)
+	}
+	return n, err
+}
+
+type moduleKey struct{}
+
+// withModule tags ctx with the module its requests are made for, so the
+// transferred bytes can be attributed to it.
+func withModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, moduleKey{}, module)
+}
+
+func moduleFrom(ctx context.Context) string {
+	module, _ := ctx.Value(moduleKey{}).(string)
+	return module
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_TransfersPerModule(t *testing.T) {
+	docs := map[string]string{
+		"/Customers": `{"openapi": "3.0.0", "info": {"title": "Customers"},"paths":{"/customers":{"get":{}}}}`,
+		"/Brands":    `{"openapi":"3.0.0","info":{"title":"Brands","description":"A much longer description of the brands API"},"paths":{"/customers":{"get":{}}}}`,
+	}
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(docs[r.URL.Path]))
+	}))
+	defer docServer.Close()
+
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			// Name the collection as configured, so it is not renamed.
+			name := "Customers Module API"
+			if body, _ := io.ReadAll(r.Body); bytes.Contains(body, []byte("Brands")) {
+				name = "Brands Module API"
+			}
+			fmt.Fprintf(w, `{"collections":[{"id":"c1","uid":"1-c1","name":%q}]}`, name)
+			return
+		}
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(module string) string { return docServer.URL + "/" + module }
+
+	config := &ModuleConfig{Modules: map[string]string{"Customers": "Customers Module API", "Brands": "Brands Module API"}}
+	orchestrator := NewSyncOrchestrator(client, config)
+	orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+	if _, err := orchestrator.SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	transfers := client.Transfers()
+	var want ModuleTransfer
+	for module := range config.Modules {
+		doc, err := client.fetchDoc(t.Context(), client.docURL(module))
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload := marshalImportPayload(t, doc, ImportOptions{})
+
+		got := transfers[module]
+		wantModule := ModuleTransfer{DocBytes: int64(len(docs["/"+module])), PostmanBytes: int64(len(payload))}
+		if got != wantModule {
+			t.Errorf("transfers[%s] = %+v, want %+v", module, got, wantModule)
+		}
+		want.DocBytes += wantModule.DocBytes
+		want.PostmanBytes += wantModule.PostmanBytes
+	}
+
+	if total := TotalTransfer(transfers); total != want {
+		t.Errorf("TotalTransfer() = %+v, want %+v", total, want)
+	}
+}
+
+func TestAPIClient_TransfersCountRetriedRequestOnce(t *testing.T) {
+	attempts := 0
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if attempts++; attempts == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithRetry(1, 0))
+	client.postmanBaseURL = postman.URL
+
+	ref := CollectionRef{UID: "1-c1"}
+	collection := map[string]any{"info": map[string]any{"name": "Customers Module API"}}
+	if err := client.updateCollection(withModule(t.Context(), "Customers"), ref, collection); err != nil {
+		t.Fatalf("updateCollection() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("got %d attempts, want the 503 retried once", attempts)
+	}
+
+	body, _ := json.Marshal(map[string]any{"collection": collection})
+	if got := client.Transfers()["Customers"].PostmanBytes; got != int64(len(body)) {
+		t.Errorf("PostmanBytes = %d, want the body size %d counted once", got, len(body))
+	}
+}
+
+func TestWriteTransferSummary(t *testing.T) {
+	var b strings.Builder
+	err := WriteTransferSummary(&b, map[string]ModuleTransfer{
+		"Customers": {DocBytes: 1200, PostmanBytes: 1500},
+		"Brands":    {DocBytes: 80, PostmanBytes: 100},
+	})
+	if err != nil {
+		t.Fatalf("WriteTransferSummary() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header, two modules and total:\n%s", len(lines), b.String())
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[1]), "Brands") {
+		t.Errorf("modules should be sorted:\n%s", b.String())
+	}
+	if fields := strings.Fields(lines[3]); strings.Join(fields, " ") != "TOTAL 1280 1600" {
+		t.Errorf("total line = %q, want TOTAL 1280 1600", lines[3])
+	}
+}
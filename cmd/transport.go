@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// defaultTimeout is the default time limit of a single HTTP request.
+const defaultTimeout = 30 * time.Second
+
+// WithTimeout sets the time limit of every HTTP request, including reading
+// its response. Modules whose client settings have no timeout inherit it.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *APIClient) {
+		if timeout > 0 {
+			c.httpClient.Timeout = timeout
+		}
+	}
+}
+
+// ClientSettings holds the HTTP transport settings used for a module's requests.
+// Zero values fall back to the settings of the client they are applied to.
+type ClientSettings struct {
+	Timeout            time.Duration
+	ProxyURL           string
+	CACertFile         string
+	ClientCertFile     string
+	ClientKeyFile      string
+	InsecureSkipVerify bool
+}
+
+// ClientConfigurer is implemented by processors that can derive a copy of
+// themselves using module-specific HTTP client settings.
+type ClientConfigurer interface {
+	WithClientSettings(settings ClientSettings) (ModuleProcessor, error)
+}
+
+// WithTransport sends every request, to the docs and to Postman, through rt
+// instead of a network transport, e.g. to serve them in tests or to
+// intercept them. Modules with client settings of their own use rt too, with
+// only their timeout applied; proxy, TLS and connection settings need a
+// network transport and are ignored.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *APIClient) {
+		c.transport = rt
+		c.httpClient.Transport = rt
+	}
+}
+
+// WithProxy sends every request through the given proxy instead of the one
+// named by HTTP_PROXY, HTTPS_PROXY and NO_PROXY. Modules whose client settings
+// have no proxy inherit it.
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(c *APIClient) {
+		if proxy == nil {
+			return
+		}
+		c.proxyURL = proxy.String()
+		if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+	}
+}
+
+// WithConcurrencyPerHost caps the connections open to any one host, such as
+// the Postman API, at n, however many modules run in parallel; requests
+// beyond it wait for a connection. Zero means no cap. Modules with client
+// settings of their own apply the same cap to their own connections.
+func WithConcurrencyPerHost(n int) ClientOption {
+	return func(c *APIClient) {
+		c.connsPerHost = n
+		if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+			limitConnsPerHost(transport, n)
+		}
+	}
+}
+
+// limitConnsPerHost caps the transport's connections per host at n, keeping
+// as many idle for reuse. Zero leaves the transport unchanged.
+func limitConnsPerHost(transport *http.Transport, n int) {
+	if n > 0 {
+		transport.MaxConnsPerHost = n
+		transport.MaxIdleConnsPerHost = n
+	}
+}
+
+// ParseProxyURL parses an explicit proxy URL, which needs a scheme and a host.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	if proxy.Scheme == "" || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: want scheme://host[:port]", raw)
+	}
+	return proxy, nil
+}
+
+// newTransport returns a transport using the proxy named by HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY.
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	return transport
+}
+
+func newHTTPClient(settings ClientSettings) (*http.Client, error) {
+	transport := newTransport()
+
+	if settings.ProxyURL != "" {
+		proxyURL, err := url.Parse(settings.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}, nil
+}
+
+func newTLSConfig(settings ClientSettings) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: settings.InsecureSkipVerify}
+
+	if settings.CACertFile != "" {
+		pem, err := os.ReadFile(settings.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", settings.CACertFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if (settings.ClientCertFile == "") != (settings.ClientKeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+
+	if settings.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(settings.ClientCertFile, settings.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// WithClientSettings returns a copy of the client whose requests use the given
+// transport settings. The API keys are shared with the original client.
+func (c *APIClient) WithClientSettings(settings ClientSettings) (ModuleProcessor, error) {
+	if settings.Timeout == 0 {
+		settings.Timeout = c.httpClient.Timeout
+	}
+	if settings.ProxyURL == "" {
+		settings.ProxyURL = c.proxyURL
+	}
+
+	httpClient, err := newHTTPClient(settings)
+	if err != nil {
+		return nil, err
+	}
+	limitConnsPerHost(httpClient.Transport.(*http.Transport), c.connsPerHost)
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+
+	if c.cassette != nil {
+		httpClient.Transport = c.cassette.Transport(httpClient.Transport)
+	}
+
+	c.httpClients.add(httpClient)
+
+	clone := *c
+	clone.httpClient = httpClient
+	return &clone, nil
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// configurableProcessor records which client settings each module was processed with.
+type configurableProcessor struct {
+	settings ClientSettings
+	mu       *sync.Mutex
+	used     map[string]ClientSettings
+}
+
+func (p *configurableProcessor) WithClientSettings(settings ClientSettings) (ModuleProcessor, error) {
+	return &configurableProcessor{settings: settings, mu: p.mu, used: p.used}, nil
+}
+
+func (p *configurableProcessor) ProcessModule(ctx context.Context, moduleName, collectionName, workspaceID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.used[moduleName] = p.settings
+	return nil
+}
+
+func TestSyncAllModules_PerModuleClientSettings(t *testing.T) {
+	processor := &configurableProcessor{mu: &sync.Mutex{}, used: map[string]ClientSettings{}}
+	config := &ModuleConfig{
+		Modules: map[string]string{
+			"Customers": "Customers Module API",
+			"Brands":    "Brands Module API",
+			"Home":      "Home Module API",
+		},
+		ClientSettings: map[string]ClientSettings{
+			"Customers": {Timeout: 5 * time.Second},
+			"Brands":    {ProxyURL: "http://proxy.internal:3128"},
+		},
+	}
+
+	if _, err := NewSyncOrchestrator(processor, config).SyncAllModules(t.Context(), "workspace"); err != nil {
+		t.Fatalf("SyncAllModules() error = %v", err)
+	}
+
+	want := map[string]ClientSettings{
+		"Customers": {Timeout: 5 * time.Second},
+		"Brands":    {ProxyURL: "http://proxy.internal:3128"},
+		"Home":      {},
+	}
+	for module, settings := range want {
+		if got := processor.used[module]; got != settings {
+			t.Errorf("module %s used settings %+v, want %+v", module, got, settings)
+		}
+	}
+}
+
+func TestSyncAllModules_InvalidClientSettings(t *testing.T) {
+	config := &ModuleConfig{
+		Modules:        map[string]string{"Customers": "Customers Module API"},
+		ClientSettings: map[string]ClientSettings{"Customers": {ClientCertFile: "cert.pem"}},
+	}
+
+	_, err := NewSyncOrchestrator(NewAPIClient("doc", "pm"), config).SyncAllModules(t.Context(), "workspace")
+	if err == nil {
+		t.Fatal("SyncAllModules() error = nil, want client settings error")
+	}
+}
+
+func TestAPIClient_WithClientSettings(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key")
+
+	processor, err := client.WithClientSettings(ClientSettings{
+		Timeout:  10 * time.Second,
+		ProxyURL: "http://proxy.internal:3128",
+	})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+
+	configured := processor.(*APIClient)
+	if configured.httpClient.Timeout != 10*time.Second {
+		t.Errorf("Timeout = %v, want %v", configured.httpClient.Timeout, 10*time.Second)
+	}
+	if configured.docAPIKey != "doc-key" || configured.pmAPIKey != "pm-key" {
+		t.Error("WithClientSettings() should keep the API keys")
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.getpostman.com/collections", nil)
+	proxy, err := configured.httpClient.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("Proxy = %v, %v, want proxy.internal:3128", proxy, err)
+	}
+
+	if client.httpClient.Timeout != defaultTimeout {
+		t.Error("WithClientSettings() should not modify the original client")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	client := NewAPIClient("doc-key", "pm-key", WithTimeout(2*time.Minute))
+	if client.httpClient.Timeout != 2*time.Minute {
+		t.Errorf("Timeout = %v, want %v", client.httpClient.Timeout, 2*time.Minute)
+	}
+
+	processor, err := client.WithClientSettings(ClientSettings{ProxyURL: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+	if got := processor.(*APIClient).httpClient.Timeout; got != 2*time.Minute {
+		t.Errorf("module Timeout = %v, want the client's %v", got, 2*time.Minute)
+	}
+}
+
+func TestWithConcurrencyPerHost(t *testing.T) {
+	const limit = 2
+
+	var mu sync.Mutex
+	var open, maxOpen int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch state {
+		case http.StateNew:
+			open++
+			maxOpen = max(maxOpen, open)
+		case http.StateClosed, http.StateHijacked:
+			open--
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithConcurrencyPerHost(limit))
+	client.postmanBaseURL = server.URL
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			if _, err := client.listCollections(t.Context(), "workspace"); err != nil {
+				t.Errorf("listCollections() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if maxOpen > limit {
+		t.Errorf("%d connections open at once, want at most %d", maxOpen, limit)
+	}
+
+	processor, err := client.WithClientSettings(ClientSettings{Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+	if got := processor.(*APIClient).httpClient.Transport.(*http.Transport).MaxConnsPerHost; got != limit {
+		t.Errorf("module MaxConnsPerHost = %d, want the client's %d", got, limit)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(`{"collections":[]}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := ParseProxyURL(proxy.URL)
+	if err != nil {
+		t.Fatalf("ParseProxyURL() error = %v", err)
+	}
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithProxy(proxyURL))
+	client.postmanBaseURL = "http://postman.invalid"
+	if _, err := client.listCollections(t.Context(), "workspace"); err != nil {
+		t.Fatalf("listCollections() error = %v", err)
+	}
+
+	processor, err := client.WithClientSettings(ClientSettings{Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+	if _, err := processor.(*APIClient).listCollections(t.Context(), "workspace"); err != nil {
+		t.Fatalf("module listCollections() error = %v", err)
+	}
+
+	if len(proxied) != 2 || !strings.HasPrefix(proxied[0], "http://postman.invalid/collections") {
+		t.Errorf("proxied requests = %v, want both listings through the proxy", proxied)
+	}
+}
+
+func TestParseProxyURL(t *testing.T) {
+	for _, raw := range []string{"proxy.internal:3128", "http://", "http://%zz"} {
+		if _, err := ParseProxyURL(raw); err == nil {
+			t.Errorf("ParseProxyURL(%q) error = nil, want invalid proxy URL", raw)
+		}
+	}
+}
+
+func TestNewHTTPClient_Defaults(t *testing.T) {
+	client, err := newHTTPClient(ClientSettings{})
+	if err != nil {
+		t.Fatalf("newHTTPClient() error = %v", err)
+	}
+	if client.Timeout != defaultTimeout {
+		t.Errorf("Timeout = %v, want %v", client.Timeout, defaultTimeout)
+	}
+}
+
+func TestNewHTTPClient_InvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ClientSettings
+	}{
+		{name: "invalid proxy URL", settings: ClientSettings{ProxyURL: "://bad"}},
+		{name: "missing CA file", settings: ClientSettings{CACertFile: "does-not-exist.pem"}},
+		{name: "cert without key", settings: ClientSettings{ClientCertFile: "cert.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newHTTPClient(tt.settings); err == nil {
+				t.Error("newHTTPClient() error = nil, want error")
+			}
+		})
+	}
+}
+
+// handlerTransport serves requests with a handler instead of the network.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func TestWithTransport_ProcessModule(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	transport := handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Host+r.URL.Path)
+		mu.Unlock()
+
+		switch {
+		case r.URL.Host == "api.customers.vivalabs-dev.link":
+			w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Customers","version":"1"},"paths":{"/customers":{"get":{"responses":{"200":{"description":"OK"}}}}}}`))
+		case r.Method == "GET":
+			w.Write([]byte(`{"collections":[{"id":"old","uid":"1-old","name":"Customers Module API"}]}`))
+		case r.Method == "POST":
+			w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Customers Module API"}]}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	})}
+
+	client := NewAPIClient("doc-key", "pm-key", WithTransport(transport), WithOutput(io.Discard))
+	processor, err := client.WithClientSettings(ClientSettings{Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("WithClientSettings() error = %v", err)
+	}
+
+	if err := processor.ProcessModule(t.Context(), "customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	want := []string{
+		"GET api.customers.vivalabs-dev.link/v1/internal-docs",
+		"GET api.getpostman.com/collections",
+		"DELETE api.getpostman.com/collections/old",
+		"POST api.getpostman.com/import/openapi",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests =\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWithPostmanBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+	}))
+	defer server.Close()
+
+	for _, baseURL := range []string{server.URL, server.URL + "/"} {
+		client := NewAPIClient("doc-key", "pm-key", WithPostmanBaseURL(baseURL), WithOutput(io.Discard))
+		refs, err := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+		if err != nil {
+			t.Fatalf("getCollectionsByName() with base URL %s error = %v", baseURL, err)
+		}
+		if len(refs) != 1 || refs[0].ID != "c1" {
+			t.Errorf("getCollectionsByName() with base URL %s = %+v, want the collection of the test server", baseURL, refs)
+		}
+	}
+
+	if client := NewAPIClient("doc-key", "pm-key", WithPostmanBaseURL("")); client.postmanBaseURL != defaultPostmanBaseURL {
+		t.Errorf("postmanBaseURL = %q, want the default for an empty base URL", client.postmanBaseURL)
+	}
+}
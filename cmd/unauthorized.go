@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrPostmanUnauthorized is wrapped by the errors of Postman requests that
+// were refused the API key. Every module fails the same way then, so
+// SyncAllModules stops at the first one.
+var ErrPostmanUnauthorized = errors.New("postman rejected the API key")
+
+// keyRejected reports whether a status means the API key was not accepted.
+func keyRejected(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
+
+// postmanStatusError wraps err, the error of a Postman request answered with
+// status, with ErrPostmanUnauthorized when the status rejects the API key.
+func postmanStatusError(status int, err error) error {
+	if keyRejected(status) {
+		return fmt.Errorf("%w: %w", ErrPostmanUnauthorized, err)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAPIClient_PostmanUnauthorized(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		want   bool
+	}{
+		{status: http.StatusUnauthorized, want: true},
+		{status: http.StatusForbidden, want: true},
+		{status: http.StatusInternalServerError, want: false},
+	} {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"error":{"name":"AuthenticationError"}}`))
+			}))
+			defer postman.Close()
+
+			client := NewAPIClient("doc-key", "pm-key", WithOutput(io.Discard), WithRetry(0, 0))
+			client.postmanBaseURL = postman.URL
+
+			_, listErr := client.getCollectionsByName(t.Context(), "Customers Module API", "workspace")
+			deleteErr := client.deleteCollection(t.Context(), CollectionRef{ID: "c1", UID: "1-c1"})
+			_, _, importErr := client.importToPostman(t.Context(), `{"openapi":"3.0.0"}`, "Customers Module API", "workspace", ImportOptions{})
+			_, getErr := client.getCollection(t.Context(), CollectionRef{ID: "c1", UID: "1-c1"})
+			checkErr := client.checkConfiguredCollection(t.Context(), "Customers", "1-c1")
+			shareErr := client.shareCollection(t.Context(), CollectionRef{ID: "c1", UID: "1-c1"}, "team-view")
+			_, workspaceErr := client.getWorkspace(t.Context(), "workspace")
+			_, resolveErr := client.ResolveWorkspaceID(t.Context(), "Team")
+
+			for name, err := range map[string]error{
+				"list":              listErr,
+				"delete":            deleteErr,
+				"import":            importErr,
+				"get collection":    getErr,
+				"check collection":  checkErr,
+				"share":             shareErr,
+				"get workspace":     workspaceErr,
+				"resolve workspace": resolveErr,
+			} {
+				if err == nil {
+					t.Errorf("%s error = nil, want status %d", name, tt.status)
+					continue
+				}
+				if got := errors.Is(err, ErrPostmanUnauthorized); got != tt.want {
+					t.Errorf("%s error = %v, wraps ErrPostmanUnauthorized = %v, want %v", name, err, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncAllModules_StopsOnRejectedKey(t *testing.T) {
+	var fetches atomic.Int32
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Docs","version":"1"},"paths":{"/customers":{"get":{"operationId":"listCustomers"}}}}`))
+	}))
+	defer docServer.Close()
+	postman := httptest.NewServer(&mockPostman{collections: `{"collections":[]}`})
+	defer postman.Close()
+
+	client := NewAPIClient("doc-key", "revoked-key", WithOutput(io.Discard))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	config := &ModuleConfig{
+		Modules:     map[string]string{"Brands": "Brands Module API", "Customers": "Customers Module API", "Orders": "Orders Module API"},
+		Concurrency: 1,
+	}
+	orchestrator := NewSyncOrchestrator(client, config)
+	orchestrator.SetLogger(NewLogger(io.Discard, slog.LevelInfo))
+
+	results, err := orchestrator.SyncAllModules(t.Context(), "workspace")
+	if !errors.Is(err, ErrPostmanUnauthorized) {
+		t.Errorf("SyncAllModules() error = %v, want ErrPostmanUnauthorized", err)
+	}
+
+	want := map[string]ModuleStatus{"Brands": StatusFailed, "Customers": StatusSkipped, "Orders": StatusSkipped}
+	for _, result := range results {
+		if result.Status != want[result.Module] {
+			t.Errorf("%s status = %s (%v), want %s", result.Module, result.Status, result.Err, want[result.Module])
+		}
+		if !errors.Is(result.Err, ErrPostmanUnauthorized) {
+			t.Errorf("%s error = %v, want ErrPostmanUnauthorized", result.Module, result.Err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetched %d docs, want only the first module's", got)
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// WithUpsert makes the client update the collection recorded in its state in
+// place instead of listing, deleting and re-importing. Modules without a
+// recorded collection are imported as usual.
+func WithUpsert(enabled bool) ClientOption {
+	return func(c *APIClient) {
+		c.upsert = enabled
+	}
+}
+
+// knownCollection returns the uid of the module's collection when the id map
+// has one, or when upserting and the state recorded one.
+func (c *APIClient) knownCollection(module string) (string, bool) {
+	if c.idMap != nil {
+		return c.idMap.CollectionID(module)
+	}
+	if !c.upsert || c.state == nil {
+		return "", false
+	}
+	return c.state.CollectionID(module)
+}
+
+// updateModule replaces the content of the prepared target collection with
+// the converted spec, keeping the collection's uid.
+func (c *APIClient) updateModule(ctx context.Context, prepared *PreparedModule) error {
+	if c.dryRun {
+		c.log.InfoContext(ctx, "dry run: would update collection in place", "collection", prepared.Target.UpdateKey(), "name", prepared.CollectionName)
+		return nil
+	}
+
+	collection, err := c.convertSpec(ctx, prepared)
+	if err != nil {
+		c.log.ErrorContext(ctx, "converting spec failed", "error", err)
+		return err
+	}
+	info, _ := collection["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+		collection["info"] = info
+	}
+	info["name"] = prepared.CollectionName
+	if c.branding != nil {
+		description, err := specDescription(prepared.Doc)
+		if err != nil {
+			return err
+		}
+		info["description"] = c.branding.describe(description)
+	}
+
+	c.log.InfoContext(ctx, "updating collection in place", "collection", prepared.Target.UpdateKey())
+	if err := c.updateCollection(ctx, *prepared.Target, collection); err != nil {
+		if !errors.Is(err, errCollectionGone) {
+			c.log.ErrorContext(ctx, "postman update failed", "error", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// convertSpec returns the prepared spec as Postman converts it, so in-place
+// updates carry the same bodies, parameters, auth and examples as an import.
+// Postman only converts specs on import, so the spec is imported into a
+// temporary collection, which is read back and deleted. With a state the
+// temporary collection is recorded until it is deleted, and one an earlier
+// run left behind is deleted first.
+func (c *APIClient) convertSpec(ctx context.Context, prepared *PreparedModule) (map[string]any, error) {
+	if c.state != nil {
+		for _, id := range c.state.ConversionsOf(prepared.ModuleName) {
+			c.removeConversion(ctx, prepared.ModuleName, CollectionRef{ID: id})
+		}
+	}
+
+	imported, _, err := c.importToPostman(ctx, prepared.Doc, prepared.CollectionName, prepared.WorkspaceID, c.importOptions[prepared.ModuleName])
+	if err != nil {
+		return nil, err
+	}
+	if c.state != nil {
+		for _, ref := range imported {
+			c.state.AddConversion(prepared.ModuleName, ref.DeleteKey())
+		}
+	}
+	defer func() {
+		for _, ref := range imported {
+			c.removeConversion(ctx, prepared.ModuleName, ref)
+		}
+	}()
+
+	body, err := c.getCollection(ctx, imported[0])
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Collection map[string]any `json:"collection"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if result.Collection == nil {
+		return nil, fmt.Errorf("collection response has no collection: %s", string(body))
+	}
+
+	stripPostmanIDs(result.Collection)
+	return result.Collection, nil
+}
+
+// removeConversion deletes a temporary collection of the module and forgets
+// it in the state once it is gone. One that cannot be deleted stays recorded
+// for the next run.
+func (c *APIClient) removeConversion(ctx context.Context, module string, ref CollectionRef) {
+	err := c.removeCollection(ctx, ref)
+	if err != nil && !errors.Is(err, errCollectionGone) {
+		c.log.WarnContext(ctx, "deleting temporary collection failed", "collection", ref.DeleteKey(), "error", err)
+		return
+	}
+	if c.state != nil {
+		c.state.RemoveConversion(module, ref.DeleteKey())
+	}
+}
+
+// postmanIDKeys are the keys Postman assigns to a collection and its items.
+// They belong to the temporary collection, not to the one being updated.
+var postmanIDKeys = []string{"id", "uid", "_postman_id", "_exporter_id"}
+
+// stripPostmanIDs removes the Postman-assigned ids from a collection, at any
+// depth.
+func stripPostmanIDs(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range postmanIDKeys {
+			delete(v, key)
+		}
+		for _, child := range v {
+			stripPostmanIDs(child)
+		}
+	case []any:
+		for _, child := range v {
+			stripPostmanIDs(child)
+		}
+	}
+}
+
+// httpMethods are the OpenAPI path item keys that hold operations.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
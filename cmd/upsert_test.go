@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const upsertSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Customers", "version": "1", "description": "Customer operations"},
+	"servers": [{"url": "https://api.example.com/v1"}],
+	"paths": {
+		"/customers/{id}": {
+			"get": {"summary": "Get customer", "tags": ["customers"], "parameters": [{"name": "expand", "in": "query", "schema": {"type": "string"}}]},
+			"put": {"summary": "Update customer", "tags": ["customers"], "requestBody": {"content": {"application/json": {"example": {"name": "Ada"}}}}}
+		},
+		"/health": {"get": {}}
+	}
+}`
+
+// convertedCollection is the collection Postman creates when importing
+// upsertSpec, with the ids it assigns.
+const convertedCollection = `{"collection": {
+	"info": {"_postman_id": "c2", "name": "Customers", "description": "Customer operations", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	"item": [{
+		"id": "f1", "name": "customers",
+		"item": [
+			{"id": "r1", "uid": "1-r1", "name": "Get customer", "request": {"method": "GET", "url": {"raw": "{{baseUrl}}/customers/:id?expand=<string>", "query": [{"key": "expand", "value": "<string>"}], "variable": [{"key": "id", "value": "<string>"}]}}},
+			{"id": "r2", "uid": "1-r2", "name": "Update customer", "request": {"method": "PUT", "body": {"mode": "raw", "raw": "{\"name\": \"Ada\"}"}, "url": {"raw": "{{baseUrl}}/customers/:id"}}}
+		]
+	}, {"id": "r3", "uid": "1-r3", "name": "health", "request": {"method": "GET", "url": {"raw": "{{baseUrl}}/health"}}}],
+	"variable": [{"id": "v1", "key": "baseUrl", "value": "https://api.example.com/v1"}]
+}}`
+
+// upsertServers starts a doc server returning upsertSpec and a Postman server
+// recording the method and path of every request. Imports create the
+// collection 1-c2, which reads back as convertedCollection.
+func upsertServers(t *testing.T) (docURL string, postmanURL string, requests *[]string, puts *[]string) {
+	t.Helper()
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	t.Cleanup(docServer.Close)
+
+	var reqs, bodies []string
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs = append(reqs, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections":
+			w.Write([]byte(`{"collections":[{"id":"c1","uid":"1-c1","name":"Customers Module API"}]}`))
+		case r.Method == "GET":
+			w.Write([]byte(convertedCollection))
+		case r.Method == "POST":
+			w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2","name":"Customers"}]}`))
+		case r.Method == "PUT":
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(postman.Close)
+
+	return docServer.URL, postman.URL, &reqs, &bodies
+}
+
+func TestAPIClient_UpsertKnownCollection(t *testing.T) {
+	docURL, postmanURL, requests, puts := upsertServers(t)
+
+	state := &State{}
+	state.SetCollectionID("Customers", "1-c9")
+	client := NewAPIClient("doc-key", "pm-key", WithUpsert(true), WithState(state), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	want := []string{"POST /import/openapi", "GET /collections/1-c2", "DELETE /collections/c2", "PUT /collections/1-c9"}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("Postman requests = %v, want %v", *requests, want)
+	}
+	if len(*puts) != 1 {
+		t.Fatalf("got %d update bodies, want 1", len(*puts))
+	}
+
+	// The update carries the imported collection, bodies and parameters
+	// included, under the configured name and without the ids of the
+	// temporary collection.
+	var imported, updated map[string]any
+	if err := json.Unmarshal([]byte(convertedCollection), &imported); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte((*puts)[0]), &updated); err != nil {
+		t.Fatalf("parsing update body: %v", err)
+	}
+	stripPostmanIDs(imported)
+	imported["collection"].(map[string]any)["info"].(map[string]any)["name"] = "Customers Module API"
+	if !reflect.DeepEqual(updated, imported) {
+		t.Errorf("updated collection = %v\nwant the imported one %v", updated, imported)
+	}
+	if strings.Contains((*puts)[0], `"r1"`) || strings.Contains((*puts)[0], "_postman_id") {
+		t.Errorf("update body keeps the ids of the temporary collection: %s", (*puts)[0])
+	}
+}
+
+func TestAPIClient_UpsertFallsBackWithoutIdentifier(t *testing.T) {
+	docURL, postmanURL, requests, _ := upsertServers(t)
+
+	state := &State{}
+	client := NewAPIClient("doc-key", "pm-key", WithUpsert(true), WithState(state), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	want := []string{"GET /collections", "DELETE /collections/c1", "POST /import/openapi", "PATCH /collections/1-c2"}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("Postman requests = %v, want %v", *requests, want)
+	}
+	if id, _ := state.CollectionID("Customers"); id != "1-c2" {
+		t.Errorf("recorded collection = %q, want the imported 1-c2", id)
+	}
+}
+
+func TestAPIClient_UpsertDeletesLeftoverConversions(t *testing.T) {
+	docURL, postmanURL, requests, _ := upsertServers(t)
+
+	state := &State{}
+	state.SetCollectionID("Customers", "1-c9")
+	state.AddConversion("Customers", "c7")
+	client := NewAPIClient("doc-key", "pm-key", WithUpsert(true), WithState(state), WithOutput(io.Discard))
+	client.postmanBaseURL = postmanURL
+	client.docURL = func(string) string { return docURL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+
+	want := []string{"DELETE /collections/c7", "POST /import/openapi", "GET /collections/1-c2", "DELETE /collections/c2", "PUT /collections/1-c9"}
+	if !reflect.DeepEqual(*requests, want) {
+		t.Errorf("Postman requests = %v, want %v", *requests, want)
+	}
+	if left := state.ConversionsOf("Customers"); len(left) != 0 {
+		t.Errorf("temporary collections still recorded: %v", left)
+	}
+}
+
+func TestAPIClient_UpsertRecordsUndeletedConversion(t *testing.T) {
+	docServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(upsertSpec))
+	}))
+	defer docServer.Close()
+	postman := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"collections":[{"id":"c2","uid":"1-c2","name":"Customers"}]}`))
+		case "GET":
+			w.Write([]byte(convertedCollection))
+		case "DELETE":
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer postman.Close()
+
+	state := &State{}
+	state.SetCollectionID("Customers", "1-c9")
+	client := NewAPIClient("doc-key", "pm-key", WithUpsert(true), WithState(state), WithOutput(io.Discard), WithRetry(0, 0))
+	client.postmanBaseURL = postman.URL
+	client.docURL = func(string) string { return docServer.URL }
+
+	if err := client.ProcessModule(t.Context(), "Customers", "Customers Module API", "workspace"); err != nil {
+		t.Fatalf("ProcessModule() error = %v", err)
+	}
+	if left := state.ConversionsOf("Customers"); !reflect.DeepEqual(left, []string{"c2"}) {
+		t.Errorf("recorded temporary collections = %v, want the undeleted c2", left)
+	}
+}
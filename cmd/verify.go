@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Modes of the -verify-import check.
+const (
+	// VerifyWarn reports an imported collection that does not match its
+	// spec and keeps it.
+	VerifyWarn = "warn"
+	// VerifyFail fails the module when the collection does not match.
+	VerifyFail = "fail"
+)
+
+var validVerifyModes = []string{VerifyWarn, VerifyFail}
+
+// WithVerifyImport makes the client fetch every collection it imports and
+// compare its requests with the operations of the spec, in one of
+// validVerifyModes. An empty mode skips the check.
+func WithVerifyImport(mode string) ClientOption {
+	return func(c *APIClient) {
+		c.verifyImport = mode
+	}
+}
+
+// specSignature returns the operations of a spec as sorted "METHOD /path"
+// lines, with every path parameter written as {}.
+func specSignature(doc string) ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+
+	var lines []string
+	for path, item := range spec.Paths {
+		for _, method := range httpMethods {
+			if _, ok := item[method]; ok {
+				lines = append(lines, signatureLine(method, strings.Split(path, "/")))
+			}
+		}
+	}
+	slices.Sort(lines)
+	return lines, nil
+}
+
+// postmanItem is a request or a folder of a Postman collection.
+type postmanItem struct {
+	Item    []postmanItem `json:"item"`
+	Request *struct {
+		Method string          `json:"method"`
+		URL    json.RawMessage `json:"url"`
+	} `json:"request"`
+}
+
+// collectionSignature returns the requests of a single-collection response
+// in the form of specSignature, descending into folders.
+func collectionSignature(body []byte) ([]string, error) {
+	var result struct {
+		Collection struct {
+			Item []postmanItem `json:"item"`
+		} `json:"collection"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing collection: %w", err)
+	}
+
+	var lines []string
+	var walk func(items []postmanItem)
+	walk = func(items []postmanItem) {
+		for _, item := range items {
+			if item.Request != nil {
+				lines = append(lines, signatureLine(item.Request.Method, requestPath(item.Request.URL)))
+			}
+			walk(item.Item)
+		}
+	}
+	walk(result.Collection.Item)
+
+	slices.Sort(lines)
+	return lines, nil
+}
+
+// requestPath returns the path segments of a request URL, which Postman
+// writes either as an object with a path array or as the raw URL.
+func requestPath(raw json.RawMessage) []string {
+	var object struct {
+		Path []string `json:"path"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		return object.Path
+	}
+
+	var rawURL string
+	if json.Unmarshal(raw, &rawURL) != nil {
+		return nil
+	}
+	// Drop the {{baseUrl}} variable or the host, and the query.
+	rawURL, _, _ = strings.Cut(rawURL, "?")
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		rawURL = u.Path
+	} else if _, rest, ok := strings.Cut(rawURL, "}}"); ok {
+		rawURL = rest
+	}
+	return strings.Split(rawURL, "/")
+}
+
+// signatureLine writes a request as "METHOD /segment/{}", replacing the
+// {name} and :name path parameters so their spelling does not matter.
+func signatureLine(method string, segments []string) string {
+	normalized := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) {
+			segment = "{}"
+		}
+		normalized = append(normalized, segment)
+	}
+	return strings.ToUpper(method) + " /" + strings.Join(normalized, "/")
+}
+
+// signatureChecksum returns the SHA-256 of a sorted signature.
+func signatureChecksum(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCollection fetches an imported collection and compares the checksum
+// of its requests with that of the spec's operations. A mismatch is logged
+// with the requests missing from and added to the collection, and fails the
+// module in VerifyFail mode.
+func (c *APIClient) verifyCollection(ctx context.Context, ref CollectionRef, doc string) error {
+	want, err := specSignature(doc)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.getCollection(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("fetching imported collection: %w", err)
+	}
+	got, err := collectionSignature(body)
+	if err != nil {
+		return err
+	}
+
+	wantSum, gotSum := signatureChecksum(want), signatureChecksum(got)
+	if wantSum == gotSum {
+		c.log.InfoContext(ctx, "verified imported collection", "collection", ref.UpdateKey(), "requests", len(got), "checksum", gotSum[:12])
+		return nil
+	}
+
+	missing := missingLines(want, got)
+	extra := missingLines(got, want)
+	if c.verifyImport == VerifyWarn {
+		c.log.WarnContext(ctx, "imported collection does not match its spec", "collection", ref.UpdateKey(),
+			"operations", len(want), "requests", len(got), "missing", strings.Join(missing, ", "), "extra", strings.Join(extra, ", "))
+		return nil
+	}
+	return fmt.Errorf("collection %s does not match its spec: %d operations, %d requests, missing [%s], extra [%s]",
+		ref.UpdateKey(), len(want), len(got), strings.Join(missing, ", "), strings.Join(extra, ", "))
+}
+
+// missingLines returns the lines of a that are not in b, counting repeats;
+// both must be sorted.
+func missingLines(a, b []string) []string {
+	var diff []string
+	for _, line := range a {
+		if i, found := slices.BinarySearch(b, line); found {
+			b = slices.Delete(slices.Clone(b), i, i+1)
+			continue
+		}
+		diff = append(diff, line)
+	}
+	return diff
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const verifySpec = `{"openapi":"3.0.0","paths":{
+  "/customers":{"get":{},"post":{}},
+  "/customers/{id}":{"get":{},"delete":{}}
+}}`
+
+// matchingCollection holds the requests Postman creates for verifySpec, in
+// folders and with both URL forms.
+const matchingCollection = `{"collection":{"item":[
+  {"name":"customers","item":[
+    {"request":{"method":"GET","url":{"raw":"{{baseUrl}}/customers","path":["customers"]}}},
+    {"request":{"method":"POST","url":"{{baseUrl}}/customers?dryRun=true"}},
+    {"name":"{id}","item":[
+      {"request":{"method":"GET","url":{"path":["customers",":id"]}}},
+      {"request":{"method":"DELETE","url":"https://api.example.com/customers/:customerId"}}
+    ]}
+  ]}
+]}}`
+
+// divergingCollection lost the delete and gained a request.
+const divergingCollection = `{"collection":{"item":[
+  {"request":{"method":"GET","url":{"path":["customers"]}}},
+  {"request":{"method":"POST","url":{"path":["customers"]}}},
+  {"request":{"method":"GET","url":{"path":["customers",":id"]}}},
+  {"request":{"method":"PUT","url":{"path":["customers",":id"]}}}
+]}}`
+
+func TestSignatures(t *testing.T) {
+	want := []string{"DELETE /customers/{}", "GET /customers", "GET /customers/{}", "POST /customers"}
+
+	spec, err := specSignature(verifySpec)
+	if err != nil || !reflect.DeepEqual(spec, want) {
+		t.Errorf("specSignature() = %v, %v, want %v", spec, err, want)
+	}
+
+	collection, err := collectionSignature([]byte(matchingCollection))
+	if err != nil || !reflect.DeepEqual(collection, want) {
+		t.Errorf("collectionSignature() = %v, %v, want %v", collection, err, want)
+	}
+}
+
+func verifyServer(t *testing.T, collection string) *APIClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST":
+			w.Write([]byte(`{"collections":[{"id":"new","uid":"1-new","name":"Customers Module API"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/collections/1-new":
+			w.Write([]byte(collection))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewAPIClient("doc-key", "pm-key", WithOutput(&strings.Builder{}))
+	client.postmanBaseURL = server.URL
+	return client
+}
+
+func TestAPIClient_ImportModuleVerify(t *testing.T) {
+	prepared := &PreparedModule{
+		ModuleName:     "Customers",
+		CollectionName: "Customers Module API",
+		WorkspaceID:    "workspace",
+		Doc:            verifySpec,
+	}
+
+	t.Run("matching collection", func(t *testing.T) {
+		client := verifyServer(t, matchingCollection)
+		client.verifyImport = VerifyFail
+		if err := client.ImportModule(t.Context(), prepared); err != nil {
+			t.Errorf("ImportModule() error = %v", err)
+		}
+	})
+
+	t.Run("diverging collection fails", func(t *testing.T) {
+		client := verifyServer(t, divergingCollection)
+		client.verifyImport = VerifyFail
+
+		err := client.ImportModule(t.Context(), prepared)
+		if err == nil {
+			t.Fatal("ImportModule() error = nil, want a mismatch")
+		}
+		for _, want := range []string{"does not match its spec", "missing [DELETE /customers/{}]", "extra [PUT /customers/{}]"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error = %v, want it to contain %q", err, want)
+			}
+		}
+	})
+
+	t.Run("diverging collection warns", func(t *testing.T) {
+		var out strings.Builder
+		client := verifyServer(t, divergingCollection)
+		client.verifyImport = VerifyWarn
+		client.log = NewLogger(&out, slog.LevelInfo)
+
+		if err := client.ImportModule(t.Context(), prepared); err != nil {
+			t.Fatalf("ImportModule() error = %v", err)
+		}
+		if !strings.Contains(out.String(), `level=WARN msg="imported collection does not match its spec"`) {
+			t.Errorf("output should warn about the mismatch:\n%s", out.String())
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+var validWorkspaceTypes = []string{"personal", "team"}
+
+// minWorkspaceIDLength is far below the 36 characters of the UUIDs Postman
+// uses, so only IDs that are obviously cut off are rejected.
+const minWorkspaceIDLength = 8
+
+// validateWorkspaceID sanity-checks the workspace ID of flag, so a typo
+// fails before any request instead of with an opaque Postman error. It does
+// not insist on a UUID, only on the letters, digits, dashes and underscores
+// IDs are made of.
+func validateWorkspaceID(flag, id string) error {
+	var problem string
+	switch {
+	case strings.Contains(id, "://"):
+		problem = "looks like a URL, give only the workspace ID"
+	case strings.ContainsFunc(id, unicode.IsSpace):
+		problem = "contains whitespace"
+	case strings.ContainsFunc(id, func(r rune) bool {
+		return !(r == '-' || r == '_' || r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	}):
+		problem = "contains characters other than letters, digits, dashes and underscores"
+	case len(id) < minWorkspaceIDLength:
+		problem = "is too short"
+	default:
+		return nil
+	}
+	return fmt.Errorf("invalid %s %q: %s; Postman workspace IDs look like 1f0df51a-8658-4ee8-a2a1-d2567dfa09a9", flag, id, problem)
+}
+
+// Workspace describes a Postman workspace.
+type Workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (c *APIClient) getWorkspace(ctx context.Context, workspaceID string) (Workspace, error) {
+	url := fmt.Sprintf("%s/workspaces/%s", c.postmanBaseURL, workspaceID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return Workspace{}, postmanStatusError(resp.StatusCode, fmt.Errorf("failed to get workspace: %d %s", resp.StatusCode, string(body)))
+	}
+
+	var result struct {
+		Workspace Workspace `json:"workspace"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return Workspace{}, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return result.Workspace, nil
+}
+
+// ResolveWorkspaceID returns the ID of the workspace with the given name,
+// among those the Postman API key can see. It fails unless exactly one
+// workspace has the name.
+func (c *APIClient) ResolveWorkspaceID(ctx context.Context, name string) (string, error) {
+	url := fmt.Sprintf("%s/workspaces", c.postmanBaseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.doPostman(req)
+	if err != nil {
+		return "", fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", postmanStatusError(resp.StatusCode, fmt.Errorf("failed to list workspaces: %d %s", resp.StatusCode, string(body)))
+	}
+
+	var result struct {
+		Workspaces []Workspace `json:"workspaces"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	var ids []string
+	for _, workspace := range result.Workspaces {
+		if workspace.Name == name {
+			ids = append(ids, workspace.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no workspace named %q", name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d workspaces named %q: %s; give the ID instead", len(ids), name, strings.Join(ids, ", "))
+	}
+}
+
+// CheckWorkspaceType writes a warning to w when the workspace is not of the
+// expected type (personal or team), which usually means the API key belongs
+// to a different account context than intended.
+func (c *APIClient) CheckWorkspaceType(ctx context.Context, workspaceID, expected string, w io.Writer) error {
+	workspace, err := c.getWorkspace(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if workspace.Type != expected {
+		fmt.Fprintf(w, "Warning: workspace %s (%s) is a %s workspace, expected %s\n",
+			workspace.ID, workspace.Name, workspace.Type, expected)
+	}
+
+	return nil
+}
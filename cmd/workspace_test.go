@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIClient_CheckWorkspaceType(t *testing.T) {
+	tests := []struct {
+		name        string
+		actual      string
+		expected    string
+		wantWarning bool
+	}{
+		{name: "team workspace when personal expected", actual: "team", expected: "personal", wantWarning: true},
+		{name: "matching type", actual: "team", expected: "team", wantWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/workspaces/ws-1" {
+					t.Errorf("path = %q, want /workspaces/ws-1", r.URL.Path)
+				}
+				w.Write([]byte(`{"workspace":{"id":"ws-1","name":"APIs","type":"` + tt.actual + `"}}`))
+			}))
+			defer server.Close()
+
+			client := NewAPIClient("doc-key", "pm-key")
+			client.postmanBaseURL = server.URL
+
+			var out strings.Builder
+			if err := client.CheckWorkspaceType(t.Context(), "ws-1", tt.expected, &out); err != nil {
+				t.Fatalf("CheckWorkspaceType() error = %v", err)
+			}
+
+			gotWarning := strings.Contains(out.String(), "Warning: workspace ws-1 (APIs) is a team workspace, expected personal")
+			if gotWarning != tt.wantWarning {
+				t.Errorf("warning output = %q, wantWarning %v", out.String(), tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestAPIClient_CheckWorkspaceType_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"name":"instanceNotFoundError"}}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	var out strings.Builder
+	if err := client.CheckWorkspaceType(t.Context(), "missing", "team", &out); err == nil {
+		t.Error("CheckWorkspaceType() error = nil, want error for unknown workspace")
+	}
+}
+
+func TestAPIClient_ResolveWorkspaceID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/workspaces" {
+			t.Errorf("path = %q, want /workspaces", r.URL.Path)
+		}
+		w.Write([]byte(`{"workspaces":[
+			{"id":"ws-1","name":"Internal APIs","type":"team"},
+			{"id":"ws-2","name":"Sandbox","type":"personal"},
+			{"id":"ws-3","name":"Sandbox","type":"team"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient("doc-key", "pm-key")
+	client.postmanBaseURL = server.URL
+
+	tests := []struct {
+		name        string
+		workspace   string
+		want        string
+		errContains string
+	}{
+		{name: "unique name", workspace: "Internal APIs", want: "ws-1"},
+		{name: "unknown name", workspace: "Public APIs", errContains: `no workspace named "Public APIs"`},
+		{name: "ambiguous name", workspace: "Sandbox", errContains: `2 workspaces named "Sandbox": ws-2, ws-3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.ResolveWorkspaceID(t.Context(), tt.workspace)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ResolveWorkspaceID() error = %v, want it to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveWorkspaceID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveWorkspaceID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWorkspaceID(t *testing.T) {
+	tests := []struct {
+		id          string
+		errContains string
+	}{
+		{id: "1f0df51a-8658-4ee8-a2a1-d2567dfa09a9"},
+		{id: "workspace_1234"},
+		{id: "https://web.postman.co/workspace/1f0df51a", errContains: "looks like a URL"},
+		{id: "1f0df51a 8658", errContains: "whitespace"},
+		{id: "1f0df51a-8658\n", errContains: "whitespace"},
+		{id: "1f0df51a-8658/4ee8", errContains: "characters other than"},
+		{id: "1f0d", errContains: "too short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			err := validateWorkspaceID("pm-workspace-id", tt.id)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("validateWorkspaceID() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateWorkspaceID() error = %v, want it to mention %q", err, tt.errContains)
+			}
+		})
+	}
+}
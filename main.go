@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"apisync.daniel.guo.com/cmd"
 )
@@ -14,13 +20,374 @@ func main() {
 		os.Exit(1)
 	}
 
-	client := cmd.NewAPIClient(params.DocAPIKey, params.PostmanAPIKey)
 	config := cmd.NewModuleConfig()
+	switch {
+	case params.Profile != "":
+		config, err = cmd.NewModuleConfigFromProfile(params.ConfigFile, params.Profile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	case params.ConfigFile != "":
+		config, err = cmd.NewModuleConfigFromFile(params.ConfigFile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+	if len(params.Modules) > 0 {
+		config, err = config.SelectModules(params.Modules)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+	config.DocURLTemplate = params.DocURLTemplate
+	config.BatchCleanup = params.BatchCleanup
+	config.Concurrency = params.Concurrency
+
+	var state *cmd.State
+	if params.StateFile != "" {
+		state, err = cmd.LoadState(params.StateFile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+
+	var idMap *cmd.IDMap
+	if params.IDMapFile != "" {
+		idMap, err = cmd.LoadIDMap(params.IDMapFile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+
+	if params.BatchSize > 0 {
+		config = config.SelectBatch(params.BatchSize, state)
+	}
+
+	out, err := cmd.NewRunOutput(params.StatusOutput, params.JSONOutput)
+	if err != nil {
+		fail(params.ExitReportFile, err)
+	}
+
+	level, _ := cmd.ParseLogLevel(params.LogLevel)
+	logger := cmd.NewLogger(out.Status, level)
+	if params.JSONLogs {
+		logger = cmd.NewJSONLogger(out.Status, level)
+	}
+
+	// The result tables are neither errors nor log records, so quiet and
+	// JSON logs leave them out. Quiet drops warnings too.
+	tables, warnings := out.Status, io.Writer(os.Stderr)
+	if params.Quiet || params.JSONLogs {
+		tables = io.Discard
+	}
+	if params.Quiet {
+		warnings = io.Discard
+	}
+
+	opts := []cmd.ClientOption{
+		cmd.WithTimeout(params.Timeout),
+		cmd.WithProxy(params.Proxy),
+		cmd.WithConcurrencyPerHost(params.ConcurrencyPerHost),
+		cmd.WithMaxConcurrentDeletes(params.MaxConcurrentDeletes),
+		cmd.WithRateLimit(params.RateLimit),
+		cmd.WithPostmanAPIVersion(params.PostmanAPIVersion),
+		cmd.WithPostmanBaseURL(params.PostmanBaseURL),
+		cmd.WithRetry(params.MaxRetries, params.RetryDelay),
+		cmd.WithImportRetries(params.ImportMaxRetries),
+		cmd.WithRetryBodyCodes(params.RetryBodyCodes...),
+		cmd.WithLogger(logger),
+		cmd.WithCollectionKeyField(params.CollectionKeyField),
+		cmd.WithGzipImport(params.GzipImport),
+		cmd.WithShare(params.Share),
+		cmd.WithDryRun(params.DryRun),
+		cmd.WithInjectSecurity(params.InjectSecurity, params.ForceSecurity),
+		cmd.WithMaskPatterns(params.MaskPatterns...),
+		cmd.WithCanonicalSpecs(params.Canonical),
+		cmd.WithUpsert(params.Upsert),
+		cmd.WithOutputDir(params.OutputDir),
+		cmd.WithMode(params.Mode),
+		cmd.WithForce(params.Force),
+		cmd.WithBreakingChanges(params.BlockBreaking, params.AllowBreaking),
+		cmd.WithDocURLTemplate(params.DocURLTemplate),
+		cmd.WithDocURLTemplates(config.DocURLs),
+		cmd.WithDocAuthType(params.DocAuthType),
+		cmd.WithDocHeaders(params.DocHeaders),
+		cmd.WithMinDocSize(params.MinDocSize),
+		cmd.WithCircuitBreaker(params.CircuitFailures, params.CircuitWindow),
+		cmd.WithImportOptions(config.ImportOptions),
+		cmd.WithCollectionUIDs(config.CollectionUIDs),
+		cmd.WithStrategy(params.Strategy),
+		cmd.WithOperationIDs(params.RequireOperationID, params.GenerateOperationID),
+		cmd.WithNormalize(!params.NoNormalize),
+		cmd.WithStrict(params.Strict),
+		cmd.WithFlagCheckURL(params.FlagCheckURL),
+		cmd.WithFailOnDuplicates(params.FailOnDuplicates),
+		cmd.WithVerifyImport(params.VerifyImport),
+	}
+	if state != nil {
+		opts = append(opts, cmd.WithState(state))
+	}
+	if idMap != nil {
+		opts = append(opts, cmd.WithIDMap(idMap))
+	}
+
+	if params.BrandingFile != "" {
+		branding, err := cmd.LoadBranding(params.BrandingFile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		opts = append(opts, cmd.WithBranding(branding))
+	}
+
+	var cassette *cmd.Cassette
+	switch {
+	case params.RecordFile != "":
+		cassette = cmd.NewRecorder(params.RecordFile)
+	case params.ReplayFile != "":
+		cassette, err = cmd.LoadCassette(params.ReplayFile)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+	if cassette != nil {
+		opts = append(opts, cmd.WithCassette(cassette))
+	}
+	if params.RecordFile != "" {
+		// Failed runs are the ones most worth replaying, so the recording is
+		// saved however the run ends.
+		onExit(func() {
+			if err := cassette.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		})
+		defer runExitHooks()
+	}
+
+	client := cmd.NewAPIClient(params.DocAPIKey, params.PostmanAPIKey, opts...)
+	defer client.Close()
+
+	// The script is written with the client's settings, so it matches the
+	// sync it stands for.
+	if params.EmitScript {
+		if err := client.WriteScript(os.Stdout, config, params.PostmanWorkspaceID); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if params.Check {
+		results := client.CheckCredentials(ctx, config)
+		if err := cmd.WriteCheckResults(os.Stdout, results); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		for _, result := range results {
+			if !result.OK {
+				exit(params.ExitReportFile, 1, fmt.Errorf("%s check failed", result.API), nil)
+			}
+		}
+		return
+	}
+
+	if params.PostmanWorkspaceID == "" && params.WorkspaceName != "" {
+		params.PostmanWorkspaceID, err = client.ResolveWorkspaceID(ctx, params.WorkspaceName)
+		if err != nil {
+			fail(params.ExitReportFile, fmt.Errorf("resolving workspace name: %w", err))
+		}
+	}
+
+	if params.Probe {
+		results := client.Probe(ctx, config, params.PostmanWorkspaceID)
+		if err := cmd.WriteProbeMatrix(os.Stdout, results); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		for _, result := range results {
+			if !result.Up {
+				exit(params.ExitReportFile, 1, fmt.Errorf("%s is unreachable", result.Endpoint), nil)
+			}
+		}
+		return
+	}
+
+	if params.CompareWorkspace != "" {
+		diffs, err := client.CompareWorkspaces(ctx, config, params.PostmanWorkspaceID, params.CompareWorkspace)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		if err := cmd.WriteWorkspaceDiff(os.Stdout, params.PostmanWorkspaceID, params.CompareWorkspace, diffs); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		for _, diff := range diffs {
+			if diff.Differs() {
+				exit(params.ExitReportFile, 1, errors.New("the workspaces differ"), nil)
+			}
+		}
+		return
+	}
+
+	if params.CheckEnv {
+		if err := client.CheckDocEnvironment(config, params.Env); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+
+	if params.OnlyIfEmpty {
+		if err := client.EnsureWorkspaceEmpty(ctx, config, params.PostmanWorkspaceID); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+
+	if params.MaxWorkspaceCollections > 0 {
+		err := client.EnsureCollectionCeiling(ctx, config, params.PostmanWorkspaceID, params.MaxWorkspaceCollections)
+		if err != nil && params.Force {
+			fmt.Fprintf(warnings, "Warning: %v\n", err)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (pass -force to continue anyway)\n", err)
+			exit(params.ExitReportFile, 1, err, nil)
+		}
+	}
+
+	if params.WorkspaceType != "" {
+		if err := client.CheckWorkspaceType(ctx, params.PostmanWorkspaceID, params.WorkspaceType, warnings); err != nil {
+			fail(params.ExitReportFile, err)
+		}
+	}
+
 	orchestrator := cmd.NewSyncOrchestrator(client, config)
+	orchestrator.SetLogger(logger)
+	orchestrator.SetShutdownGrace(params.ShutdownGrace)
+	orchestrator.SetFailFast(params.FailFast)
+	orchestrator.SetStartJitter(params.StartJitter)
+
+	if params.SummaryStreamFile != "" {
+		file, err := os.OpenFile(params.SummaryStreamFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			fail(params.ExitReportFile, err)
+		}
+		defer file.Close()
+		orchestrator.SetResultStream(cmd.NewResultStream(file))
+	}
+
+	started := time.Now()
+	results, syncErr := orchestrator.SyncAllModules(ctx, params.PostmanWorkspaceID)
+	if errors.Is(syncErr, cmd.ErrPostmanUnauthorized) {
+		// The other modules were stopped, as they would fail the same way.
+		fmt.Fprintln(os.Stderr, "Error: Postman rejected the API key; check your Postman API key (-pm-api-key or PM_API_KEY)")
+	} else if syncErr != nil {
+		fmt.Fprintf(os.Stderr, "Sync error: %v\n", syncErr)
+	}
+	if params.DiagnoseOnFailure && cmd.IsNetworkError(syncErr) {
+		fmt.Fprintln(os.Stderr, "Diagnosing connectivity:")
+		if err := cmd.WriteDiagnosis(os.Stderr, client.Diagnose(context.Background(), config)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	var previous map[string]cmd.ModuleStatus
+	if state != nil {
+		previous = state.RecordOutcomes(results)
+		if err := state.Save(params.StateFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if idMap != nil {
+		if err := idMap.Save(params.IDMapFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if params.ReportFile != "" {
+		report := cmd.NewReport(params.PostmanWorkspaceID, started, results, client.CollectionChanges())
+		if err := cmd.WriteReport(params.ReportFile, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if err := cmd.WriteResultTable(tables, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	transfers := client.Transfers()
+	if err := cmd.WriteTransferSummary(tables, transfers); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	metrics := client.SpecMetrics()
+	if err := cmd.WriteSpecMetrics(tables, metrics); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	status := cmd.NewRunStatus(params.PostmanWorkspaceID, syncErr).WithTransfers(transfers)
+	status.Modules = results
+	status.SpecMetrics = metrics
+	if err := out.WriteJSON(status); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	if params.NotifyURL != "" {
+		notification := cmd.Notification{
+			WorkspaceID: params.PostmanWorkspaceID,
+			Modules:     cmd.ModuleChanges(results, previous, params.NotifyOnChange),
+		}
+		if err := client.Notify(context.Background(), params.NotifyURL, notification); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if params.SlackWebhook != "" {
+		if err := client.NotifySlack(context.Background(), params.SlackWebhook, params.PostmanWorkspaceID, results); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	// Any failed module fails the run, so schedulers notice partial failures.
+	if syncErr != nil {
+		exit(params.ExitReportFile, 1, syncErr, results)
+	}
+	writeExitReport(params.ExitReportFile, 0, nil, results)
+}
 
-	if err := orchestrator.SyncAllModules(params.PostmanWorkspaceID); err != nil {
-		fmt.Fprintf(os.Stderr, "Sync error: %v\n", err)
+// fail reports err on stderr and exits with status 1.
+func fail(reportFile string, err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	exit(reportFile, 1, err, nil)
+}
+
+// exitHooks run once before the program ends, by returning from main or
+// through exit.
+var exitHooks []func()
+
+// onExit registers hook to run before the program ends.
+func onExit(hook func()) {
+	exitHooks = append(exitHooks, hook)
+}
+
+// runExitHooks runs the registered exit hooks, at most once each.
+func runExitHooks() {
+	hooks := exitHooks
+	exitHooks = nil
+	for _, hook := range hooks {
+		hook()
 	}
+}
 
-	fmt.Println("Successfully imported to Postman!")
+// exit runs the exit hooks, records the exit in the exit report, if one is
+// wanted, and exits.
+func exit(reportFile string, code int, err error, results []cmd.ModuleResult) {
+	runExitHooks()
+	writeExitReport(reportFile, code, err, results)
+	os.Exit(code)
+}
+
+// writeExitReport writes the exit report to reportFile unless it is empty.
+func writeExitReport(reportFile string, code int, err error, results []cmd.ModuleResult) {
+	if reportFile == "" {
+		return
+	}
+	if err := cmd.WriteExitReport(reportFile, cmd.NewExitReport(code, err, results)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
 }
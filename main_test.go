@@ -1,8 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -93,6 +97,110 @@ func TestMainFunction(t *testing.T) {
 	}
 }
 
+// TestMainExitReport runs the binary against a local doc server and checks the
+// exit report written on success and on failure.
+func TestMainExitReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		wantStatus string
+		wantExit   int
+	}{
+		{name: "success", doc: `{"openapi":"3.0.0","info":{"title":"Customers"},"paths":{"/customers":{"get":{}}}}`, wantStatus: "success"},
+		{name: "failure", doc: `{"openapi":"3.0.0","paths":{}}`, wantStatus: "failed", wantExit: 1},
+	}
+
+	build := exec.Command("go", "build", "-o", "test-binary", ".")
+	if err := build.Run(); err != nil {
+		t.Fatalf("Failed to build binary: %v", err)
+	}
+	defer os.Remove("test-binary")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.doc))
+			}))
+			defer server.Close()
+
+			reportFile := filepath.Join(t.TempDir(), "exit.json")
+			run := exec.Command("./test-binary",
+				"-doc-api-key=test",
+				"-mode=validate",
+				"-modules=Customers",
+				"-doc-url-template="+server.URL+"/%s",
+				"-report-file="+reportFile,
+			)
+			output, err := run.CombinedOutput()
+			exitCode := 0
+			if exitError, ok := err.(*exec.ExitError); ok {
+				exitCode = exitError.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Failed to run command: %v", err)
+			}
+
+			data, err := os.ReadFile(reportFile)
+			if err != nil {
+				t.Fatalf("reading exit report: %v\n%s", err, output)
+			}
+			var report cmd.ExitReport
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatalf("exit report is not JSON: %v\n%s", err, data)
+			}
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d\n%s", exitCode, tt.wantExit, output)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if report.ExitCode != exitCode {
+				t.Errorf("exit code in report = %d, process exited with %d", report.ExitCode, exitCode)
+			}
+			if len(report.Modules) != 1 {
+				t.Errorf("got %d modules in the report, want 1", len(report.Modules))
+			}
+		})
+	}
+}
+
+// TestMainSavesRecordingOnFailure checks that a run that exits early with a
+// failure still saves what it recorded.
+func TestMainSavesRecordingOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	build := exec.Command("go", "build", "-o", "test-binary", ".")
+	if err := build.Run(); err != nil {
+		t.Fatalf("Failed to build binary: %v", err)
+	}
+	defer os.Remove("test-binary")
+
+	cassetteFile := filepath.Join(t.TempDir(), "cassette.json")
+	run := exec.Command("./test-binary",
+		"-check",
+		"-doc-api-key=test",
+		"-pm-api-key=test",
+		"-modules=Customers",
+		"-doc-url-template="+server.URL+"/%s",
+		"-postman-base-url="+server.URL,
+		"-record="+cassetteFile,
+	)
+	output, err := run.CombinedOutput()
+	if exitError, ok := err.(*exec.ExitError); !ok || exitError.ExitCode() != 1 {
+		t.Fatalf("got %v, want exit status 1\n%s", err, output)
+	}
+
+	cassette, err := cmd.LoadCassette(cassetteFile)
+	if err != nil {
+		t.Fatalf("loading the recording: %v\n%s", err, output)
+	}
+	if len(cassette.Interactions) == 0 {
+		t.Error("the recording has no interactions")
+	}
+}
+
 // TestMainIntegration tests the main function with valid parameters
 // This test would require actual API keys to run successfully
 func TestMainIntegration(t *testing.T) {